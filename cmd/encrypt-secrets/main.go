@@ -0,0 +1,71 @@
+/*
+encrypt-secrets encrypts the GatewayAAT private keys, and the secret keys not yet hashed, stored in clear text,
+and re-encrypts values wrapped with an old key under the current key of the local key provider.
+
+Usage:
+
+	encrypt-secrets -connection-string postgres://... [-keys-file keys.json]
+
+Keys are read from -keys-file, or from the PORTAL_DB_ENCRYPTION_KEYS environment variable if no file is given,
+in the form {"currentKeyID": "key-2", "keys": {"key-1": "<base64 key>", "key-2": "<base64 key>"}}.
+To rotate, add the new key, point currentKeyID at it and run the command again; old keys can be removed afterwards.
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vishruthsk/portal-db-main/encryption"
+	postgresdriver "github.com/vishruthsk/portal-db-main/postgres-driver"
+)
+
+const keysEnvVar = "PORTAL_DB_ENCRYPTION_KEYS"
+
+func main() {
+	connectionString := flag.String("connection-string", os.Getenv("PORTAL_DB_CONNECTION_STRING"), "Postgres connection string")
+	keysFile := flag.String("keys-file", "", "path to the keys JSON file, defaults to the "+keysEnvVar+" environment variable")
+	timeout := flag.Duration("timeout", 10*time.Minute, "statement timeout for the migration")
+	flag.Parse()
+
+	if *connectionString == "" {
+		log.Fatal("error: -connection-string is required")
+	}
+
+	provider, err := loadKeyProvider(*keysFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	listener := pq.NewListener(*connectionString, 10*time.Second, time.Minute, nil)
+	driver, err := postgresdriver.NewPostgresDriver(*connectionString, listener)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	driver.SetKeyProvider(provider)
+	driver.SetTimeouts(postgresdriver.Timeouts{
+		Read:  postgresdriver.DefaultTimeouts.Read,
+		Write: postgresdriver.OperationTimeouts{Statement: *timeout, Lock: postgresdriver.DefaultTimeouts.Write.Lock},
+	})
+
+	rewritten, err := driver.ReencryptSecrets(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("encrypted %d secrets with key %s\n", rewritten, provider.CurrentKeyID())
+}
+
+func loadKeyProvider(keysFile string) (*encryption.LocalKeyProvider, error) {
+	if keysFile != "" {
+		return encryption.NewLocalKeyProviderFromFile(keysFile)
+	}
+
+	return encryption.NewLocalKeyProviderFromEnv(keysEnvVar)
+}
@@ -0,0 +1,72 @@
+/*
+hash-secret-keys is a one-off migration that replaces the gateway settings secret keys and named secret keys
+stored in clear text or encrypted with their salted hashes.
+
+Usage:
+
+	hash-secret-keys -connection-string postgres://... [-keys-file keys.json]
+
+Keys are only needed if secret keys were encrypted with encrypt-secrets, they are read from -keys-file, or from the
+PORTAL_DB_ENCRYPTION_KEYS environment variable if it is set. Running the command again is a no-op.
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vishruthsk/portal-db-main/encryption"
+	postgresdriver "github.com/vishruthsk/portal-db-main/postgres-driver"
+)
+
+const keysEnvVar = "PORTAL_DB_ENCRYPTION_KEYS"
+
+func main() {
+	connectionString := flag.String("connection-string", os.Getenv("PORTAL_DB_CONNECTION_STRING"), "Postgres connection string")
+	keysFile := flag.String("keys-file", "", "path to the keys JSON file of encrypted secret keys, defaults to the "+keysEnvVar+" environment variable")
+	timeout := flag.Duration("timeout", 10*time.Minute, "statement timeout for the migration")
+	flag.Parse()
+
+	if *connectionString == "" {
+		log.Fatal("error: -connection-string is required")
+	}
+
+	listener := pq.NewListener(*connectionString, 10*time.Second, time.Minute, nil)
+	driver, err := postgresdriver.NewPostgresDriver(*connectionString, listener)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *keysFile != "" || os.Getenv(keysEnvVar) != "" {
+		provider, err := loadKeyProvider(*keysFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		driver.SetKeyProvider(provider)
+	}
+
+	driver.SetTimeouts(postgresdriver.Timeouts{
+		Read:  postgresdriver.DefaultTimeouts.Read,
+		Write: postgresdriver.OperationTimeouts{Statement: *timeout, Lock: postgresdriver.DefaultTimeouts.Write.Lock},
+	})
+
+	rewritten, err := driver.HashSecretKeys(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("hashed %d secret keys\n", rewritten)
+}
+
+func loadKeyProvider(keysFile string) (*encryption.LocalKeyProvider, error) {
+	if keysFile != "" {
+		return encryption.NewLocalKeyProviderFromFile(keysFile)
+	}
+
+	return encryption.NewLocalKeyProviderFromEnv(keysEnvVar)
+}
@@ -2,15 +2,29 @@ package driver
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/vishruthsk/portal-db-main/types"
 )
 
+// ErrTxNotSupported is returned by InTx of drivers that cannot run several writes in one transaction
+var ErrTxNotSupported = errors.New("error: the driver does not support transactions")
+
 type (
 	// The Driver interface represents all database operations required by the Viper HTTP DB
 	Driver interface {
 		Reader
 		Writer
+
+		InTx(ctx context.Context, opts *TxOptions, fn func(w Writer) error) error
+	}
+
+	// TxOptions holds the options for running several Writer operations in one transaction
+	TxOptions struct {
+		Isolation sql.IsolationLevel
+		ReadOnly  bool
 	}
 
 	Reader interface {
@@ -18,7 +32,13 @@ type (
 		ReadApplications(ctx context.Context) ([]*types.Application, error)
 		ReadLoadBalancers(ctx context.Context) ([]*types.LoadBalancer, error)
 		ReadUserRoles(ctx context.Context) (map[string]map[string][]types.PermissionsEnum, error)
+		ReadPendingInvitations(ctx context.Context, email string) ([]*types.Invitation, error)
 		ReadBlockchains(ctx context.Context) ([]*types.Blockchain, error)
+		ReadAuditLog(ctx context.Context, filter types.AuditLogFilter) ([]*types.AuditLogEntry, error)
+		ReadUsage(ctx context.Context, filter types.RelayUsageFilter) ([]*types.RelayUsage, error)
+		ReadApplicationsOverLimit(ctx context.Context) ([]*types.ApplicationUsage, error)
+		ReadAppPlanHistory(ctx context.Context, appID string, from, to time.Time) ([]*types.AppPlanPeriod, error)
+		ReadAppPlanAt(ctx context.Context, appID string, at time.Time) (*types.AppPlanPeriod, error)
 
 		NotificationChannel() <-chan *types.Notification
 	}
@@ -26,8 +46,14 @@ type (
 	Writer interface {
 		WriteLoadBalancer(ctx context.Context, loadBalancer *types.LoadBalancer) (*types.LoadBalancer, error)
 		WriteLoadBalancerUser(ctx context.Context, lbID string, userAccess types.UserAccess) error
+		WriteLoadBalancerInvitation(ctx context.Context, lbID string, userAccess types.UserAccess) (*types.Invitation, error)
+		AcceptInvitation(ctx context.Context, token, userID string) error
+		DeclineInvitation(ctx context.Context, token string) error
+		RemoveExpiredInvitations(ctx context.Context) (int64, error)
 		UpdateLoadBalancer(ctx context.Context, id string, options *types.UpdateLoadBalancer) error
 		UpdateUserAccessRole(ctx context.Context, userID, lbID string, roleName types.RoleName) error
+		UpdateLoadBalancerUser(ctx context.Context, lbID string, update *types.UpdateUserAccess) error
+		TransferLoadBalancerOwnership(ctx context.Context, lbID, fromUserID, toUserID string) error
 		RemoveLoadBalancer(ctx context.Context, id string) error
 		RemoveUserAccess(ctx context.Context, userID, lbID string) error
 
@@ -35,6 +61,11 @@ type (
 		UpdateApplication(ctx context.Context, id string, update *types.UpdateApplication) error
 		UpdateAppFirstDateSurpassed(ctx context.Context, update *types.UpdateFirstDateSurpassed) error
 		RemoveApplication(ctx context.Context, id string) error
+		RotateSecretKey(ctx context.Context, appID, name string, overlap time.Duration) (*types.SecretKey, error)
+		RevokeSecretKey(ctx context.Context, appID, name string) error
+		IncrementUsage(ctx context.Context, appID string, relays int64) (int64, error)
+		FlushUsage(ctx context.Context, usage []types.RelayUsage) error
+		EvaluateUsageThresholds(ctx context.Context, appID string) ([]*types.UsageThresholdEvent, error)
 
 		WriteBlockchain(ctx context.Context, blockchain *types.Blockchain) (*types.Blockchain, error)
 		WriteRedirect(ctx context.Context, redirect *types.Redirect) (*types.Redirect, error)
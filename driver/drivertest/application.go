@@ -0,0 +1,281 @@
+package drivertest
+
+import (
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* applicationTables are the tables a new Application without a gateway AAT sends notifications for */
+var applicationTables = []types.Table{
+	types.TableApplications, types.TableAppLimits, types.TableGatewaySettings,
+	types.TableSecretKeys, types.TableNotificationSettings,
+}
+
+/* writeApplication writes an Application on the pay plan and receives its notifications */
+func (s *Suite) writeApplication(payPlan types.PayPlanType) *types.Application {
+	s.T().Helper()
+
+	app, err := s.driver.WriteApplication(testCtx, &types.Application{
+		Name:         unique("drivertest_app"),
+		UserID:       unique("drivertest_user"),
+		ContactEmail: "drivertest@test.com",
+		Status:       types.InService,
+		Limit: types.AppLimit{
+			PayPlan: types.PayPlan{Type: payPlan},
+		},
+		NotificationSettings: types.NotificationSettings{SignedUp: true, Half: true, Full: true},
+	})
+	s.Require().NoError(err)
+
+	notification := s.notificationOf(s.expectNotifications(applicationTables...), types.TableApplications)
+	s.Equal(types.ActionInsert, notification.Action)
+	s.Equal(app.ID, notification.Data.(*types.Application).ID)
+
+	return app
+}
+
+/* readApplication returns the stored Application with the ID, failing if there is none */
+func (s *Suite) readApplication(id string) *types.Application {
+	s.T().Helper()
+
+	apps, err := s.driver.ReadApplications(testCtx)
+	s.Require().NoError(err)
+
+	for _, app := range apps {
+		if app.ID == id {
+			return app
+		}
+	}
+	s.Require().Failf("application not found", "no application with ID %s", id)
+
+	return nil
+}
+
+func (s *Suite) Test_WriteApplication() {
+	app := s.writeApplication(types.FreetierV0)
+	s.NotEmpty(app.ID)
+	s.NotEmpty(app.GatewaySettings.SecretKey)
+
+	stored := s.readApplication(app.ID)
+	s.Equal(app.Name, stored.Name)
+	s.Equal(app.UserID, stored.UserID)
+	s.Equal(types.InService, stored.Status)
+	s.Equal(types.FreetierV0, stored.Limit.PayPlan.Type)
+	s.Equal(250_000, stored.Limit.DailyLimit())
+	s.True(stored.NotificationSettings.Half)
+	s.Equal(1, stored.Version)
+
+	// Only the hash of the generated secret key is stored
+	s.NotEqual(app.GatewaySettings.SecretKey, stored.GatewaySettings.SecretKey)
+	s.True(types.VerifySecretKey(stored.GatewaySettings.SecretKey, app.GatewaySettings.SecretKey))
+
+	other := s.writeApplication(types.FreetierV0)
+	s.NotEqual(app.ID, other.ID)
+}
+
+func (s *Suite) Test_WriteApplication_Validation() {
+	tests := []struct {
+		name string
+		app  *types.Application
+		err  error
+	}{
+		{
+			name: "Should fail if the status is invalid",
+			app: &types.Application{
+				Name:   unique("drivertest_app"),
+				Status: "NOT_A_STATUS",
+				Limit:  types.AppLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}},
+			},
+			err: types.ErrInvalidAppStatus,
+		},
+		{
+			name: "Should fail if a plan that is not enterprise has a custom limit",
+			app: &types.Application{
+				Name:   unique("drivertest_app"),
+				Status: types.InService,
+				Limit:  types.AppLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}, CustomLimit: 10},
+			},
+			err: types.ErrNotEnterprisePlan,
+		},
+		{
+			name: "Should fail if the secret key is set",
+			app: &types.Application{
+				Name:            unique("drivertest_app"),
+				Status:          types.InService,
+				Limit:           types.AppLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}},
+				GatewaySettings: types.GatewaySettings{SecretKey: "not_generated"},
+			},
+			err: types.ErrSecretKeyIsGenerated,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			_, err := s.driver.WriteApplication(testCtx, test.app)
+			s.ErrorIs(err, test.err)
+
+			var validationErr *types.ValidationError
+			s.ErrorAs(err, &validationErr)
+		})
+	}
+
+	s.expectNoNotifications()
+}
+
+func (s *Suite) Test_UpdateApplication() {
+	app := s.writeApplication(types.FreetierV0)
+
+	err := s.driver.UpdateApplication(testCtx, app.ID, &types.UpdateApplication{
+		Name: "drivertest_app_updated",
+		GatewaySettings: &types.UpdateGatewaySettings{
+			WhitelistOrigins:    []string{"https://www.example.com"},
+			WhitelistUserAgents: []string{"drivertest"},
+		},
+		NotificationSettings: &types.UpdateNotificationSettings{Quarter: boolPointer(true), Full: boolPointer(false)},
+		Limit: &types.AppLimit{
+			PayPlan:     types.PayPlan{Type: types.Enterprise},
+			CustomLimit: 2_000_000,
+		},
+		ExpectedVersion: 1,
+	})
+	s.Require().NoError(err)
+	s.expectNotifications(types.TableApplications, types.TableAppLimits, types.TableGatewaySettings, types.TableNotificationSettings)
+
+	stored := s.readApplication(app.ID)
+	s.Equal("drivertest_app_updated", stored.Name)
+	s.Equal(2, stored.Version)
+	s.Equal(types.Enterprise, stored.Limit.PayPlan.Type)
+	s.Equal(2_000_000, stored.Limit.DailyLimit())
+	s.Equal([]string{"https://www.example.com"}, stored.GatewaySettings.WhitelistOrigins)
+	s.Equal([]string{"drivertest"}, stored.GatewaySettings.WhitelistUserAgents)
+	s.True(stored.NotificationSettings.SignedUp)
+	s.True(stored.NotificationSettings.Quarter)
+	s.True(stored.NotificationSettings.Half)
+	s.False(stored.NotificationSettings.Full)
+
+	// The plan change is recorded in the plan history
+	period, err := s.driver.ReadAppPlanAt(testCtx, app.ID, time.Now())
+	s.Require().NoError(err)
+	s.Equal(types.Enterprise, period.Limit.PayPlan.Type)
+	s.Equal(2_000_000, period.Limit.CustomLimit)
+
+	history, err := s.driver.ReadAppPlanHistory(testCtx, app.ID, time.Time{}, time.Time{})
+	s.Require().NoError(err)
+	s.Len(history, 2)
+	s.Equal(types.FreetierV0, history[0].Limit.PayPlan.Type)
+	s.False(history[0].EffectiveTo.IsZero())
+}
+
+func (s *Suite) Test_UpdateApplication_Errors() {
+	app := s.writeApplication(types.FreetierV0)
+
+	tests := []struct {
+		name   string
+		id     string
+		update *types.UpdateApplication
+		err    error
+	}{
+		{
+			name:   "Should fail if the ID is missing",
+			update: &types.UpdateApplication{Name: "drivertest_app_updated"},
+			err:    types.ErrMissingID,
+		},
+		{
+			name:   "Should fail if the Application does not exist",
+			id:     unique("drivertest_missing_app"),
+			update: &types.UpdateApplication{Name: "drivertest_app_updated"},
+			err:    types.ErrNotFound,
+		},
+		{
+			name:   "Should fail if the update is invalid",
+			id:     app.ID,
+			update: &types.UpdateApplication{Limit: &types.AppLimit{PayPlan: types.PayPlan{Type: types.Enterprise}}},
+			err:    types.ErrEnterprisePlanNeedsCustomLimit,
+		},
+		{
+			name:   "Should fail if the expected version is not the stored one",
+			id:     app.ID,
+			update: &types.UpdateApplication{Name: "drivertest_app_updated", ExpectedVersion: 7},
+			err:    types.ErrConflict,
+		},
+		{
+			name:   "Should fail if the status transition is not allowed",
+			id:     app.ID,
+			update: &types.UpdateApplication{Status: types.Decomissioned},
+			err:    types.ErrInvalidStatusTransition,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			err := s.driver.UpdateApplication(testCtx, test.id, test.update)
+			s.ErrorIs(err, test.err)
+		})
+	}
+
+	s.expectNoNotifications()
+	s.Equal(1, s.readApplication(app.ID).Version)
+}
+
+func (s *Suite) Test_RemoveApplication() {
+	app := s.writeApplication(types.FreetierV0)
+
+	err := s.driver.RemoveApplication(testCtx, app.ID)
+	s.Require().NoError(err)
+
+	notifications := s.expectNotifications(types.TableApplications)
+	s.Equal(types.ActionUpdate, notifications[0].Action)
+	s.Equal(types.AwaitingGracePeriod, notifications[0].Data.(*types.Application).Status)
+
+	// Applications are only soft-deleted
+	s.Equal(types.AwaitingGracePeriod, s.readApplication(app.ID).Status)
+
+	err = s.driver.RemoveApplication(testCtx, unique("drivertest_missing_app"))
+	s.ErrorIs(err, types.ErrNotFound)
+
+	err = s.driver.RemoveApplication(testCtx, "")
+	s.ErrorIs(err, types.ErrMissingID)
+}
+
+func (s *Suite) Test_SecretKeys() {
+	app := s.writeApplication(types.FreetierV0)
+
+	secretKey, err := s.driver.RotateSecretKey(testCtx, app.ID, "rotated", time.Hour)
+	s.Require().NoError(err)
+	s.expectNotifications(types.TableSecretKeys, types.TableSecretKeys, types.TableGatewaySettings)
+
+	// Both keys are valid during the overlap
+	settings := s.readApplication(app.ID).GatewaySettings
+	s.True(types.VerifySecretKey(settings.SecretKey, secretKey.Key))
+	s.True(settings.VerifySecretKey(app.GatewaySettings.SecretKey, time.Now()))
+	s.True(settings.VerifySecretKey(secretKey.Key, time.Now()))
+	s.False(settings.VerifySecretKey(app.GatewaySettings.SecretKey, time.Now().Add(2*time.Hour)))
+
+	// Rotating the name again expires its previous key
+	rotatedAgain, err := s.driver.RotateSecretKey(testCtx, app.ID, "rotated", time.Hour)
+	s.Require().NoError(err)
+	s.expectNotifications(types.TableSecretKeys, types.TableSecretKeys, types.TableGatewaySettings)
+
+	settings = s.readApplication(app.ID).GatewaySettings
+	s.True(settings.VerifySecretKey(secretKey.Key, time.Now()))
+	s.False(settings.VerifySecretKey(secretKey.Key, time.Now().Add(2*time.Hour)))
+	s.True(settings.VerifySecretKey(rotatedAgain.Key, time.Now().Add(2*time.Hour)))
+
+	// Every key of the name is revoked
+	err = s.driver.RevokeSecretKey(testCtx, app.ID, "rotated")
+	s.Require().NoError(err)
+	s.expectNotifications(types.TableSecretKeys, types.TableSecretKeys, types.TableGatewaySettings)
+
+	settings = s.readApplication(app.ID).GatewaySettings
+	s.Empty(settings.SecretKey)
+	s.False(settings.VerifySecretKey(secretKey.Key, time.Now()))
+	s.False(settings.VerifySecretKey(rotatedAgain.Key, time.Now()))
+
+	err = s.driver.RevokeSecretKey(testCtx, app.ID, "rotated")
+	s.ErrorIs(err, types.ErrSecretKeyNotFound)
+}
+
+func boolPointer(value bool) *bool {
+	return &value
+}
@@ -0,0 +1,140 @@
+/*
+Package drivertest runs a standard set of scenarios against any driver.Driver so alternative backends and
+decorators can prove they behave like the PostgresDriver: CRUD, validation errors, soft-deletes,
+user role maps and the notifications sent for each write.
+*/
+package drivertest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vishruthsk/portal-db-main/driver"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const (
+	defaultNotificationTimeout = 5 * time.Second
+	// quietPeriod is how long no further notification must arrive once the expected ones were received
+	quietPeriod = 100 * time.Millisecond
+)
+
+var testCtx = context.Background()
+
+/*
+The Suite struct runs every scenario against the driver.Driver returned by NewDriver, which is called before each of them.
+
+Scenarios only rely on the records they write and look them up by ID, so NewDriver may return the same driver
+every time and that driver may already hold data, like the seeded test database. The pay plans and user roles
+of the test database must exist.
+*/
+type Suite struct {
+	suite.Suite
+
+	NewDriver func() (driver.Driver, error)
+	// NotificationTimeout is how long to wait for an expected notification, 5 seconds if zero
+	NotificationTimeout time.Duration
+
+	driver driver.Driver
+}
+
+/* Run runs the Suite against the drivers returned by newDriver */
+func Run(t *testing.T, newDriver func() (driver.Driver, error)) {
+	suite.Run(t, &Suite{NewDriver: newDriver})
+}
+
+// SetupTest runs before each scenario, the notifications left by the previous one are discarded
+func (s *Suite) SetupTest() {
+	d, err := s.NewDriver()
+	s.Require().NoError(err)
+	s.Require().NotNil(d)
+	s.driver = d
+
+	for {
+		select {
+		case <-s.driver.NotificationChannel():
+		case <-time.After(quietPeriod):
+			return
+		}
+	}
+}
+
+/*
+expectNotifications returns the next notifications, failing if their tables are not the expected ones.
+The order is not checked since drivers like the PostgresDriver may send the notifications of a write in any order,
+notificationOf and notificationsOf find them by table.
+*/
+func (s *Suite) expectNotifications(tables ...types.Table) []*types.Notification {
+	s.T().Helper()
+
+	timeout := s.NotificationTimeout
+	if timeout == 0 {
+		timeout = defaultNotificationTimeout
+	}
+
+	var notifications []*types.Notification
+	var received []types.Table
+	for len(notifications) < len(tables) {
+		select {
+		case notification, ok := <-s.driver.NotificationChannel():
+			s.Require().True(ok, "notification channel closed")
+			notifications = append(notifications, notification)
+			received = append(received, notification.Table)
+		case <-time.After(timeout):
+			s.Require().Failf("missing notifications", "expected %v, received %v", tables, received)
+		}
+	}
+
+	select {
+	case notification := <-s.driver.NotificationChannel():
+		s.Require().Failf("unexpected notification", "expected %v, then received %s", tables, notification.Table)
+	case <-time.After(quietPeriod):
+	}
+
+	s.Require().ElementsMatch(tables, received)
+
+	return notifications
+}
+
+/* notificationOf returns the notification of the table, failing if there is not exactly one */
+func (s *Suite) notificationOf(notifications []*types.Notification, table types.Table) *types.Notification {
+	s.T().Helper()
+
+	ofTable := notificationsOf(notifications, table)
+	s.Require().Len(ofTable, 1, "notifications of %s", table)
+
+	return ofTable[0]
+}
+
+/* notificationsOf returns the notifications of the table in the order they were received */
+func notificationsOf(notifications []*types.Notification, table types.Table) []*types.Notification {
+	var ofTable []*types.Notification
+	for _, notification := range notifications {
+		if notification.Table == table {
+			ofTable = append(ofTable, notification)
+		}
+	}
+
+	return ofTable
+}
+
+/* expectNoNotifications fails if a notification is sent */
+func (s *Suite) expectNoNotifications() {
+	s.T().Helper()
+	s.expectNotifications()
+}
+
+/* unique returns value followed by a random suffix, so scenarios run on a driver holding data do not collide */
+func unique(value string) string {
+	suffix := make([]byte, 6)
+	_, err := rand.Read(suffix)
+	if err != nil {
+		panic(err)
+	}
+
+	return value + "_" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,296 @@
+package drivertest
+
+import (
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* writeLoadBalancer writes a LoadBalancer owned by a new user with a new Application and receives their notifications */
+func (s *Suite) writeLoadBalancer() *types.LoadBalancer {
+	s.T().Helper()
+
+	app := s.writeApplication(types.FreetierV0)
+	ownerID := unique("drivertest_owner")
+
+	lb, err := s.driver.WriteLoadBalancer(testCtx, &types.LoadBalancer{
+		Name:           unique("drivertest_lb"),
+		UserID:         ownerID,
+		ApplicationIDs: []string{app.ID},
+		RequestTimeout: 5_000,
+		StickyOptions: types.StickyOptions{
+			Duration:      "60",
+			StickyOrigins: []string{"chrome-extension://"},
+			StickyMax:     300,
+			Stickiness:    true,
+		},
+		Users: []types.UserAccess{{UserID: ownerID, Email: ownerID + "@test.com"}},
+	})
+	s.Require().NoError(err)
+
+	notifications := s.expectNotifications(types.TableLoadBalancers, types.TableStickinessOptions, types.TableUserAccess, types.TableLbApps)
+	s.Equal(lb.ID, s.notificationOf(notifications, types.TableLoadBalancers).Data.(*types.LoadBalancer).ID)
+	s.Equal(&types.LbApp{LbID: lb.ID, AppID: app.ID}, s.notificationOf(notifications, types.TableLbApps).Data)
+
+	return lb
+}
+
+/* readLoadBalancer returns the stored LoadBalancer with the ID, failing if there is none */
+func (s *Suite) readLoadBalancer(id string) *types.LoadBalancer {
+	s.T().Helper()
+
+	loadBalancers, err := s.driver.ReadLoadBalancers(testCtx)
+	s.Require().NoError(err)
+
+	for _, lb := range loadBalancers {
+		if lb.ID == id {
+			return lb
+		}
+	}
+	s.Require().Failf("load balancer not found", "no load balancer with ID %s", id)
+
+	return nil
+}
+
+/* readUserRoles returns the permissions of each LoadBalancer the user has access to */
+func (s *Suite) readUserRoles(userID string) map[string][]types.PermissionsEnum {
+	s.T().Helper()
+
+	userRoles, err := s.driver.ReadUserRoles(testCtx)
+	s.Require().NoError(err)
+
+	return userRoles[userID]
+}
+
+func (s *Suite) Test_WriteLoadBalancer() {
+	lb := s.writeLoadBalancer()
+	s.NotEmpty(lb.ID)
+	s.Equal(types.RoleOwner, lb.Users[0].RoleName)
+
+	stored := s.readLoadBalancer(lb.ID)
+	s.Equal(lb.Name, stored.Name)
+	s.Equal(lb.UserID, stored.UserID)
+	s.Equal(lb.ApplicationIDs, stored.ApplicationIDs)
+	s.Equal(5_000, stored.RequestTimeout)
+	s.Equal(lb.StickyOptions, stored.StickyOptions)
+	s.Equal(1, stored.Version)
+	s.Equal([]types.UserAccess{{
+		UserID:   lb.UserID,
+		RoleName: types.RoleOwner,
+		Email:    lb.UserID + "@test.com",
+		Accepted: true,
+	}}, stored.Users)
+
+	s.Equal(map[string][]types.PermissionsEnum{
+		lb.ID: {types.ReadEndpoint, types.WriteEndpoint},
+	}, s.readUserRoles(lb.UserID))
+}
+
+func (s *Suite) Test_WriteLoadBalancer_Errors() {
+	_, err := s.driver.WriteLoadBalancer(testCtx, &types.LoadBalancer{Name: unique("drivertest_lb")})
+	s.ErrorIs(err, types.ErrLBMustHaveUser)
+
+	ownerID := unique("drivertest_owner")
+	_, err = s.driver.WriteLoadBalancer(testCtx, &types.LoadBalancer{
+		Name:           unique("drivertest_lb"),
+		UserID:         ownerID,
+		ApplicationIDs: []string{unique("drivertest_missing_app")},
+		Users:          []types.UserAccess{{UserID: ownerID, Email: ownerID + "@test.com"}},
+	})
+	s.ErrorIs(err, types.ErrReferenceViolation)
+
+	// Nothing of the failed LoadBalancer is kept
+	s.expectNoNotifications()
+	s.Empty(s.readUserRoles(ownerID))
+}
+
+func (s *Suite) Test_LoadBalancerUsers() {
+	lb := s.writeLoadBalancer()
+	memberID := unique("drivertest_member")
+
+	err := s.driver.WriteLoadBalancerUser(testCtx, lb.ID, types.UserAccess{
+		UserID:   memberID,
+		RoleName: types.RoleOwner,
+		Email:    memberID + "@test.com",
+	})
+	s.ErrorIs(err, types.ErrCannotSetToOwner)
+
+	err = s.driver.WriteLoadBalancerUser(testCtx, lb.ID, types.UserAccess{UserID: memberID, RoleName: types.RoleMember})
+	s.ErrorIs(err, types.ErrUserInputIsMissingField)
+
+	err = s.driver.WriteLoadBalancerUser(testCtx, lb.ID, types.UserAccess{
+		UserID:   memberID,
+		RoleName: types.RoleMember,
+		Email:    memberID + "@test.com",
+	})
+	s.Require().NoError(err)
+
+	notifications := s.expectNotifications(types.TableUserAccess)
+	s.Equal(&types.UserAccess{
+		ID:       lb.ID,
+		UserID:   memberID,
+		RoleName: types.RoleMember,
+		Email:    memberID + "@test.com",
+	}, notifications[0].Data)
+	s.Equal(map[string][]types.PermissionsEnum{lb.ID: {types.ReadEndpoint}}, s.readUserRoles(memberID))
+
+	err = s.driver.UpdateUserAccessRole(testCtx, memberID, lb.ID, types.RoleAdmin)
+	s.Require().NoError(err)
+	s.expectNotifications(types.TableLoadBalancers, types.TableUserAccess)
+	s.Equal(map[string][]types.PermissionsEnum{lb.ID: {types.ReadEndpoint, types.WriteEndpoint}}, s.readUserRoles(memberID))
+
+	err = s.driver.RemoveUserAccess(testCtx, memberID, lb.ID)
+	s.Require().NoError(err)
+	s.Empty(s.readUserRoles(memberID))
+
+	err = s.driver.RemoveUserAccess(testCtx, memberID, lb.ID)
+	s.ErrorIs(err, types.ErrNotFound)
+}
+
+func (s *Suite) Test_LoadBalancerInvitations() {
+	lb := s.writeLoadBalancer()
+	email := unique("drivertest_invited") + "@test.com"
+	userID := unique("drivertest_invited")
+
+	invitation, err := s.driver.WriteLoadBalancerInvitation(testCtx, lb.ID, types.UserAccess{
+		RoleName: types.RoleMember,
+		Email:    email,
+	})
+	s.Require().NoError(err)
+	s.NotEmpty(invitation.Token)
+	s.expectNotifications(types.TableUserAccess)
+
+	invitations, err := s.driver.ReadPendingInvitations(testCtx, email)
+	s.Require().NoError(err)
+	s.Require().Len(invitations, 1)
+	s.Equal(invitation.Token, invitations[0].Token)
+	s.Equal(lb.ID, invitations[0].LbID)
+	s.Equal(types.RoleMember, invitations[0].RoleName)
+
+	err = s.driver.AcceptInvitation(testCtx, unique("drivertest_token"), userID)
+	s.ErrorIs(err, types.ErrInvitationNotFound)
+
+	err = s.driver.AcceptInvitation(testCtx, invitation.Token, userID)
+	s.Require().NoError(err)
+
+	notifications := s.expectNotifications(types.TableUserAccess)
+	s.Equal(types.ActionUpdate, notifications[0].Action)
+	s.Equal(userID, notifications[0].Data.(*types.UserAccess).UserID)
+	s.True(notifications[0].Data.(*types.UserAccess).Accepted)
+
+	invitations, err = s.driver.ReadPendingInvitations(testCtx, email)
+	s.Require().NoError(err)
+	s.Empty(invitations)
+	s.Equal(map[string][]types.PermissionsEnum{lb.ID: {types.ReadEndpoint}}, s.readUserRoles(userID))
+
+	declined, err := s.driver.WriteLoadBalancerInvitation(testCtx, lb.ID, types.UserAccess{
+		RoleName: types.RoleAdmin,
+		Email:    email,
+	})
+	s.Require().NoError(err)
+	s.expectNotifications(types.TableUserAccess)
+
+	err = s.driver.DeclineInvitation(testCtx, declined.Token)
+	s.Require().NoError(err)
+
+	err = s.driver.DeclineInvitation(testCtx, declined.Token)
+	s.ErrorIs(err, types.ErrInvitationNotFound)
+}
+
+func (s *Suite) Test_TransferLoadBalancerOwnership() {
+	lb := s.writeLoadBalancer()
+	newOwnerID := unique("drivertest_new_owner")
+
+	invitation, err := s.driver.WriteLoadBalancerInvitation(testCtx, lb.ID, types.UserAccess{
+		UserID:   newOwnerID,
+		RoleName: types.RoleAdmin,
+		Email:    newOwnerID + "@test.com",
+	})
+	s.Require().NoError(err)
+	s.expectNotifications(types.TableUserAccess)
+
+	err = s.driver.TransferLoadBalancerOwnership(testCtx, lb.ID, lb.UserID, newOwnerID)
+	s.ErrorIs(err, types.ErrNewOwnerNotAccepted)
+
+	err = s.driver.AcceptInvitation(testCtx, invitation.Token, newOwnerID)
+	s.Require().NoError(err)
+	s.expectNotifications(types.TableUserAccess)
+
+	err = s.driver.TransferLoadBalancerOwnership(testCtx, lb.ID, lb.UserID, lb.UserID)
+	s.ErrorIs(err, types.ErrSameOwner)
+
+	err = s.driver.TransferLoadBalancerOwnership(testCtx, lb.ID, newOwnerID, lb.UserID)
+	s.ErrorIs(err, types.ErrUserIsNotOwner)
+
+	err = s.driver.TransferLoadBalancerOwnership(testCtx, lb.ID, lb.UserID, newOwnerID)
+	s.Require().NoError(err)
+
+	// The previous owner is demoted and the new one promoted
+	notifications := s.expectNotifications(types.TableUserAccess, types.TableUserAccess, types.TableLoadBalancers)
+	roles := map[string]types.RoleName{}
+	for _, notification := range notificationsOf(notifications, types.TableUserAccess) {
+		userAccess := notification.Data.(*types.UserAccess)
+		roles[userAccess.UserID] = userAccess.RoleName
+	}
+	s.Equal(map[string]types.RoleName{lb.UserID: types.RoleAdmin, newOwnerID: types.RoleOwner}, roles)
+	s.Equal(newOwnerID, s.notificationOf(notifications, types.TableLoadBalancers).Data.(*types.LoadBalancer).UserID)
+
+	stored := s.readLoadBalancer(lb.ID)
+	s.Equal(newOwnerID, stored.UserID)
+	s.Equal(2, stored.Version)
+}
+
+func (s *Suite) Test_UpdateLoadBalancer() {
+	lb := s.writeLoadBalancer()
+
+	err := s.driver.UpdateLoadBalancer(testCtx, lb.ID, &types.UpdateLoadBalancer{
+		Name:            "drivertest_lb_updated",
+		ExpectedVersion: 1,
+	})
+	s.Require().NoError(err)
+	s.expectNotifications(types.TableLoadBalancers)
+
+	stored := s.readLoadBalancer(lb.ID)
+	s.Equal("drivertest_lb_updated", stored.Name)
+	s.Equal(2, stored.Version)
+
+	err = s.driver.UpdateLoadBalancer(testCtx, lb.ID, &types.UpdateLoadBalancer{
+		Limit: &types.LBLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}},
+	})
+	s.Require().NoError(err)
+	notifications := s.expectNotifications(types.TableLoadBalancers, types.TableLbLimits)
+	s.Equal(250_000, s.notificationOf(notifications, types.TableLbLimits).Data.(*types.LBLimit).DailyLimit())
+	s.Equal(types.FreetierV0, s.readLoadBalancer(lb.ID).Limit.PayPlan.Type)
+
+	err = s.driver.UpdateLoadBalancer(testCtx, lb.ID, &types.UpdateLoadBalancer{RemoveLimit: true})
+	s.Require().NoError(err)
+	notifications = s.expectNotifications(types.TableLoadBalancers, types.TableLbLimits)
+	s.Equal(types.ActionDelete, s.notificationOf(notifications, types.TableLbLimits).Action)
+	s.Nil(s.readLoadBalancer(lb.ID).Limit)
+
+	err = s.driver.UpdateLoadBalancer(testCtx, lb.ID, &types.UpdateLoadBalancer{Name: "drivertest_lb_stale", ExpectedVersion: 1})
+	s.ErrorIs(err, types.ErrConflict)
+
+	err = s.driver.UpdateLoadBalancer(testCtx, unique("drivertest_missing_lb"), &types.UpdateLoadBalancer{Name: "drivertest_lb_updated"})
+	s.ErrorIs(err, types.ErrNotFound)
+
+	s.expectNoNotifications()
+}
+
+func (s *Suite) Test_RemoveLoadBalancer() {
+	lb := s.writeLoadBalancer()
+
+	err := s.driver.RemoveLoadBalancer(testCtx, lb.ID)
+	s.Require().NoError(err)
+
+	notifications := s.expectNotifications(types.TableLoadBalancers)
+	s.Equal(types.ActionUpdate, notifications[0].Action)
+	s.Empty(notifications[0].Data.(*types.LoadBalancer).UserID)
+
+	// LoadBalancers are only soft-deleted by clearing their user
+	s.Empty(s.readLoadBalancer(lb.ID).UserID)
+
+	err = s.driver.RemoveLoadBalancer(testCtx, unique("drivertest_missing_lb"))
+	s.ErrorIs(err, types.ErrNotFound)
+
+	err = s.driver.RemoveLoadBalancer(testCtx, "")
+	s.ErrorIs(err, types.ErrMissingID)
+}
@@ -0,0 +1,66 @@
+package drivertest
+
+import (
+	"errors"
+
+	"github.com/vishruthsk/portal-db-main/driver"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func (s *Suite) Test_InTx() {
+	var app *types.Application
+
+	err := s.driver.InTx(testCtx, nil, func(w driver.Writer) error {
+		var err error
+		app, err = w.WriteApplication(testCtx, &types.Application{
+			Name:   unique("drivertest_app"),
+			UserID: unique("drivertest_user"),
+			Status: types.InService,
+			Limit:  types.AppLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}},
+		})
+		if err != nil {
+			return err
+		}
+
+		return w.UpdateApplication(testCtx, app.ID, &types.UpdateApplication{Name: "drivertest_app_updated"})
+	})
+	s.skipIfTxNotSupported(err)
+	s.Require().NoError(err)
+
+	// Notifications are only sent once the transaction commits
+	s.expectNotifications(append(applicationTables, types.TableApplications)...)
+	s.Equal("drivertest_app_updated", s.readApplication(app.ID).Name)
+}
+
+func (s *Suite) Test_InTx_Rollback() {
+	errRollback := errors.New("rollback")
+	name := unique("drivertest_app")
+
+	err := s.driver.InTx(testCtx, nil, func(w driver.Writer) error {
+		_, err := w.WriteApplication(testCtx, &types.Application{
+			Name:   name,
+			UserID: unique("drivertest_user"),
+			Status: types.InService,
+			Limit:  types.AppLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}},
+		})
+		s.Require().NoError(err)
+
+		return errRollback
+	})
+	s.skipIfTxNotSupported(err)
+	s.ErrorIs(err, errRollback)
+	s.expectNoNotifications()
+
+	apps, err := s.driver.ReadApplications(testCtx)
+	s.Require().NoError(err)
+	for _, app := range apps {
+		s.NotEqual(name, app.Name)
+	}
+}
+
+/* skipIfTxNotSupported skips the scenario for drivers that cannot run writes in a transaction */
+func (s *Suite) skipIfTxNotSupported(err error) {
+	if errors.Is(err, driver.ErrTxNotSupported) {
+		s.T().Skip("the driver does not support transactions")
+	}
+}
@@ -0,0 +1,121 @@
+package drivertest
+
+import (
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* readOverLimit returns the usage of the Application if it is over its daily limit today, or nil */
+func (s *Suite) readOverLimit(appID string) *types.ApplicationUsage {
+	s.T().Helper()
+
+	apps, err := s.driver.ReadApplicationsOverLimit(testCtx)
+	s.Require().NoError(err)
+
+	for _, app := range apps {
+		if app.ApplicationID == appID {
+			return app
+		}
+	}
+
+	return nil
+}
+
+func (s *Suite) Test_IncrementUsage() {
+	// The test plan has a daily limit of 100 relays
+	app := s.writeApplication(types.TestPlanV0)
+
+	total, err := s.driver.IncrementUsage(testCtx, app.ID, 60)
+	s.Require().NoError(err)
+	s.Equal(int64(60), total)
+	s.expectNoNotifications()
+	s.Nil(s.readOverLimit(app.ID))
+
+	// Going over the daily limit sets FirstDateSurpassed the first time only
+	total, err = s.driver.IncrementUsage(testCtx, app.ID, 60)
+	s.Require().NoError(err)
+	s.Equal(int64(120), total)
+
+	notifications := s.expectNotifications(types.TableApplications)
+	s.False(notifications[0].Data.(*types.Application).FirstDateSurpassed.IsZero())
+
+	_, err = s.driver.IncrementUsage(testCtx, app.ID, 1)
+	s.Require().NoError(err)
+	s.expectNoNotifications()
+
+	overLimit := s.readOverLimit(app.ID)
+	s.Require().NotNil(overLimit)
+	s.Equal(int64(121), overLimit.Quota.Used)
+	s.Equal(int64(100), overLimit.Quota.Limit)
+	s.False(overLimit.FirstDateSurpassed.IsZero())
+
+	_, err = s.driver.IncrementUsage(testCtx, app.ID, -1)
+	s.ErrorIs(err, types.ErrNegativeRelays)
+
+	_, err = s.driver.IncrementUsage(testCtx, unique("drivertest_missing_app"), 1)
+	s.ErrorIs(err, types.ErrReferenceViolation)
+}
+
+func (s *Suite) Test_FlushUsage() {
+	app := s.writeApplication(types.FreetierV0)
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	err := s.driver.FlushUsage(testCtx, []types.RelayUsage{
+		{ApplicationID: app.ID, Relays: 10},
+		{ApplicationID: app.ID, Relays: 5},
+		{ApplicationID: app.ID, Date: yesterday, Relays: 7},
+	})
+	s.Require().NoError(err)
+
+	usage, err := s.driver.ReadUsage(testCtx, types.RelayUsageFilter{ApplicationID: app.ID})
+	s.Require().NoError(err)
+	s.Require().Len(usage, 2)
+	s.True(types.UsageDay(yesterday).Equal(usage[0].Date))
+	s.Equal(int64(7), usage[0].Relays)
+	s.True(types.UsageDay(time.Now()).Equal(usage[1].Date))
+	s.Equal(int64(15), usage[1].Relays)
+
+	// Nothing of the batch is counted if one of the Applications does not exist
+	err = s.driver.FlushUsage(testCtx, []types.RelayUsage{
+		{ApplicationID: app.ID, Relays: 10},
+		{ApplicationID: unique("drivertest_missing_app"), Relays: 10},
+	})
+	s.ErrorIs(err, types.ErrReferenceViolation)
+
+	usage, err = s.driver.ReadUsage(testCtx, types.RelayUsageFilter{ApplicationID: app.ID, From: time.Now()})
+	s.Require().NoError(err)
+	s.Require().Len(usage, 1)
+	s.Equal(int64(15), usage[0].Relays)
+}
+
+func (s *Suite) Test_EvaluateUsageThresholds() {
+	// The Application is notified at half and full usage of the test plan's 100 relays
+	app := s.writeApplication(types.TestPlanV0)
+
+	_, err := s.driver.IncrementUsage(testCtx, app.ID, 80)
+	s.Require().NoError(err)
+
+	events, err := s.driver.EvaluateUsageThresholds(testCtx, app.ID)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal(types.ThresholdHalf, events[0].Threshold)
+	s.Equal(app.ID, events[0].ApplicationID)
+	s.Equal(int64(80), events[0].Quota.Used)
+
+	// Each threshold is only returned once a day
+	events, err = s.driver.EvaluateUsageThresholds(testCtx, app.ID)
+	s.Require().NoError(err)
+	s.Empty(events)
+
+	_, err = s.driver.IncrementUsage(testCtx, app.ID, 20)
+	s.Require().NoError(err)
+
+	events, err = s.driver.EvaluateUsageThresholds(testCtx, app.ID)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal(types.ThresholdFull, events[0].Threshold)
+
+	_, err = s.driver.EvaluateUsageThresholds(testCtx, unique("drivertest_missing_app"))
+	s.ErrorIs(err, types.ErrNotFound)
+}
@@ -4,6 +4,7 @@ package driver
 
 import (
 	context "context"
+	time "time"
 
 	types "github.com/vishruthsk/portal-db-main/types"
 	mock "github.com/stretchr/testify/mock"
@@ -14,6 +15,20 @@ type MockDriver struct {
 	mock.Mock
 }
 
+// AcceptInvitation provides a mock function with given fields: ctx, token, userID
+func (_m *MockDriver) AcceptInvitation(ctx context.Context, token string, userID string) error {
+	ret := _m.Called(ctx, token, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ActivateChain provides a mock function with given fields: ctx, id, active
 func (_m *MockDriver) ActivateChain(ctx context.Context, id string, active bool) error {
 	ret := _m.Called(ctx, id, active)
@@ -28,6 +43,92 @@ func (_m *MockDriver) ActivateChain(ctx context.Context, id string, active bool)
 	return r0
 }
 
+// DeclineInvitation provides a mock function with given fields: ctx, token
+func (_m *MockDriver) DeclineInvitation(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EvaluateUsageThresholds provides a mock function with given fields: ctx, appID
+func (_m *MockDriver) EvaluateUsageThresholds(ctx context.Context, appID string) ([]*types.UsageThresholdEvent, error) {
+	ret := _m.Called(ctx, appID)
+
+	var r0 []*types.UsageThresholdEvent
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*types.UsageThresholdEvent); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.UsageThresholdEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FlushUsage provides a mock function with given fields: ctx, usage
+func (_m *MockDriver) FlushUsage(ctx context.Context, usage []types.RelayUsage) error {
+	ret := _m.Called(ctx, usage)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []types.RelayUsage) error); ok {
+		r0 = rf(ctx, usage)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InTx provides a mock function with given fields: ctx, opts, fn
+func (_m *MockDriver) InTx(ctx context.Context, opts *TxOptions, fn func(w Writer) error) error {
+	ret := _m.Called(ctx, opts, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *TxOptions, func(w Writer) error) error); ok {
+		r0 = rf(ctx, opts, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IncrementUsage provides a mock function with given fields: ctx, appID, relays
+func (_m *MockDriver) IncrementUsage(ctx context.Context, appID string, relays int64) (int64, error) {
+	ret := _m.Called(ctx, appID, relays)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) int64); ok {
+		r0 = rf(ctx, appID, relays)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, appID, relays)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NotificationChannel provides a mock function with given fields:
 func (_m *MockDriver) NotificationChannel() <-chan *types.Notification {
 	ret := _m.Called()
@@ -44,6 +145,52 @@ func (_m *MockDriver) NotificationChannel() <-chan *types.Notification {
 	return r0
 }
 
+// ReadAppPlanAt provides a mock function with given fields: ctx, appID, at
+func (_m *MockDriver) ReadAppPlanAt(ctx context.Context, appID string, at time.Time) (*types.AppPlanPeriod, error) {
+	ret := _m.Called(ctx, appID, at)
+
+	var r0 *types.AppPlanPeriod
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *types.AppPlanPeriod); ok {
+		r0 = rf(ctx, appID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.AppPlanPeriod)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, appID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadAppPlanHistory provides a mock function with given fields: ctx, appID, from, to
+func (_m *MockDriver) ReadAppPlanHistory(ctx context.Context, appID string, from time.Time, to time.Time) ([]*types.AppPlanPeriod, error) {
+	ret := _m.Called(ctx, appID, from, to)
+
+	var r0 []*types.AppPlanPeriod
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []*types.AppPlanPeriod); ok {
+		r0 = rf(ctx, appID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.AppPlanPeriod)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, appID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadApplications provides a mock function with given fields: ctx
 func (_m *MockDriver) ReadApplications(ctx context.Context) ([]*types.Application, error) {
 	ret := _m.Called(ctx)
@@ -67,6 +214,52 @@ func (_m *MockDriver) ReadApplications(ctx context.Context) ([]*types.Applicatio
 	return r0, r1
 }
 
+// ReadApplicationsOverLimit provides a mock function with given fields: ctx
+func (_m *MockDriver) ReadApplicationsOverLimit(ctx context.Context) ([]*types.ApplicationUsage, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*types.ApplicationUsage
+	if rf, ok := ret.Get(0).(func(context.Context) []*types.ApplicationUsage); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.ApplicationUsage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadAuditLog provides a mock function with given fields: ctx, filter
+func (_m *MockDriver) ReadAuditLog(ctx context.Context, filter types.AuditLogFilter) ([]*types.AuditLogEntry, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*types.AuditLogEntry
+	if rf, ok := ret.Get(0).(func(context.Context, types.AuditLogFilter) []*types.AuditLogEntry); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.AuditLogEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, types.AuditLogFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadBlockchains provides a mock function with given fields: ctx
 func (_m *MockDriver) ReadBlockchains(ctx context.Context) ([]*types.Blockchain, error) {
 	ret := _m.Called(ctx)
@@ -136,6 +329,52 @@ func (_m *MockDriver) ReadPayPlans(ctx context.Context) ([]*types.PayPlan, error
 	return r0, r1
 }
 
+// ReadPendingInvitations provides a mock function with given fields: ctx, email
+func (_m *MockDriver) ReadPendingInvitations(ctx context.Context, email string) ([]*types.Invitation, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 []*types.Invitation
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*types.Invitation); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Invitation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadUsage provides a mock function with given fields: ctx, filter
+func (_m *MockDriver) ReadUsage(ctx context.Context, filter types.RelayUsageFilter) ([]*types.RelayUsage, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*types.RelayUsage
+	if rf, ok := ret.Get(0).(func(context.Context, types.RelayUsageFilter) []*types.RelayUsage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.RelayUsage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, types.RelayUsageFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadUserRoles provides a mock function with given fields: ctx
 func (_m *MockDriver) ReadUserRoles(ctx context.Context) (map[string]map[string][]types.PermissionsEnum, error) {
 	ret := _m.Called(ctx)
@@ -173,6 +412,27 @@ func (_m *MockDriver) RemoveApplication(ctx context.Context, id string) error {
 	return r0
 }
 
+// RemoveExpiredInvitations provides a mock function with given fields: ctx
+func (_m *MockDriver) RemoveExpiredInvitations(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveLoadBalancer provides a mock function with given fields: ctx, id
 func (_m *MockDriver) RemoveLoadBalancer(ctx context.Context, id string) error {
 	ret := _m.Called(ctx, id)
@@ -201,6 +461,57 @@ func (_m *MockDriver) RemoveUserAccess(ctx context.Context, userID string, lbID
 	return r0
 }
 
+// RevokeSecretKey provides a mock function with given fields: ctx, appID, name
+func (_m *MockDriver) RevokeSecretKey(ctx context.Context, appID string, name string) error {
+	ret := _m.Called(ctx, appID, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, appID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateSecretKey provides a mock function with given fields: ctx, appID, name, overlap
+func (_m *MockDriver) RotateSecretKey(ctx context.Context, appID string, name string, overlap time.Duration) (*types.SecretKey, error) {
+	ret := _m.Called(ctx, appID, name, overlap)
+
+	var r0 *types.SecretKey
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) *types.SecretKey); ok {
+		r0 = rf(ctx, appID, name, overlap)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.SecretKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, appID, name, overlap)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransferLoadBalancerOwnership provides a mock function with given fields: ctx, lbID, fromUserID, toUserID
+func (_m *MockDriver) TransferLoadBalancerOwnership(ctx context.Context, lbID string, fromUserID string, toUserID string) error {
+	ret := _m.Called(ctx, lbID, fromUserID, toUserID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, lbID, fromUserID, toUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateAppFirstDateSurpassed provides a mock function with given fields: ctx, update
 func (_m *MockDriver) UpdateAppFirstDateSurpassed(ctx context.Context, update *types.UpdateFirstDateSurpassed) error {
 	ret := _m.Called(ctx, update)
@@ -243,6 +554,20 @@ func (_m *MockDriver) UpdateLoadBalancer(ctx context.Context, id string, options
 	return r0
 }
 
+// UpdateLoadBalancerUser provides a mock function with given fields: ctx, lbID, update
+func (_m *MockDriver) UpdateLoadBalancerUser(ctx context.Context, lbID string, update *types.UpdateUserAccess) error {
+	ret := _m.Called(ctx, lbID, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *types.UpdateUserAccess) error); ok {
+		r0 = rf(ctx, lbID, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUserAccessRole provides a mock function with given fields: ctx, userID, lbID, roleName
 func (_m *MockDriver) UpdateUserAccessRole(ctx context.Context, userID string, lbID string, roleName types.RoleName) error {
 	ret := _m.Called(ctx, userID, lbID, roleName)
@@ -326,6 +651,29 @@ func (_m *MockDriver) WriteLoadBalancer(ctx context.Context, loadBalancer *types
 	return r0, r1
 }
 
+// WriteLoadBalancerInvitation provides a mock function with given fields: ctx, lbID, userAccess
+func (_m *MockDriver) WriteLoadBalancerInvitation(ctx context.Context, lbID string, userAccess types.UserAccess) (*types.Invitation, error) {
+	ret := _m.Called(ctx, lbID, userAccess)
+
+	var r0 *types.Invitation
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.UserAccess) *types.Invitation); ok {
+		r0 = rf(ctx, lbID, userAccess)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Invitation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.UserAccess) error); ok {
+		r1 = rf(ctx, lbID, userAccess)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WriteLoadBalancerUser provides a mock function with given fields: ctx, lbID, userAccess
 func (_m *MockDriver) WriteLoadBalancerUser(ctx context.Context, lbID string, userAccess types.UserAccess) error {
 	ret := _m.Called(ctx, lbID, userAccess)
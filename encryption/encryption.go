@@ -0,0 +1,179 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	envelopePrefix = "enc:v1:"
+	dataKeyLength  = 32
+)
+
+var (
+	ErrInvalidCiphertext = errors.New("error: ciphertext is not a valid envelope")
+	ErrUnknownKeyID      = errors.New("error: unknown key encryption key ID")
+	ErrInvalidKeyID      = errors.New("error: key encryption key IDs must be non-empty and must not contain ':'")
+	ErrInvalidKeyLength  = errors.New("error: key encryption keys must be 32 bytes long")
+)
+
+/*
+KeyProvider wraps and unwraps the per-value data keys with a key encryption key.
+Implementations may keep the keys locally or delegate to an external KMS.
+*/
+type KeyProvider interface {
+	// CurrentKeyID returns the ID of the key that new data keys are wrapped with
+	CurrentKeyID() string
+	WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+/*
+Envelope encrypts values with a random data key which is itself wrapped by the KeyProvider.
+Encrypted values take the form enc:v1:<key ID>:<wrapped data key>:<nonce and ciphertext>.
+*/
+type Envelope struct {
+	provider KeyProvider
+}
+
+/* NewEnvelope returns an Envelope using the given KeyProvider */
+func NewEnvelope(provider KeyProvider) *Envelope {
+	return &Envelope{provider: provider}
+}
+
+/* Encrypt returns the envelope for plaintext, empty values are left empty */
+func (e *Envelope) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, dataKeyLength)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	ciphertext, err := seal(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+
+	keyID := e.provider.CurrentKeyID()
+	wrappedKey, err := e.provider.WrapKey(ctx, keyID, dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return envelopePrefix + keyID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+/* Decrypt returns the plaintext of an envelope, values that are not encrypted are returned as they are */
+func (e *Envelope) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, wrappedKey, ciphertext, err := parseEnvelope(value)
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := e.provider.UnwrapKey(ctx, keyID, wrappedKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidCiphertext, err)
+	}
+
+	return string(plaintext), nil
+}
+
+/* NeedsRotation reports whether value is not yet encrypted or is encrypted under a key other than the current one */
+func (e *Envelope) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+
+	keyID, _, _, err := parseEnvelope(value)
+	if err != nil {
+		return true
+	}
+
+	return keyID != e.provider.CurrentKeyID()
+}
+
+/* IsEncrypted reports whether value is an envelope produced by Encrypt */
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+func parseEnvelope(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, envelopePrefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %s", ErrInvalidCiphertext, err)
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %s", ErrInvalidCiphertext, err)
+	}
+
+	return parts[0], wrappedKey, ciphertext, nil
+}
+
+/* seal encrypts plaintext with AES-GCM and prepends the random nonce */
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+/* open reverses seal */
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, sealed, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
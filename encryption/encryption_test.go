@@ -0,0 +1,169 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testCtx = context.Background()
+	key1    = bytes.Repeat([]byte{1}, keyLength)
+	key2    = bytes.Repeat([]byte{2}, keyLength)
+)
+
+func newTestProvider(t *testing.T, currentKeyID string) *LocalKeyProvider {
+	provider, err := NewLocalKeyProvider(currentKeyID, map[string][]byte{"key-1": key1, "key-2": key2})
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestEnvelope_EncryptDecrypt(t *testing.T) {
+	c := require.New(t)
+
+	envelope := NewEnvelope(newTestProvider(t, "key-1"))
+
+	encrypted, err := envelope.Encrypt(testCtx, "test_f403700aed7e039c0a8fc2dd22da6fd9")
+	c.NoError(err)
+	c.True(IsEncrypted(encrypted))
+	c.True(strings.HasPrefix(encrypted, "enc:v1:key-1:"))
+	c.NotContains(encrypted, "test_f403700aed7e039c0a8fc2dd22da6fd9")
+
+	encryptedAgain, err := envelope.Encrypt(testCtx, "test_f403700aed7e039c0a8fc2dd22da6fd9")
+	c.NoError(err)
+	c.NotEqual(encrypted, encryptedAgain)
+
+	decrypted, err := envelope.Decrypt(testCtx, encrypted)
+	c.NoError(err)
+	c.Equal("test_f403700aed7e039c0a8fc2dd22da6fd9", decrypted)
+
+	empty, err := envelope.Encrypt(testCtx, "")
+	c.NoError(err)
+	c.Empty(empty)
+
+	plaintext, err := envelope.Decrypt(testCtx, "test_not_encrypted")
+	c.NoError(err)
+	c.Equal("test_not_encrypted", plaintext)
+}
+
+func TestEnvelope_Rotation(t *testing.T) {
+	c := require.New(t)
+
+	oldEnvelope := NewEnvelope(newTestProvider(t, "key-1"))
+	newEnvelope := NewEnvelope(newTestProvider(t, "key-2"))
+
+	encrypted, err := oldEnvelope.Encrypt(testCtx, "secret")
+	c.NoError(err)
+
+	c.False(oldEnvelope.NeedsRotation(encrypted))
+	c.True(newEnvelope.NeedsRotation(encrypted))
+	c.True(newEnvelope.NeedsRotation("secret"))
+	c.False(newEnvelope.NeedsRotation(""))
+
+	decrypted, err := newEnvelope.Decrypt(testCtx, encrypted)
+	c.NoError(err)
+	c.Equal("secret", decrypted)
+
+	onlyNewKey, err := NewLocalKeyProvider("key-2", map[string][]byte{"key-2": key2})
+	c.NoError(err)
+
+	_, err = NewEnvelope(onlyNewKey).Decrypt(testCtx, encrypted)
+	c.ErrorIs(err, ErrUnknownKeyID)
+}
+
+func TestEnvelope_DecryptInvalid(t *testing.T) {
+	envelope := NewEnvelope(newTestProvider(t, "key-1"))
+
+	encrypted, err := envelope.Encrypt(testCtx, "secret")
+	require.NoError(t, err)
+
+	parts := strings.Split(encrypted, ":")
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[4])
+	require.NoError(t, err)
+	ciphertext[len(ciphertext)-1] ^= 0xff
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{
+			name:  "Should fail if the envelope is missing parts",
+			value: "enc:v1:key-1:abc",
+		},
+		{
+			name:  "Should fail if the envelope is not base64",
+			value: "enc:v1:key-1:!!!:!!!",
+		},
+		{
+			name:  "Should fail if the ciphertext was tampered with",
+			value: strings.Join(append(parts[:4], base64.RawStdEncoding.EncodeToString(ciphertext)), ":"),
+		},
+		{
+			name:  "Should fail if the wrapped key belongs to another key ID",
+			value: strings.Replace(encrypted, "key-1", "key-2", 1),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := envelope.Decrypt(testCtx, test.value)
+			require.ErrorIs(t, err, ErrInvalidCiphertext)
+		})
+	}
+}
+
+func TestNewLocalKeyProviderFromJSON(t *testing.T) {
+	encodedKey := base64.StdEncoding.EncodeToString(key1)
+
+	tests := []struct {
+		name        string
+		data        string
+		expectedErr error
+	}{
+		{
+			name: "Should load the keys",
+			data: `{"currentKeyID": "key-1", "keys": {"key-1": "` + encodedKey + `"}}`,
+		},
+		{
+			name:        "Should fail if the current key is missing",
+			data:        `{"currentKeyID": "key-2", "keys": {"key-1": "` + encodedKey + `"}}`,
+			expectedErr: ErrUnknownKeyID,
+		},
+		{
+			name:        "Should fail if a key ID contains a colon",
+			data:        `{"currentKeyID": "key:1", "keys": {"key:1": "` + encodedKey + `"}}`,
+			expectedErr: ErrInvalidKeyID,
+		},
+		{
+			name:        "Should fail if a key has the wrong length",
+			data:        `{"currentKeyID": "key-1", "keys": {"key-1": "c2hvcnQ="}}`,
+			expectedErr: ErrInvalidKeyLength,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider, err := NewLocalKeyProviderFromJSON([]byte(test.data))
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "key-1", provider.CurrentKeyID())
+		})
+	}
+
+	t.Setenv("TEST_PORTAL_DB_KEYS", `{"currentKeyID": "key-1", "keys": {"key-1": "`+encodedKey+`"}}`)
+	provider, err := NewLocalKeyProviderFromEnv("TEST_PORTAL_DB_KEYS")
+	require.NoError(t, err)
+	require.Equal(t, "key-1", provider.CurrentKeyID())
+
+	_, err = NewLocalKeyProviderFromEnv("TEST_PORTAL_DB_KEYS_MISSING")
+	require.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const keyLength = 32
+
+/*
+LocalKeyProvider keeps the key encryption keys in memory, loaded from a file or an environment variable.
+Old keys are kept so values encrypted before a rotation can still be decrypted.
+*/
+type LocalKeyProvider struct {
+	currentKeyID string
+	keys         map[string][]byte
+}
+
+/* localKeysJSON is the format read by NewLocalKeyProviderFromFile and NewLocalKeyProviderFromEnv */
+type localKeysJSON struct {
+	CurrentKeyID string            `json:"currentKeyID"`
+	Keys         map[string]string `json:"keys"` // base64 encoded 32 byte keys
+}
+
+/* NewLocalKeyProvider returns a LocalKeyProvider wrapping new data keys with the key currentKeyID */
+func NewLocalKeyProvider(currentKeyID string, keys map[string][]byte) (*LocalKeyProvider, error) {
+	for keyID, key := range keys {
+		if keyID == "" || strings.Contains(keyID, ":") {
+			return nil, ErrInvalidKeyID
+		}
+		if len(key) != keyLength {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKeyLength, keyID)
+		}
+	}
+
+	if _, ok := keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, currentKeyID)
+	}
+
+	return &LocalKeyProvider{
+		currentKeyID: currentKeyID,
+		keys:         keys,
+	}, nil
+}
+
+/* NewLocalKeyProviderFromJSON parses keys of the form {"currentKeyID": "id", "keys": {"id": "<base64 key>"}} */
+func NewLocalKeyProviderFromJSON(data []byte) (*LocalKeyProvider, error) {
+	var keysJSON localKeysJSON
+	err := json.Unmarshal(data, &keysJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string][]byte, len(keysJSON.Keys))
+	for keyID, encodedKey := range keysJSON.Keys {
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("error: key %s is not valid base64: %s", keyID, err)
+		}
+
+		keys[keyID] = key
+	}
+
+	return NewLocalKeyProvider(keysJSON.CurrentKeyID, keys)
+}
+
+/* NewLocalKeyProviderFromFile reads the keys JSON from the file at path */
+func NewLocalKeyProviderFromFile(path string) (*LocalKeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocalKeyProviderFromJSON(data)
+}
+
+/* NewLocalKeyProviderFromEnv reads the keys JSON from the environment variable name */
+func NewLocalKeyProviderFromEnv(name string) (*LocalKeyProvider, error) {
+	data, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("error: environment variable %s is not set", name)
+	}
+
+	return NewLocalKeyProviderFromJSON([]byte(data))
+}
+
+/* CurrentKeyID returns the ID of the key that new data keys are wrapped with */
+func (l *LocalKeyProvider) CurrentKeyID() string {
+	return l.currentKeyID
+}
+
+/* WrapKey encrypts dataKey with the key keyID, binding the key ID as additional data */
+func (l *LocalKeyProvider) WrapKey(_ context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	key, ok := l.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, keyID)
+	}
+
+	return seal(key, dataKey, []byte(keyID))
+}
+
+/* UnwrapKey decrypts a data key wrapped by WrapKey */
+func (l *LocalKeyProvider) UnwrapKey(_ context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	key, ok := l.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, keyID)
+	}
+
+	dataKey, err := open(key, wrappedKey, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCiphertext, err)
+	}
+
+	return dataKey, nil
+}
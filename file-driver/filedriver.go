@@ -0,0 +1,313 @@
+package filedriver
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const defaultPollInterval = time.Second
+
+/*
+The FileDriver struct satisfies the driver.Reader interface reading a Snapshot file, for local development and offline testing.
+
+The file is polled for changes and reloaded when its content changes, the notifications the database triggers
+would send for the changed rows are then sent on the Notification channel. A change that does not parse or validate
+is passed to reportProblem and the previous Snapshot is kept.
+There are no usage, audit log or invitations in a Snapshot, so they are always read as empty.
+*/
+type FileDriver struct {
+	path          string
+	reportProblem func(err error)
+
+	mu       sync.RWMutex
+	snapshot *Snapshot
+	content  []byte
+
+	notification chan *types.Notification
+	done         chan struct{}
+	stopped      chan struct{}
+	closeOnce    sync.Once
+}
+
+/*
+NewFileDriver returns a FileDriver reading the Snapshot at path, or an error if it cannot be read.
+The file is polled every pollInterval, every second if it is zero. reportProblem may be nil.
+*/
+func NewFileDriver(path string, pollInterval time.Duration, reportProblem func(err error)) (*FileDriver, error) {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	if reportProblem == nil {
+		reportProblem = func(err error) {}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := ParseSnapshot(path, content)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &FileDriver{
+		path:          path,
+		reportProblem: reportProblem,
+		snapshot:      snapshot,
+		content:       content,
+		notification:  make(chan *types.Notification),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+
+	go d.watch(pollInterval)
+
+	return d, nil
+}
+
+/* NotificationChannel returns receiver Notification channel  */
+func (d *FileDriver) NotificationChannel() <-chan *types.Notification {
+	return d.notification
+}
+
+/* CloseListener stops watching the file and closes the Notification channel */
+func (d *FileDriver) CloseListener() {
+	d.closeOnce.Do(func() {
+		close(d.done)
+		<-d.stopped
+		close(d.notification)
+	})
+}
+
+/* watch reloads the file every interval until the FileDriver is closed */
+func (d *FileDriver) watch(interval time.Duration) {
+	defer close(d.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+		}
+
+		for _, notification := range d.reload() {
+			select {
+			case d.notification <- notification:
+			case <-d.done:
+				return
+			}
+		}
+	}
+}
+
+/* reload reads the file and returns the notifications for the rows that changed since it was last loaded */
+func (d *FileDriver) reload() []*types.Notification {
+	content, err := os.ReadFile(d.path)
+	if err != nil {
+		d.reportProblem(err)
+		return nil
+	}
+
+	d.mu.RLock()
+	unchanged := bytes.Equal(content, d.content)
+	d.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	snapshot, err := ParseSnapshot(d.path, content)
+	if err != nil {
+		d.reportProblem(err)
+		return nil
+	}
+
+	d.mu.Lock()
+	previous := d.snapshot
+	d.snapshot = snapshot
+	d.content = content
+	d.mu.Unlock()
+
+	return diff(previous, snapshot)
+}
+
+/* current returns the loaded Snapshot, it is never modified once loaded */
+func (d *FileDriver) current() *Snapshot {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.snapshot
+}
+
+/* ReadPayPlans returns all pay plans ordered by type */
+func (d *FileDriver) ReadPayPlans(ctx context.Context) ([]*types.PayPlan, error) {
+	var payPlans []*types.PayPlan
+	for _, payPlan := range d.current().PayPlans {
+		copied := *payPlan
+		payPlans = append(payPlans, &copied)
+	}
+
+	sort.Slice(payPlans, func(i, j int) bool { return payPlans[i].Type < payPlans[j].Type })
+
+	return payPlans, nil
+}
+
+/* ReadApplications returns all Applications ordered by ID, their pay plan limits are read from the pay plans */
+func (d *FileDriver) ReadApplications(ctx context.Context) ([]*types.Application, error) {
+	snapshot := d.current()
+
+	var apps []*types.Application
+	for _, app := range snapshot.Applications {
+		copied := *app
+		copied.Limit.PayPlan.Limit = snapshot.payPlanLimit(app.Limit.PayPlan.Type)
+		apps = append(apps, &copied)
+	}
+
+	sort.Slice(apps, func(i, j int) bool { return apps[i].ID < apps[j].ID })
+
+	return apps, nil
+}
+
+/* ReadLoadBalancers returns all LoadBalancers ordered by ID, removed ones included */
+func (d *FileDriver) ReadLoadBalancers(ctx context.Context) ([]*types.LoadBalancer, error) {
+	snapshot := d.current()
+
+	var loadBalancers []*types.LoadBalancer
+	for _, lb := range snapshot.LoadBalancers {
+		copied := *lb
+		if lb.Limit != nil {
+			limit := *lb.Limit
+			limit.PayPlan.Limit = snapshot.payPlanLimit(limit.PayPlan.Type)
+			copied.Limit = &limit
+		}
+		if copied.Users == nil {
+			copied.Users = []types.UserAccess{}
+		}
+		loadBalancers = append(loadBalancers, &copied)
+	}
+
+	sort.Slice(loadBalancers, func(i, j int) bool { return loadBalancers[i].ID < loadBalancers[j].ID })
+
+	return loadBalancers, nil
+}
+
+/* ReadUserRoles returns all User Roles as a map that takes the form map[User ID]map[LB ID][]types.PermissionsEnum */
+func (d *FileDriver) ReadUserRoles(ctx context.Context) (map[string]map[string][]types.PermissionsEnum, error) {
+	snapshot := d.current()
+
+	userRolesMap := make(map[string]map[string][]types.PermissionsEnum)
+	for _, lb := range snapshot.LoadBalancers {
+		for _, user := range lb.Users {
+			userRoles, ok := userRolesMap[user.UserID]
+			if !ok {
+				userRoles = make(map[string][]types.PermissionsEnum)
+				userRolesMap[user.UserID] = userRoles
+			}
+
+			userRoles[lb.ID] = append([]types.PermissionsEnum{}, snapshot.UserRoles[user.RoleName]...)
+		}
+	}
+
+	return userRolesMap, nil
+}
+
+/* ReadPendingInvitations returns no invitations, a Snapshot holds no invitation tokens */
+func (d *FileDriver) ReadPendingInvitations(ctx context.Context, email string) ([]*types.Invitation, error) {
+	return nil, nil
+}
+
+/* ReadBlockchains returns all blockchains ordered by ID */
+func (d *FileDriver) ReadBlockchains(ctx context.Context) ([]*types.Blockchain, error) {
+	var blockchains []*types.Blockchain
+	for _, blockchain := range d.current().Blockchains {
+		copied := *blockchain
+		if copied.Redirects == nil {
+			copied.Redirects = []types.Redirect{}
+		}
+		blockchains = append(blockchains, &copied)
+	}
+
+	sort.Slice(blockchains, func(i, j int) bool { return blockchains[i].ID < blockchains[j].ID })
+
+	return blockchains, nil
+}
+
+/* ReadAuditLog returns no entries, a Snapshot has no audit log */
+func (d *FileDriver) ReadAuditLog(ctx context.Context, filter types.AuditLogFilter) ([]*types.AuditLogEntry, error) {
+	return nil, nil
+}
+
+/* ReadUsage returns no usage, a Snapshot holds no relay counts */
+func (d *FileDriver) ReadUsage(ctx context.Context, filter types.RelayUsageFilter) ([]*types.RelayUsage, error) {
+	return nil, nil
+}
+
+/* ReadApplicationsOverLimit returns no Applications, a Snapshot holds no relay counts */
+func (d *FileDriver) ReadApplicationsOverLimit(ctx context.Context) ([]*types.ApplicationUsage, error) {
+	return nil, nil
+}
+
+/*
+ReadAppPlanHistory returns the pay plan period of an Application that overlaps the time range.
+A Snapshot holds no plan history, so an Application has had its current pay plan since it was created.
+*/
+func (d *FileDriver) ReadAppPlanHistory(ctx context.Context, appID string, from, to time.Time) ([]*types.AppPlanPeriod, error) {
+	if appID == "" {
+		return nil, types.ErrMissingID
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.After(to) {
+		return nil, types.ErrInvalidTimeRange
+	}
+
+	snapshot := d.current()
+	for _, app := range snapshot.Applications {
+		if app.ID != appID || app.CreatedAt.After(to) {
+			continue
+		}
+
+		limit := app.Limit
+		limit.PayPlan.Limit = snapshot.payPlanLimit(limit.PayPlan.Type)
+
+		return []*types.AppPlanPeriod{{
+			ApplicationID: app.ID,
+			Limit:         limit,
+			EffectiveFrom: app.CreatedAt,
+		}}, nil
+	}
+
+	return nil, nil
+}
+
+/* ReadAppPlanAt returns the pay plan period of an Application in effect at a time, or types.ErrNotFound if there is none */
+func (d *FileDriver) ReadAppPlanAt(ctx context.Context, appID string, at time.Time) (*types.AppPlanPeriod, error) {
+	periods, err := d.ReadAppPlanHistory(ctx, appID, at, at)
+	if err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return periods[0], nil
+}
+
+func (s *Snapshot) payPlanLimit(payPlanType types.PayPlanType) int {
+	for _, payPlan := range s.PayPlans {
+		if payPlan.Type == payPlanType {
+			return payPlan.Limit
+		}
+	}
+
+	return 0
+}
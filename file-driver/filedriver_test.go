@@ -0,0 +1,171 @@
+package filedriver
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/driver"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+var (
+	testCtx = context.Background()
+
+	_ driver.Reader = &FileDriver{}
+)
+
+func testSnapshotJSON(t *testing.T, snapshot *Snapshot) []byte {
+	t.Helper()
+
+	content, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+
+	return content
+}
+
+/* newTestFileDriver writes the snapshot to a temporary file and returns a FileDriver polling it every millisecond */
+func newTestFileDriver(t *testing.T, snapshot *Snapshot) (*FileDriver, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(path, testSnapshotJSON(t, snapshot), 0o600))
+
+	d, err := NewFileDriver(path, time.Millisecond, func(err error) { t.Log(err) })
+	require.NoError(t, err)
+	t.Cleanup(d.CloseListener)
+
+	return d, path
+}
+
+/* receiveNotifications returns the next n notifications and fails if more were sent */
+func receiveNotifications(t *testing.T, d *FileDriver, n int) []*types.Notification {
+	t.Helper()
+
+	var notifications []*types.Notification
+	for len(notifications) < n {
+		select {
+		case notification := <-d.NotificationChannel():
+			notifications = append(notifications, notification)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d notifications, expected %d", len(notifications), n)
+		}
+	}
+
+	select {
+	case notification := <-d.NotificationChannel():
+		t.Fatalf("unexpected notification on table %s", notification.Table)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	return notifications
+}
+
+func testSnapshot(t *testing.T) *Snapshot {
+	t.Helper()
+
+	snapshot, err := ParseSnapshot("snapshot.yaml", []byte(testSnapshotYAML))
+	require.NoError(t, err)
+
+	return snapshot
+}
+
+func Test_NewFileDriver(t *testing.T) {
+	c := require.New(t)
+
+	_, err := NewFileDriver(filepath.Join(t.TempDir(), "missing.json"), 0, nil)
+	c.ErrorIs(err, os.ErrNotExist)
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	c.NoError(os.WriteFile(path, []byte(`{"apps": []}`), 0o600))
+	_, err = NewFileDriver(path, 0, nil)
+	c.ErrorIs(err, ErrInvalidSnapshot)
+}
+
+func Test_ReadSnapshot(t *testing.T) {
+	c := require.New(t)
+
+	d, _ := newTestFileDriver(t, testSnapshot(t))
+
+	apps, err := d.ReadApplications(testCtx)
+	c.NoError(err)
+	c.Len(apps, 1)
+	c.Equal("app_1", apps[0].ID)
+	c.Equal(250000, apps[0].Limit.PayPlan.Limit)
+
+	loadBalancers, err := d.ReadLoadBalancers(testCtx)
+	c.NoError(err)
+	c.Len(loadBalancers, 1)
+	c.Equal([]string{"app_1"}, loadBalancers[0].ApplicationIDs)
+
+	userRoles, err := d.ReadUserRoles(testCtx)
+	c.NoError(err)
+	c.Equal(map[string]map[string][]types.PermissionsEnum{
+		"user_1": {"lb_1": {types.ReadEndpoint, types.WriteEndpoint}},
+	}, userRoles)
+
+	periods, err := d.ReadAppPlanHistory(testCtx, "app_1", time.Time{}, time.Time{})
+	c.NoError(err)
+	c.Len(periods, 1)
+	c.Equal(types.FreetierV0, periods[0].Limit.PayPlan.Type)
+
+	_, err = d.ReadAppPlanHistory(testCtx, "", time.Time{}, time.Time{})
+	c.ErrorIs(err, types.ErrMissingID)
+
+	_, err = d.ReadAppPlanAt(testCtx, "app_2", time.Now())
+	c.ErrorIs(err, types.ErrNotFound)
+}
+
+func Test_Reload(t *testing.T) {
+	c := require.New(t)
+
+	snapshot := testSnapshot(t)
+	snapshot.LoadBalancers[0].Limit = &types.LBLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}}
+	d, path := newTestFileDriver(t, snapshot)
+
+	snapshot = testSnapshot(t)
+	snapshot.Applications[0].Name = "pokt_app_456"
+	snapshot.Applications = append(snapshot.Applications, &types.Application{
+		ID:     "app_2",
+		UserID: "user_1",
+		Status: types.InService,
+		Limit:  types.AppLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}},
+	})
+	c.NoError(os.WriteFile(path, testSnapshotJSON(t, snapshot), 0o600))
+
+	notifications := receiveNotifications(t, d, 6)
+	c.Equal(&types.Notification{
+		Table:  types.TableApplications,
+		Action: types.ActionUpdate,
+		Data: &types.Application{
+			ID:     "app_1",
+			UserID: "user_1",
+			Name:   "pokt_app_456",
+			Status: types.InService,
+		},
+	}, notifications[0])
+	for i, table := range []types.Table{types.TableApplications, types.TableAppLimits, types.TableGatewaySettings, types.TableNotificationSettings} {
+		c.Equal(table, notifications[i+1].Table)
+		c.Equal(types.ActionInsert, notifications[i+1].Action)
+	}
+	// Deleted rows are notified after the changed ones
+	c.Equal(types.TableLbLimits, notifications[5].Table)
+	c.Equal(types.ActionDelete, notifications[5].Action)
+
+	apps, err := d.ReadApplications(testCtx)
+	c.NoError(err)
+	c.Len(apps, 2)
+	c.Equal("pokt_app_456", apps[0].Name)
+
+	// Invalid changes are reported and the loaded Snapshot is kept
+	c.NoError(os.WriteFile(path, []byte(`{"applications": [`), 0o600))
+	receiveNotifications(t, d, 0)
+
+	apps, err = d.ReadApplications(testCtx)
+	c.NoError(err)
+	c.Len(apps, 2)
+}
@@ -0,0 +1,235 @@
+package filedriver
+
+import (
+	"reflect"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+type (
+	/* row is a database row of a Snapshot as the PostgresDriver's listener parses it */
+	row struct {
+		rowKey
+		data types.SavedOnDB
+	}
+	// rowKey identifies a row, key is unique within its table
+	rowKey struct {
+		table types.Table
+		key   string
+	}
+)
+
+func newRow(table types.Table, key string, data types.SavedOnDB) row {
+	return row{rowKey: rowKey{table: table, key: key}, data: data}
+}
+
+/*
+diff returns the notifications the database triggers would send to go from one Snapshot to the other, in the order
+the PostgresDriver writes their rows. Applications, LoadBalancers and Blockchains are never deleted from the database,
+so leaving them out of a Snapshot sends no Notification.
+*/
+func diff(from, to *Snapshot) []*types.Notification {
+	fromRows := make(map[rowKey]types.SavedOnDB)
+	for _, r := range snapshotRows(from) {
+		fromRows[r.rowKey] = r.data
+	}
+
+	var notifications []*types.Notification
+	notify := func(action types.Action, table types.Table, data types.SavedOnDB) {
+		if types.NotifiedActions[table][action] {
+			notifications = append(notifications, &types.Notification{Table: table, Action: action, Data: data})
+		}
+	}
+
+	for _, r := range snapshotRows(to) {
+		previous, ok := fromRows[r.rowKey]
+		delete(fromRows, r.rowKey)
+		switch {
+		case !ok:
+			notify(types.ActionInsert, r.table, r.data)
+		case !reflect.DeepEqual(previous, r.data):
+			notify(types.ActionUpdate, r.table, r.data)
+		}
+	}
+
+	for _, r := range snapshotRows(from) {
+		if _, removed := fromRows[r.rowKey]; removed {
+			notify(types.ActionDelete, r.table, r.data)
+		}
+	}
+
+	return notifications
+}
+
+/* snapshotRows returns the rows of every entity of the Snapshot, each entity's rows in the order the PostgresDriver writes them */
+func snapshotRows(s *Snapshot) []row {
+	if s == nil {
+		return nil
+	}
+
+	var rows []row
+	for _, app := range s.Applications {
+		rows = append(rows, applicationRows(app)...)
+	}
+	for _, lb := range s.LoadBalancers {
+		rows = append(rows, loadBalancerRows(lb)...)
+	}
+	for _, blockchain := range s.Blockchains {
+		rows = append(rows, blockchainRows(blockchain)...)
+	}
+
+	return rows
+}
+
+func applicationRows(app *types.Application) []row {
+	id := app.ID
+
+	rows := []row{
+		newRow(types.TableApplications, id, &types.Application{
+			ID:                 id,
+			UserID:             app.UserID,
+			Name:               app.Name,
+			ContactEmail:       app.ContactEmail,
+			Description:        app.Description,
+			Owner:              app.Owner,
+			URL:                app.URL,
+			Status:             app.Status,
+			CreatedAt:          app.CreatedAt,
+			UpdatedAt:          app.UpdatedAt,
+			FirstDateSurpassed: app.FirstDateSurpassed,
+			Dummy:              app.Dummy,
+			Version:            app.Version,
+		}),
+		newRow(types.TableAppLimits, id, &types.AppLimit{
+			ID:          id,
+			PayPlan:     types.PayPlan{Type: app.Limit.PayPlan.Type},
+			CustomLimit: app.Limit.CustomLimit,
+		}),
+	}
+
+	aat := app.GatewayAAT
+	if aat != (types.GatewayAAT{}) {
+		aat.ID = id
+		rows = append(rows, newRow(types.TableGatewayAAT, id, &aat))
+	}
+
+	settings := app.GatewaySettings
+	rows = append(rows, newRow(types.TableGatewaySettings, id, &types.GatewaySettings{
+		ID:                   id,
+		SecretKey:            settings.SecretKey,
+		SecretKeyRequired:    settings.SecretKeyRequired,
+		WhitelistOrigins:     settings.WhitelistOrigins,
+		WhitelistUserAgents:  settings.WhitelistUserAgents,
+		WhitelistBlockchains: settings.WhitelistBlockchains,
+	}))
+	for _, secretKey := range settings.SecretKeys {
+		secretKey := secretKey
+		secretKey.ID = id
+		rows = append(rows, newRow(types.TableSecretKeys, id+"/"+secretKey.Name, &secretKey))
+	}
+	for _, contract := range settings.WhitelistContracts {
+		contract := contract
+		contract.ID = id
+		rows = append(rows, newRow(types.TableWhitelistContracts, id+"/"+contract.BlockchainID, &contract))
+	}
+	for _, method := range settings.WhitelistMethods {
+		method := method
+		method.ID = id
+		rows = append(rows, newRow(types.TableWhitelistMethods, id+"/"+method.BlockchainID, &method))
+	}
+
+	notificationSettings := app.NotificationSettings
+	notificationSettings.ID = id
+	rows = append(rows, newRow(types.TableNotificationSettings, id, &notificationSettings))
+
+	return rows
+}
+
+func loadBalancerRows(lb *types.LoadBalancer) []row {
+	id := lb.ID
+
+	rows := []row{
+		newRow(types.TableLoadBalancers, id, &types.LoadBalancer{
+			ID:                id,
+			Name:              lb.Name,
+			UserID:            lb.UserID,
+			RequestTimeout:    lb.RequestTimeout,
+			Gigastake:         lb.Gigastake,
+			GigastakeRedirect: lb.GigastakeRedirect,
+			Version:           lb.Version,
+			CreatedAt:         lb.CreatedAt,
+			UpdatedAt:         lb.UpdatedAt,
+		}),
+	}
+
+	stickiness := lb.StickyOptions
+	if stickiness.Duration != "" || len(stickiness.StickyOrigins) > 0 || stickiness.StickyMax != 0 || stickiness.Stickiness {
+		stickiness.ID = id
+		rows = append(rows, newRow(types.TableStickinessOptions, id, &stickiness))
+	}
+
+	if lb.Limit != nil {
+		rows = append(rows, newRow(types.TableLbLimits, id, &types.LBLimit{
+			ID:          id,
+			PayPlan:     types.PayPlan{Type: lb.Limit.PayPlan.Type},
+			CustomLimit: lb.Limit.CustomLimit,
+		}))
+	}
+
+	for _, user := range lb.Users {
+		user := user
+		key := user.UserID
+		if key == "" {
+			// Invited users who have not signed up are only known by their email
+			key = user.Email
+		}
+
+		user.ID = id
+		rows = append(rows, newRow(types.TableUserAccess, id+"/"+key, &user))
+	}
+
+	for _, appID := range lb.ApplicationIDs {
+		rows = append(rows, newRow(types.TableLbApps, id+"/"+appID, &types.LbApp{LbID: id, AppID: appID}))
+	}
+
+	return rows
+}
+
+func blockchainRows(blockchain *types.Blockchain) []row {
+	id := blockchain.ID
+
+	rows := []row{
+		newRow(types.TableBlockchains, id, &types.Blockchain{
+			ID:                id,
+			Altruist:          blockchain.Altruist,
+			Blockchain:        blockchain.Blockchain,
+			ChainID:           blockchain.ChainID,
+			ChainIDCheck:      blockchain.ChainIDCheck,
+			Path:              blockchain.Path,
+			Description:       blockchain.Description,
+			EnforceResult:     blockchain.EnforceResult,
+			Network:           blockchain.Network,
+			Ticker:            blockchain.Ticker,
+			BlockchainAliases: blockchain.BlockchainAliases,
+			LogLimitBlocks:    blockchain.LogLimitBlocks,
+			RequestTimeout:    blockchain.RequestTimeout,
+			Active:            blockchain.Active,
+			CreatedAt:         blockchain.CreatedAt,
+			UpdatedAt:         blockchain.UpdatedAt,
+		}),
+	}
+
+	options := blockchain.SyncCheckOptions
+	if blockchain.SyncCheck != "" || options.Body != "" || options.Path != "" || options.ResultKey != "" || options.Allowance != 0 {
+		options.BlockchainID = id
+		rows = append(rows, newRow(types.TableSyncCheckOptions, id, &options))
+	}
+
+	for _, redirect := range blockchain.Redirects {
+		redirect := redirect
+		redirect.BlockchainID = id
+		rows = append(rows, newRow(types.TableRedirects, id+"/"+redirect.Domain, &redirect))
+	}
+
+	return rows
+}
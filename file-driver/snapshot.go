@@ -0,0 +1,147 @@
+package filedriver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/vishruthsk/portal-db-main/types"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidSnapshot = errors.New("error: snapshot is invalid")
+
+/*
+Snapshot is the content of a snapshot file, every entity is in the JSON format of its type.
+UserRoles maps each role name to its permissions like the user_roles table, the permissions of each user are
+read from the Users of the LoadBalancers.
+*/
+type Snapshot struct {
+	PayPlans      []*types.PayPlan                           `json:"payPlans"`
+	UserRoles     map[types.RoleName][]types.PermissionsEnum `json:"userRoles"`
+	Applications  []*types.Application                       `json:"applications"`
+	LoadBalancers []*types.LoadBalancer                      `json:"loadBalancers"`
+	Blockchains   []*types.Blockchain                        `json:"blockchains"`
+}
+
+/* ParseSnapshot parses a snapshot file's content, it is read as YAML if the path ends with .yaml or .yml and as JSON otherwise */
+func ParseSnapshot(path string, content []byte) (*Snapshot, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var document any
+		err := yaml.Unmarshal(content, &document)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSnapshot, err)
+		}
+
+		// The types only have JSON tags, so the YAML document is decoded as the JSON one it is equivalent to
+		content, err = json.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSnapshot, err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	var snapshot Snapshot
+	err := decoder.Decode(&snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSnapshot, err)
+	}
+
+	err = snapshot.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+/*
+Validate returns an error wrapping ErrInvalidSnapshot if the Snapshot could not be stored in the database:
+missing or duplicate IDs, invalid entities or references to Applications, pay plans or roles that are not in it
+*/
+func (s *Snapshot) Validate() error {
+	payPlans := make(map[types.PayPlanType]bool, len(s.PayPlans))
+	for _, payPlan := range s.PayPlans {
+		if payPlan == nil {
+			return fmt.Errorf("%w: pay plan is null", ErrInvalidSnapshot)
+		}
+
+		err := payPlan.Validate()
+		if err != nil {
+			return fmt.Errorf("%w: pay plan %q: %s", ErrInvalidSnapshot, payPlan.Type, err)
+		}
+		if payPlans[payPlan.Type] {
+			return fmt.Errorf("%w: duplicate pay plan %q", ErrInvalidSnapshot, payPlan.Type)
+		}
+		payPlans[payPlan.Type] = true
+	}
+
+	apps := make(map[string]bool, len(s.Applications))
+	for _, app := range s.Applications {
+		switch {
+		case app == nil || app.ID == "":
+			return fmt.Errorf("%w: application is missing its ID", ErrInvalidSnapshot)
+		case apps[app.ID]:
+			return fmt.Errorf("%w: duplicate application %q", ErrInvalidSnapshot, app.ID)
+		case !types.ValidAppStatuses[app.Status]:
+			return fmt.Errorf("%w: application %q: %s", ErrInvalidSnapshot, app.ID, types.ErrInvalidAppStatus)
+		case !payPlans[app.Limit.PayPlan.Type]:
+			return fmt.Errorf("%w: application %q: unknown pay plan %q", ErrInvalidSnapshot, app.ID, app.Limit.PayPlan.Type)
+		}
+		apps[app.ID] = true
+	}
+
+	loadBalancers := make(map[string]bool, len(s.LoadBalancers))
+	for _, lb := range s.LoadBalancers {
+		switch {
+		case lb == nil || lb.ID == "":
+			return fmt.Errorf("%w: load balancer is missing its ID", ErrInvalidSnapshot)
+		case loadBalancers[lb.ID]:
+			return fmt.Errorf("%w: duplicate load balancer %q", ErrInvalidSnapshot, lb.ID)
+		}
+		loadBalancers[lb.ID] = true
+
+		err := lb.Validate()
+		if err != nil {
+			return fmt.Errorf("%w: load balancer %q: %s", ErrInvalidSnapshot, lb.ID, err)
+		}
+		if lb.Limit != nil && !payPlans[lb.Limit.PayPlan.Type] {
+			return fmt.Errorf("%w: load balancer %q: unknown pay plan %q", ErrInvalidSnapshot, lb.ID, lb.Limit.PayPlan.Type)
+		}
+
+		for _, appID := range lb.ApplicationIDs {
+			if !apps[appID] {
+				return fmt.Errorf("%w: load balancer %q: unknown application %q", ErrInvalidSnapshot, lb.ID, appID)
+			}
+		}
+		for _, user := range lb.Users {
+			if _, ok := s.UserRoles[user.RoleName]; !ok {
+				return fmt.Errorf("%w: load balancer %q: unknown role %q", ErrInvalidSnapshot, lb.ID, user.RoleName)
+			}
+		}
+	}
+
+	blockchains := make(map[string]bool, len(s.Blockchains))
+	for _, blockchain := range s.Blockchains {
+		if blockchain == nil {
+			return fmt.Errorf("%w: blockchain is null", ErrInvalidSnapshot)
+		}
+
+		err := blockchain.Validate()
+		if err != nil {
+			return fmt.Errorf("%w: blockchain %q: %s", ErrInvalidSnapshot, blockchain.ID, err)
+		}
+		if blockchains[blockchain.ID] {
+			return fmt.Errorf("%w: duplicate blockchain %q", ErrInvalidSnapshot, blockchain.ID)
+		}
+		blockchains[blockchain.ID] = true
+	}
+
+	return nil
+}
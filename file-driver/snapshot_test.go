@@ -0,0 +1,97 @@
+package filedriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const testSnapshotYAML = `
+payPlans:
+  - planType: FREETIER_V0
+    dailyLimit: 250000
+userRoles:
+  OWNER: [read:endpoint, write:endpoint]
+applications:
+  - id: app_1
+    name: pokt_app_123
+    userID: user_1
+    status: IN_SERVICE
+    limit:
+      payPlan:
+        planType: FREETIER_V0
+loadBalancers:
+  - id: lb_1
+    name: pokt_lb_123
+    userID: user_1
+    applicationIDs: [app_1]
+    users:
+      - userID: user_1
+        email: owner@test.com
+        roleName: OWNER
+        accepted: true
+`
+
+func Test_ParseSnapshot(t *testing.T) {
+	c := require.New(t)
+
+	fromYAML, err := ParseSnapshot("snapshot.yaml", []byte(testSnapshotYAML))
+	c.NoError(err)
+	c.Len(fromYAML.Applications, 1)
+	c.Equal(types.InService, fromYAML.Applications[0].Status)
+	c.Equal([]string{"app_1"}, fromYAML.LoadBalancers[0].ApplicationIDs)
+	c.Equal([]types.PermissionsEnum{types.ReadEndpoint, types.WriteEndpoint}, fromYAML.UserRoles[types.RoleOwner])
+
+	fromJSON, err := ParseSnapshot("snapshot.json", testSnapshotJSON(t, fromYAML))
+	c.NoError(err)
+	c.Equal(fromYAML, fromJSON)
+}
+
+func Test_ParseSnapshot_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+	}{
+		{
+			name:    "Should fail on malformed JSON",
+			path:    "snapshot.json",
+			content: `{"applications": [`,
+		},
+		{
+			name:    "Should fail on unknown fields",
+			path:    "snapshot.json",
+			content: `{"apps": []}`,
+		},
+		{
+			name:    "Should fail on an application without ID",
+			path:    "snapshot.json",
+			content: `{"payPlans": [{"planType": "FREETIER_V0"}], "applications": [{"status": "IN_SERVICE", "limit": {"payPlan": {"planType": "FREETIER_V0"}}}]}`,
+		},
+		{
+			name:    "Should fail on an unknown pay plan",
+			path:    "snapshot.json",
+			content: `{"applications": [{"id": "app_1", "status": "IN_SERVICE", "limit": {"payPlan": {"planType": "FREETIER_V0"}}}]}`,
+		},
+		{
+			name:    "Should fail on a load balancer with an unknown application",
+			path:    "snapshot.yml",
+			content: "loadBalancers:\n  - id: lb_1\n    userID: user_1\n    applicationIDs: [app_2]\n",
+		},
+		{
+			name:    "Should fail on a duplicate blockchain",
+			path:    "snapshot.json",
+			content: `{"blockchains": [{"id": "0001", "altruist": "https://test.com"}, {"id": "0001", "altruist": "https://test.com"}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			_, err := ParseSnapshot(test.path, []byte(test.content))
+			c.ErrorIs(err, ErrInvalidSnapshot)
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* Codes of the errors that are not returned by a driver */
+const (
+	CodeInvalidBody      = "invalid_body"
+	CodeInvalidParameter = "invalid_parameter"
+	CodeUnauthorized     = "unauthorized"
+	CodeRouteNotFound    = "route_not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeValidationFailed = "validation_failed"
+	CodeTimeout          = "timeout"
+	CodeInternal         = "internal"
+)
+
+var (
+	ErrInvalidBody      = errors.New("error: request body is invalid")
+	ErrInvalidParameter = errors.New("error: query parameter is invalid")
+	ErrUnauthorized     = errors.New("error: API key is missing or invalid")
+	ErrRouteNotFound    = errors.New("error: route not found")
+	ErrMethodNotAllowed = errors.New("error: method not allowed")
+	ErrInternal         = errors.New("error: internal server error")
+)
+
+type (
+	/*
+		ErrorResponse is the body of every response that is not successful.
+		Code identifies the error a driver returned so a client can return the same one, Constraint and TimeoutKind
+		are only set for a types.ConstraintError and a types.TimeoutError, Violations for a types.ValidationError
+	*/
+	ErrorResponse struct {
+		Code        string              `json:"code"`
+		Message     string              `json:"message"`
+		Constraint  string              `json:"constraint,omitempty"`
+		TimeoutKind types.TimeoutKind   `json:"timeoutKind,omitempty"`
+		Violations  []ViolationResponse `json:"violations,omitempty"`
+	}
+	/* ViolationResponse is a types.Violation, Error is the code of the error describing it */
+	ViolationResponse struct {
+		Field   string `json:"field"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	errorCode struct {
+		code   string
+		err    error
+		status int
+	}
+
+	/* remoteError is an error read from an ErrorResponse whose message differs from the error it wraps */
+	remoteError struct {
+		message string
+		err     error
+	}
+)
+
+/* errorCodes lists the errors drivers return with their code and status, an error is matched against them in order */
+var errorCodes = []errorCode{
+	{code: "not_found", err: types.ErrNotFound, status: http.StatusNotFound},
+	{code: "invitation_not_found", err: types.ErrInvitationNotFound, status: http.StatusNotFound},
+	{code: "secret_key_not_found", err: types.ErrSecretKeyNotFound, status: http.StatusNotFound},
+
+	{code: "conflict", err: types.ErrConflict, status: http.StatusConflict},
+	{code: "already_exists", err: types.ErrAlreadyExists, status: http.StatusConflict},
+	{code: "reference_violation", err: types.ErrReferenceViolation, status: http.StatusConflict},
+	{code: "invalid_status_transition", err: types.ErrInvalidStatusTransition, status: http.StatusConflict},
+	{code: "user_is_not_owner", err: types.ErrUserIsNotOwner, status: http.StatusConflict},
+	{code: "new_owner_not_accepted", err: types.ErrNewOwnerNotAccepted, status: http.StatusConflict},
+	{code: "same_owner", err: types.ErrSameOwner, status: http.StatusConflict},
+	{code: "cannot_set_to_owner", err: types.ErrCannotSetToOwner, status: http.StatusConflict},
+
+	{code: "invitation_expired", err: types.ErrInvitationExpired, status: http.StatusGone},
+	{code: "invitation_user_mismatch", err: types.ErrInvitationUserMismatch, status: http.StatusForbidden},
+
+	{code: "missing_id", err: types.ErrMissingID, status: http.StatusUnprocessableEntity},
+	{code: "no_fields_to_update", err: types.ErrNoFieldsToUpdate, status: http.StatusUnprocessableEntity},
+	{code: "lb_must_have_user", err: types.ErrLBMustHaveUser, status: http.StatusUnprocessableEntity},
+	{code: "user_input_missing_field", err: types.ErrUserInputIsMissingField, status: http.StatusUnprocessableEntity},
+	{code: "missing_secret_key_name", err: types.ErrMissingSecretKeyName, status: http.StatusUnprocessableEntity},
+	{code: "negative_overlap", err: types.ErrNegativeOverlap, status: http.StatusUnprocessableEntity},
+	{code: "negative_relays", err: types.ErrNegativeRelays, status: http.StatusUnprocessableEntity},
+	{code: "invalid_time_range", err: types.ErrInvalidTimeRange, status: http.StatusUnprocessableEntity},
+	{code: "missing_value", err: types.ErrMissingValue, status: http.StatusUnprocessableEntity},
+	{code: "negative_value", err: types.ErrNegativeValue, status: http.StatusUnprocessableEntity},
+	{code: "invalid_app_status", err: types.ErrInvalidAppStatus, status: http.StatusUnprocessableEntity},
+	{code: "invalid_pay_plan_type", err: types.ErrInvalidPayPlanType, status: http.StatusUnprocessableEntity},
+	{code: "not_enterprise_plan", err: types.ErrNotEnterprisePlan, status: http.StatusUnprocessableEntity},
+	{code: "enterprise_plan_needs_custom_limit", err: types.ErrEnterprisePlanNeedsCustomLimit, status: http.StatusUnprocessableEntity},
+	{code: "secret_key_is_generated", err: types.ErrSecretKeyIsGenerated, status: http.StatusUnprocessableEntity},
+	{code: "invalid_sticky_duration", err: types.ErrInvalidStickyDuration, status: http.StatusUnprocessableEntity},
+	{code: "limit_set_and_removed", err: types.ErrLimitSetAndRemoved, status: http.StatusUnprocessableEntity},
+	{code: "invalid_altruist_url", err: types.ErrInvalidAltruistURL, status: http.StatusUnprocessableEntity},
+	{code: "duplicate_blockchain_alias", err: types.ErrDuplicateBlockchainAlias, status: http.StatusUnprocessableEntity},
+	{code: "invalid_sync_check_body", err: types.ErrInvalidSyncCheckBody, status: http.StatusUnprocessableEntity},
+	{code: "invalid_redirect_domain", err: types.ErrInvalidRedirectDomain, status: http.StatusUnprocessableEntity},
+	{code: "invalid_whitelist_origin", err: types.ErrInvalidWhitelistOrigin, status: http.StatusUnprocessableEntity},
+	{code: "invalid_whitelist_user_agent", err: types.ErrInvalidWhitelistUserAgent, status: http.StatusUnprocessableEntity},
+	{code: "invalid_contract_address", err: types.ErrInvalidContractAddress, status: http.StatusUnprocessableEntity},
+	{code: "invalid_contract_checksum", err: types.ErrInvalidContractChecksum, status: http.StatusUnprocessableEntity},
+	{code: "invalid_method_name", err: types.ErrInvalidMethodName, status: http.StatusUnprocessableEntity},
+	{code: "unknown_blockchain", err: types.ErrUnknownBlockchain, status: http.StatusUnprocessableEntity},
+
+	{code: CodeInvalidBody, err: ErrInvalidBody, status: http.StatusBadRequest},
+	{code: CodeInvalidParameter, err: ErrInvalidParameter, status: http.StatusBadRequest},
+	{code: CodeUnauthorized, err: ErrUnauthorized, status: http.StatusUnauthorized},
+	{code: CodeRouteNotFound, err: ErrRouteNotFound, status: http.StatusNotFound},
+	{code: CodeMethodNotAllowed, err: ErrMethodNotAllowed, status: http.StatusMethodNotAllowed},
+}
+
+func (e *remoteError) Error() string {
+	return e.message
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.err
+}
+
+/*
+NewErrorResponse returns the ErrorResponse and status reporting err.
+Errors that are not listed are internal, their message is not sent as it may hold details of the database.
+*/
+func NewErrorResponse(err error) (*ErrorResponse, int) {
+	var validationErr *types.ValidationError
+	if errors.As(err, &validationErr) {
+		response := &ErrorResponse{Code: CodeValidationFailed, Message: validationErr.Error()}
+		for _, violation := range validationErr.Violations {
+			response.Violations = append(response.Violations, ViolationResponse{
+				Field:   violation.Field,
+				Code:    violation.Code,
+				Message: violation.Message,
+				Error:   codeOf(violation.Err),
+			})
+		}
+
+		return response, http.StatusUnprocessableEntity
+	}
+
+	var timeoutErr *types.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return &ErrorResponse{Code: CodeTimeout, Message: err.Error(), TimeoutKind: timeoutErr.Kind}, http.StatusGatewayTimeout
+	}
+
+	var constraintErr *types.ConstraintError
+	if errors.As(err, &constraintErr) {
+		response := &ErrorResponse{Code: codeOf(constraintErr.Kind), Message: err.Error(), Constraint: constraintErr.Constraint}
+		return response, http.StatusConflict
+	}
+
+	for _, errorCode := range errorCodes {
+		if errors.Is(err, errorCode.err) {
+			return &ErrorResponse{Code: errorCode.code, Message: err.Error()}, errorCode.status
+		}
+	}
+
+	return &ErrorResponse{Code: CodeInternal, Message: ErrInternal.Error()}, http.StatusInternalServerError
+}
+
+/*
+Err returns the error the ErrorResponse reports, matching the one the driver returned with errors.Is and errors.As.
+Errors whose code is unknown are returned with their message only.
+*/
+func (r *ErrorResponse) Err() error {
+	switch r.Code {
+	case CodeValidationFailed:
+		validationErr := &types.ValidationError{}
+		for _, violation := range r.Violations {
+			validationErr.Violations = append(validationErr.Violations, types.Violation{
+				Field:   violation.Field,
+				Code:    violation.Code,
+				Message: violation.Message,
+				Err:     errorOf(violation.Error, violation.Message),
+			})
+		}
+
+		return validationErr
+	case CodeTimeout:
+		// The message is the one of the TimeoutError, only the one of the error it wraps is kept
+		message := strings.TrimPrefix(r.Message, (&types.TimeoutError{Kind: r.TimeoutKind, Err: errors.New("")}).Error())
+		return &types.TimeoutError{Kind: r.TimeoutKind, Err: errors.New(message)}
+	case CodeInternal:
+		return ErrInternal
+	}
+
+	kind := errorWithCode(r.Code)
+	if r.Constraint != "" && (kind == types.ErrAlreadyExists || kind == types.ErrReferenceViolation) {
+		return &types.ConstraintError{Kind: kind, Constraint: r.Constraint, Err: errors.New(r.Message)}
+	}
+
+	return errorOf(r.Code, r.Message)
+}
+
+/* codeOf returns the code of the first listed error err matches, or an empty string if there is none */
+func codeOf(err error) string {
+	for _, errorCode := range errorCodes {
+		if errors.Is(err, errorCode.err) {
+			return errorCode.code
+		}
+	}
+
+	return ""
+}
+
+/* errorWithCode returns the listed error with the code, or nil if there is none */
+func errorWithCode(code string) error {
+	for _, errorCode := range errorCodes {
+		if errorCode.code == code {
+			return errorCode.err
+		}
+	}
+
+	return nil
+}
+
+/* errorOf returns the listed error with the code, wrapped with the message if it differs from the error's own */
+func errorOf(code, message string) error {
+	err := errorWithCode(code)
+	switch {
+	case err == nil:
+		return errors.New(message)
+	case err.Error() == message:
+		return err
+	default:
+		return &remoteError{message: message, err: err}
+	}
+}
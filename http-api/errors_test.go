@@ -0,0 +1,110 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func Test_ErrorResponse(t *testing.T) {
+	validationErr := &types.ValidationError{}
+	validationErr.Add("status", types.ViolationInvalid, types.ErrInvalidAppStatus)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+		expectedIs     []error
+	}{
+		{
+			name:           "Should report a not found error",
+			err:            types.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "not_found",
+			expectedIs:     []error{types.ErrNotFound},
+		},
+		{
+			name:           "Should report a wrapped error with its message",
+			err:            fmt.Errorf("%w: IN_SERVICE to DECOMISSIONED", types.ErrInvalidStatusTransition),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "invalid_status_transition",
+			expectedIs:     []error{types.ErrInvalidStatusTransition},
+		},
+		{
+			name:           "Should report the violations of a validation error",
+			err:            validationErr,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   CodeValidationFailed,
+			expectedIs:     []error{types.ErrInvalidAppStatus},
+		},
+		{
+			name:           "Should report a constraint error",
+			err:            &types.ConstraintError{Kind: types.ErrAlreadyExists, Constraint: "blockchains_pkey", Err: errors.New("duplicate key")},
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "already_exists",
+			expectedIs:     []error{types.ErrAlreadyExists},
+		},
+		{
+			name:           "Should report a timeout error",
+			err:            &types.TimeoutError{Kind: types.TimeoutLock, Err: errors.New("lock not available")},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   CodeTimeout,
+			expectedIs:     []error{types.ErrTimeout},
+		},
+		{
+			name:           "Should report a driver error",
+			err:            types.ErrInvitationExpired,
+			expectedStatus: http.StatusGone,
+			expectedCode:   "invitation_expired",
+			expectedIs:     []error{types.ErrInvitationExpired},
+		},
+		{
+			name:           "Should not report the message of an internal error",
+			err:            errors.New("pq: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   CodeInternal,
+			expectedIs:     []error{ErrInternal},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			response, status := NewErrorResponse(test.err)
+			c.Equal(test.expectedStatus, status)
+			c.Equal(test.expectedCode, response.Code)
+
+			err := response.Err()
+			for _, expected := range test.expectedIs {
+				c.ErrorIs(err, expected)
+			}
+			if test.expectedCode != CodeInternal {
+				c.Equal(test.err.Error(), err.Error())
+			}
+		})
+	}
+}
+
+func Test_ErrorResponse_Types(t *testing.T) {
+	c := require.New(t)
+
+	response, _ := NewErrorResponse(&types.ConstraintError{Kind: types.ErrReferenceViolation, Constraint: "lb_apps_app_id_fkey"})
+	var constraintErr *types.ConstraintError
+	c.ErrorAs(response.Err(), &constraintErr)
+	c.Equal("lb_apps_app_id_fkey", constraintErr.Constraint)
+
+	response, _ = NewErrorResponse(&types.TimeoutError{Kind: types.TimeoutStatement, Err: errors.New("canceling statement")})
+	var timeoutErr *types.TimeoutError
+	c.ErrorAs(response.Err(), &timeoutErr)
+	c.Equal(types.TimeoutStatement, timeoutErr.Kind)
+
+	// Errors sent with their own message are the same error once read
+	response, _ = NewErrorResponse(types.ErrConflict)
+	c.Equal(types.ErrConflict, response.Err())
+}
@@ -0,0 +1,15 @@
+/*
+Package httpapi holds the wire format shared by the httpserver package serving a driver over HTTP and the httpclient
+package calling it: headers, request and response bodies, error codes and notification events.
+*/
+package httpapi
+
+/* Headers read from every request */
+const (
+	// APIKeyHeader holds one of the API keys the Server was created with
+	APIKeyHeader = "X-API-Key"
+	// ActorHeader holds the ID of the user making the change, it is recorded in the audit log
+	ActorHeader = "X-Actor-ID"
+	// StatusOverrideHeader set to true allows any status transition, like types.WithStatusOverride, for admin API keys only
+	StatusOverrideHeader = "X-Status-Override"
+)
@@ -0,0 +1,85 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* Server-sent events of the notifications route */
+const (
+	// EventNotification holds a NotificationEvent, its ID can be sent back in the Last-Event-ID header to resume after it
+	EventNotification = "notification"
+	// EventGap is sent first when the notifications after the Last-Event-ID are no longer held or were sent by
+	// another server process, some were missed
+	EventGap = "gap"
+)
+
+var ErrUnknownTable = errors.New("error: notification table is unknown")
+
+/* NotificationEvent is the JSON format of a types.Notification */
+type NotificationEvent struct {
+	Table  types.Table     `json:"table"`
+	Action types.Action    `json:"action"`
+	Data   json.RawMessage `json:"data"`
+}
+
+/* ParseNotification parses the data of an EventNotification into the types.Notification it was sent for */
+func ParseNotification(data []byte) (*types.Notification, error) {
+	var notificationEvent NotificationEvent
+	err := json.Unmarshal(data, &notificationEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved types.SavedOnDB
+	switch notificationEvent.Table {
+	case types.TableLoadBalancers:
+		saved = &types.LoadBalancer{}
+	case types.TableStickinessOptions:
+		saved = &types.StickyOptions{}
+	case types.TableUserAccess:
+		saved = &types.UserAccess{}
+	case types.TableLbLimits:
+		saved = &types.LBLimit{}
+	case types.TableLbApps:
+		saved = &types.LbApp{}
+	case types.TableApplications:
+		saved = &types.Application{}
+	case types.TableAppLimits:
+		saved = &types.AppLimit{}
+	case types.TableGatewayAAT:
+		saved = &types.GatewayAAT{}
+	case types.TableGatewaySettings:
+		saved = &types.GatewaySettings{}
+	case types.TableSecretKeys:
+		saved = &types.SecretKey{}
+	case types.TableWhitelistContracts:
+		saved = &types.WhitelistContract{}
+	case types.TableWhitelistMethods:
+		saved = &types.WhitelistMethod{}
+	case types.TableNotificationSettings:
+		saved = &types.NotificationSettings{}
+	case types.TableBlockchains:
+		saved = &types.Blockchain{}
+	case types.TableRedirects:
+		saved = &types.Redirect{}
+	case types.TableSyncCheckOptions:
+		saved = &types.SyncCheckOptions{}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTable, notificationEvent.Table)
+	}
+
+	err = json.Unmarshal(notificationEvent.Data, saved)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Notification{
+		Table:  notificationEvent.Table,
+		Action: notificationEvent.Action,
+		Data:   saved,
+	}, nil
+}
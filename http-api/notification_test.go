@@ -0,0 +1,23 @@
+package httpapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func Test_ParseNotification(t *testing.T) {
+	c := require.New(t)
+
+	notification, err := ParseNotification([]byte(`{"table": "lb_apps", "action": "INSERT", "data": {"lb_id": "lb_1", "app_id": "app_1"}}`))
+	c.NoError(err)
+	c.Equal(&types.Notification{
+		Table:  types.TableLbApps,
+		Action: types.ActionInsert,
+		Data:   &types.LbApp{LbID: "lb_1", AppID: "app_1"},
+	}, notification)
+
+	_, err = ParseNotification([]byte(`{"table": "not_a_table", "action": "INSERT", "data": {}}`))
+	c.ErrorIs(err, ErrUnknownTable)
+}
@@ -0,0 +1,40 @@
+package httpapi
+
+import "github.com/vishruthsk/portal-db-main/types"
+
+/* Bodies of the routes taking or answering more than the types of the driver */
+type (
+	/* RotateSecretKeyRequest is the body of the rotate route, Overlap is a duration such as 1h30m */
+	RotateSecretKeyRequest struct {
+		Overlap string `json:"overlap"`
+	}
+	/* ActivateChainRequest is the body of the blockchain active route */
+	ActivateChainRequest struct {
+		Active bool `json:"active"`
+	}
+	/* AcceptInvitationRequest is the body of the accept invitation route */
+	AcceptInvitationRequest struct {
+		UserID string `json:"userID"`
+	}
+	/* UpdateUserAccessRoleRequest is the body of the user role route */
+	UpdateUserAccessRoleRequest struct {
+		RoleName types.RoleName `json:"roleName"`
+	}
+	/* TransferOwnershipRequest is the body of the transfer route */
+	TransferOwnershipRequest struct {
+		FromUserID string `json:"fromUserID"`
+		ToUserID   string `json:"toUserID"`
+	}
+	/* RemoveExpiredInvitationsResponse is the body answered by the expired invitations route */
+	RemoveExpiredInvitationsResponse struct {
+		Removed int64 `json:"removed"`
+	}
+	/* IncrementUsageRequest is the body of the application usage route */
+	IncrementUsageRequest struct {
+		Relays int64 `json:"relays"`
+	}
+	/* IncrementUsageResponse is the body answered by the application usage route, Relays is the total of the day */
+	IncrementUsageResponse struct {
+		Relays int64 `json:"relays"`
+	}
+)
@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* ReadPayPlans returns all pay plans */
+func (c *Client) ReadPayPlans(ctx context.Context) ([]*types.PayPlan, error) {
+	var payPlans []*types.PayPlan
+	err := c.do(ctx, http.MethodGet, path("pay-plans"), nil, &payPlans)
+	if err != nil {
+		return nil, err
+	}
+
+	return payPlans, nil
+}
+
+/* ReadApplications returns all Applications */
+func (c *Client) ReadApplications(ctx context.Context) ([]*types.Application, error) {
+	var apps []*types.Application
+	err := c.do(ctx, http.MethodGet, path("applications"), nil, &apps)
+	if err != nil {
+		return nil, err
+	}
+
+	return apps, nil
+}
+
+/* WriteApplication saves input Application and returns it with its generated fields */
+func (c *Client) WriteApplication(ctx context.Context, app *types.Application) (*types.Application, error) {
+	var written types.Application
+	err := c.do(ctx, http.MethodPost, path("applications"), app.WithSecrets(), &written)
+	if err != nil {
+		return nil, err
+	}
+
+	return &written, nil
+}
+
+/* UpdateApplication updates fields by ID */
+func (c *Client) UpdateApplication(ctx context.Context, id string, update *types.UpdateApplication) error {
+	return c.do(ctx, http.MethodPut, path("applications", id), update, nil)
+}
+
+/* UpdateAppFirstDateSurpassed updates FirstDateSurpassed of the Applications */
+func (c *Client) UpdateAppFirstDateSurpassed(ctx context.Context, update *types.UpdateFirstDateSurpassed) error {
+	return c.do(ctx, http.MethodPut, path("applications", "first-date-surpassed"), update, nil)
+}
+
+/* RemoveApplication sets the Application's status to AwaitingGracePeriod */
+func (c *Client) RemoveApplication(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, path("applications", id), nil, nil)
+}
+
+/* RotateSecretKey generates a new secret key with the name, the key it replaces stays valid for the overlap */
+func (c *Client) RotateSecretKey(ctx context.Context, appID, name string, overlap time.Duration) (*types.SecretKey, error) {
+	var secretKey types.SecretKey
+	err := c.do(ctx, http.MethodPost, path("applications", appID, "secret-keys", name, "rotate"),
+		&httpapi.RotateSecretKeyRequest{Overlap: overlap.String()}, &secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secretKey, nil
+}
+
+/* RevokeSecretKey revokes the secret key with the name */
+func (c *Client) RevokeSecretKey(ctx context.Context, appID, name string) error {
+	return c.do(ctx, http.MethodDelete, path("applications", appID, "secret-keys", name), nil, nil)
+}
+
+/* ReadAppPlanHistory returns the pay plan periods of an Application overlapping the time range, a zero to is now */
+func (c *Client) ReadAppPlanHistory(ctx context.Context, appID string, from, to time.Time) ([]*types.AppPlanPeriod, error) {
+	query := url.Values{}
+	setQueryTime(query, "from", from)
+	setQueryTime(query, "to", to)
+
+	var periods []*types.AppPlanPeriod
+	err := c.do(ctx, http.MethodGet, path("applications", appID, "plan-history")+encodeQuery(query), nil, &periods)
+	if err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+/* ReadAppPlanAt returns the pay plan period of an Application in effect at a time */
+func (c *Client) ReadAppPlanAt(ctx context.Context, appID string, at time.Time) (*types.AppPlanPeriod, error) {
+	query := url.Values{}
+	setQueryTime(query, "at", at)
+
+	var period types.AppPlanPeriod
+	err := c.do(ctx, http.MethodGet, path("applications", appID, "plan")+encodeQuery(query), nil, &period)
+	if err != nil {
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+/* setQueryTime sets a time query parameter, zero times are left out */
+func setQueryTime(query url.Values, name string, value time.Time) {
+	if !value.IsZero() {
+		query.Set(name, value.Format(time.RFC3339Nano))
+	}
+}
+
+func encodeQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	return "?" + query.Encode()
+}
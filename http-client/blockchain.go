@@ -0,0 +1,47 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* ReadBlockchains returns all blockchains */
+func (c *Client) ReadBlockchains(ctx context.Context) ([]*types.Blockchain, error) {
+	var blockchains []*types.Blockchain
+	err := c.do(ctx, http.MethodGet, path("blockchains"), nil, &blockchains)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockchains, nil
+}
+
+/* WriteBlockchain saves input blockchain and returns it */
+func (c *Client) WriteBlockchain(ctx context.Context, blockchain *types.Blockchain) (*types.Blockchain, error) {
+	var written types.Blockchain
+	err := c.do(ctx, http.MethodPost, path("blockchains"), blockchain, &written)
+	if err != nil {
+		return nil, err
+	}
+
+	return &written, nil
+}
+
+/* WriteRedirect saves input redirect and returns it */
+func (c *Client) WriteRedirect(ctx context.Context, redirect *types.Redirect) (*types.Redirect, error) {
+	var written types.Redirect
+	err := c.do(ctx, http.MethodPost, path("redirects"), redirect, &written)
+	if err != nil {
+		return nil, err
+	}
+
+	return &written, nil
+}
+
+/* ActivateChain activates or deactivates the blockchain */
+func (c *Client) ActivateChain(ctx context.Context, id string, active bool) error {
+	return c.do(ctx, http.MethodPut, path("blockchains", id, "active"), &httpapi.ActivateChainRequest{Active: active}, nil)
+}
@@ -0,0 +1,152 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+var ErrUnexpectedResponse = errors.New("error: unexpected response from the server")
+
+/*
+The Client struct satisfies the Driver interface calling a remote httpserver.Server.
+The errors answered by the server are returned as the ones its driver returned, so they can be matched with
+errors.Is and errors.As as with any other driver. Notifications are read from the server's notifications stream,
+which is reopened after it breaks, resuming after the last notification received.
+*/
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	listener   *listener
+}
+
+/*
+NewClient returns a Client for the server at baseURL authenticating with the API key, or an error if it cannot
+open the notifications stream. httpClient may be nil to use one without timeouts, it must not time out the stream.
+reportProblem receives the errors of the notifications stream and may be nil.
+*/
+func NewClient(baseURL, apiKey string, httpClient *http.Client, reportProblem func(err error)) (*Client, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	if reportProblem == nil {
+		reportProblem = func(err error) {}
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+
+	listener, err := c.listen(reportProblem)
+	if err != nil {
+		return nil, err
+	}
+	c.listener = listener
+
+	return c, nil
+}
+
+/* path joins the segments into a path of the server, escaping each of them */
+func path(segments ...string) string {
+	var builder strings.Builder
+	for _, segment := range segments {
+		builder.WriteString("/")
+		builder.WriteString(url.PathEscape(segment))
+	}
+
+	return builder.String()
+}
+
+/* newRequest returns a request to the server carrying the API key and the actor and status override of ctx */
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(httpapi.APIKeyHeader, c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if actor := types.ActorFromContext(ctx); actor != "" {
+		req.Header.Set(httpapi.ActorHeader, actor)
+	}
+	if types.StatusOverrideFromContext(ctx) {
+		req.Header.Set(httpapi.StatusOverrideHeader, "true")
+	}
+
+	return req, nil
+}
+
+/*
+do sends body as JSON and decodes the response into out if they are not nil.
+Error responses are returned as the error the server's driver returned.
+*/
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := c.newRequest(ctx, method, path, reader)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return translateError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return readError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnexpectedResponse, err)
+	}
+
+	return nil
+}
+
+/* readError returns the error answered by the server */
+func readError(resp *http.Response) error {
+	var errorResponse httpapi.ErrorResponse
+	err := json.NewDecoder(resp.Body).Decode(&errorResponse)
+	if err != nil || errorResponse.Code == "" {
+		return fmt.Errorf("%w: %s", ErrUnexpectedResponse, resp.Status)
+	}
+
+	return errorResponse.Err()
+}
+
+/* translateError converts errors caused by the deadline of ctx into a *types.TimeoutError, like the other drivers */
+func translateError(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &types.TimeoutError{Kind: types.TimeoutContext, Err: err}
+	}
+
+	return err
+}
@@ -0,0 +1,126 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* ReadLoadBalancers returns all LoadBalancers */
+func (c *Client) ReadLoadBalancers(ctx context.Context) ([]*types.LoadBalancer, error) {
+	var loadBalancers []*types.LoadBalancer
+	err := c.do(ctx, http.MethodGet, path("load-balancers"), nil, &loadBalancers)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadBalancers, nil
+}
+
+/* ReadUserRoles returns all User Roles as a map that takes the form map[User ID]map[LB ID][]types.PermissionsEnum */
+func (c *Client) ReadUserRoles(ctx context.Context) (map[string]map[string][]types.PermissionsEnum, error) {
+	var userRoles map[string]map[string][]types.PermissionsEnum
+	err := c.do(ctx, http.MethodGet, path("user-roles"), nil, &userRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	return userRoles, nil
+}
+
+/* ReadPendingInvitations returns the invitations sent to the email that have not expired */
+func (c *Client) ReadPendingInvitations(ctx context.Context, email string) ([]*types.Invitation, error) {
+	query := url.Values{}
+	query.Set("email", email)
+
+	var invitations []*types.Invitation
+	err := c.do(ctx, http.MethodGet, path("invitations")+encodeQuery(query), nil, &invitations)
+	if err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+/* WriteLoadBalancer saves input LoadBalancer and returns it with its generated fields */
+func (c *Client) WriteLoadBalancer(ctx context.Context, loadBalancer *types.LoadBalancer) (*types.LoadBalancer, error) {
+	var written types.LoadBalancer
+	err := c.do(ctx, http.MethodPost, path("load-balancers"), loadBalancer.WithSecrets(), &written)
+	if err != nil {
+		return nil, err
+	}
+
+	return &written, nil
+}
+
+/* WriteLoadBalancerUser adds a user to the LoadBalancer */
+func (c *Client) WriteLoadBalancerUser(ctx context.Context, lbID string, userAccess types.UserAccess) error {
+	return c.do(ctx, http.MethodPost, path("load-balancers", lbID, "users"), userAccess, nil)
+}
+
+/* WriteLoadBalancerInvitation invites a user to the LoadBalancer and returns the invitation */
+func (c *Client) WriteLoadBalancerInvitation(ctx context.Context, lbID string, userAccess types.UserAccess) (*types.Invitation, error) {
+	var invitation types.Invitation
+	err := c.do(ctx, http.MethodPost, path("load-balancers", lbID, "invitations"), userAccess, &invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+/* AcceptInvitation adds the user the invitation was sent to to its LoadBalancer */
+func (c *Client) AcceptInvitation(ctx context.Context, token, userID string) error {
+	return c.do(ctx, http.MethodPost, path("invitations", token, "accept"), &httpapi.AcceptInvitationRequest{UserID: userID}, nil)
+}
+
+/* DeclineInvitation removes the invitation */
+func (c *Client) DeclineInvitation(ctx context.Context, token string) error {
+	return c.do(ctx, http.MethodPost, path("invitations", token, "decline"), nil, nil)
+}
+
+/* RemoveExpiredInvitations removes the expired invitations and returns how many were removed */
+func (c *Client) RemoveExpiredInvitations(ctx context.Context) (int64, error) {
+	var response httpapi.RemoveExpiredInvitationsResponse
+	err := c.do(ctx, http.MethodDelete, path("invitations", "expired"), nil, &response)
+	if err != nil {
+		return 0, err
+	}
+
+	return response.Removed, nil
+}
+
+/* UpdateLoadBalancer updates LoadBalancer by ID */
+func (c *Client) UpdateLoadBalancer(ctx context.Context, id string, options *types.UpdateLoadBalancer) error {
+	return c.do(ctx, http.MethodPut, path("load-balancers", id), options, nil)
+}
+
+/* UpdateUserAccessRole updates the role of a user of the LoadBalancer */
+func (c *Client) UpdateUserAccessRole(ctx context.Context, userID, lbID string, roleName types.RoleName) error {
+	return c.do(ctx, http.MethodPut, path("load-balancers", lbID, "users", userID, "role"),
+		&httpapi.UpdateUserAccessRoleRequest{RoleName: roleName}, nil)
+}
+
+/* UpdateLoadBalancerUser updates a user of the LoadBalancer */
+func (c *Client) UpdateLoadBalancerUser(ctx context.Context, lbID string, update *types.UpdateUserAccess) error {
+	return c.do(ctx, http.MethodPut, path("load-balancers", lbID, "users"), update, nil)
+}
+
+/* TransferLoadBalancerOwnership makes an accepted user of the LoadBalancer its owner, the previous owner becomes an admin */
+func (c *Client) TransferLoadBalancerOwnership(ctx context.Context, lbID, fromUserID, toUserID string) error {
+	return c.do(ctx, http.MethodPost, path("load-balancers", lbID, "transfer"),
+		&httpapi.TransferOwnershipRequest{FromUserID: fromUserID, ToUserID: toUserID}, nil)
+}
+
+/* RemoveLoadBalancer sets the user ID of the LoadBalancer to an empty string so it no longer appears to its users */
+func (c *Client) RemoveLoadBalancer(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, path("load-balancers", id), nil, nil)
+}
+
+/* RemoveUserAccess removes a user from the LoadBalancer */
+func (c *Client) RemoveUserAccess(ctx context.Context, userID, lbID string) error {
+	return c.do(ctx, http.MethodDelete, path("load-balancers", lbID, "users", userID), nil, nil)
+}
@@ -0,0 +1,195 @@
+package httpclient
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const (
+	minReconnectDelay = 100 * time.Millisecond
+	maxReconnectDelay = 10 * time.Second
+)
+
+var (
+	ErrStreamDisconnected  = errors.New("error: notifications stream disconnected")
+	ErrMissedNotifications = errors.New("error: notifications were missed while the stream was disconnected")
+)
+
+/* listener reads the notifications stream of the server, reopening it after the last event it received when it breaks */
+type listener struct {
+	notification  chan *types.Notification
+	reportProblem func(err error)
+	lastEventID   string
+
+	cancel    context.CancelFunc
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+/* NotificationChannel returns receiver Notification channel  */
+func (c *Client) NotificationChannel() <-chan *types.Notification {
+	return c.listener.notification
+}
+
+/* CloseListener closes the notifications stream and the Notification channel */
+func (c *Client) CloseListener() {
+	l := c.listener
+	l.closeOnce.Do(func() {
+		l.cancel()
+		<-l.stopped
+		close(l.notification)
+	})
+}
+
+/* listen opens the notifications stream, so no notification sent once NewClient returns is missed */
+func (c *Client) listen(reportProblem func(err error)) (*listener, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	resp, err := c.openStream(ctx, "")
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	l := &listener{
+		notification:  make(chan *types.Notification),
+		reportProblem: reportProblem,
+		cancel:        cancel,
+		stopped:       make(chan struct{}),
+	}
+
+	go l.run(ctx, c, resp)
+
+	return l, nil
+}
+
+func (c *Client) openStream(ctx context.Context, lastEventID string) (*http.Response, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path("notifications"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, readError(resp)
+	}
+
+	return resp, nil
+}
+
+/* run reads the stream until the listener is closed, waiting longer between each failed attempt to reopen it */
+func (l *listener) run(ctx context.Context, c *Client, resp *http.Response) {
+	defer close(l.stopped)
+
+	for {
+		err := l.read(ctx, resp.Body)
+		resp.Body.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		l.reportProblem(fmt.Errorf("%w: %s", ErrStreamDisconnected, err))
+
+		delay := minReconnectDelay
+		for {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+
+			resp, err = c.openStream(ctx, l.lastEventID)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			l.reportProblem(fmt.Errorf("%w: %s", ErrStreamDisconnected, err))
+
+			delay *= 2
+			if delay > maxReconnectDelay {
+				delay = maxReconnectDelay
+			}
+		}
+	}
+}
+
+/* read sends the notifications of the stream's events on the Notification channel until the stream ends */
+func (l *listener) read(ctx context.Context, body io.Reader) error {
+	reader := bufio.NewReader(body)
+
+	var id, name string
+	var data []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch {
+		case line == "":
+			err = l.dispatch(ctx, id, name, strings.Join(data, "\n"))
+			if err != nil {
+				return err
+			}
+			id, name, data = "", "", nil
+		case field == "id":
+			id = value
+		case field == "event":
+			name = value
+		case field == "data":
+			data = append(data, value)
+		}
+	}
+}
+
+func (l *listener) dispatch(ctx context.Context, id, name, data string) error {
+	switch name {
+	case httpapi.EventGap:
+		l.reportProblem(ErrMissedNotifications)
+	case httpapi.EventNotification:
+		notification, err := httpapi.ParseNotification([]byte(data))
+		if err != nil {
+			l.reportProblem(err)
+			break
+		}
+
+		select {
+		case l.notification <- notification:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if id != "" {
+		l.lastEventID = id
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package httpclient
+
+import (
+	"context"
+
+	"github.com/vishruthsk/portal-db-main/driver"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/*
+InTx returns driver.ErrTxNotSupported without calling fn, the server runs every write in its own transaction
+so several writes sent to it cannot be committed or rolled back together
+*/
+func (c *Client) InTx(ctx context.Context, opts *driver.TxOptions, fn func(w driver.Writer) error) error {
+	if fn == nil {
+		return types.ErrNilTxFunc
+	}
+
+	return driver.ErrTxNotSupported
+}
@@ -0,0 +1,92 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* ReadUsage returns the relay usage matching the filter */
+func (c *Client) ReadUsage(ctx context.Context, filter types.RelayUsageFilter) ([]*types.RelayUsage, error) {
+	query := url.Values{}
+	if filter.ApplicationID != "" {
+		query.Set("applicationID", filter.ApplicationID)
+	}
+	setQueryTime(query, "from", filter.From)
+	setQueryTime(query, "to", filter.To)
+
+	var usage []*types.RelayUsage
+	err := c.do(ctx, http.MethodGet, path("usage")+encodeQuery(query), nil, &usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+/* ReadApplicationsOverLimit returns the usage of the Applications over their daily limit today */
+func (c *Client) ReadApplicationsOverLimit(ctx context.Context) ([]*types.ApplicationUsage, error) {
+	var usage []*types.ApplicationUsage
+	err := c.do(ctx, http.MethodGet, path("usage", "over-limit"), nil, &usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+/* IncrementUsage adds relays to the Application's usage today and returns its total */
+func (c *Client) IncrementUsage(ctx context.Context, appID string, relays int64) (int64, error) {
+	var response httpapi.IncrementUsageResponse
+	err := c.do(ctx, http.MethodPost, path("applications", appID, "usage"), &httpapi.IncrementUsageRequest{Relays: relays}, &response)
+	if err != nil {
+		return 0, err
+	}
+
+	return response.Relays, nil
+}
+
+/* FlushUsage adds the relays counted elsewhere to the usage */
+func (c *Client) FlushUsage(ctx context.Context, usage []types.RelayUsage) error {
+	return c.do(ctx, http.MethodPost, path("usage"), usage, nil)
+}
+
+/* EvaluateUsageThresholds returns the thresholds the Application's usage crossed today that were not notified yet */
+func (c *Client) EvaluateUsageThresholds(ctx context.Context, appID string) ([]*types.UsageThresholdEvent, error) {
+	var events []*types.UsageThresholdEvent
+	err := c.do(ctx, http.MethodPost, path("applications", appID, "usage-thresholds"), nil, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+/* ReadAuditLog returns the audit log entries matching the filter */
+func (c *Client) ReadAuditLog(ctx context.Context, filter types.AuditLogFilter) ([]*types.AuditLogEntry, error) {
+	query := url.Values{}
+	if filter.EntityType != "" {
+		query.Set("entityType", string(filter.EntityType))
+	}
+	if filter.EntityID != "" {
+		query.Set("entityID", filter.EntityID)
+	}
+	if filter.ActorID != "" {
+		query.Set("actorID", filter.ActorID)
+	}
+	if filter.Limit != 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	var entries []*types.AuditLogEntry
+	err := c.do(ctx, http.MethodGet, path("audit-log")+encodeQuery(query), nil, &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
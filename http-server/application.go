@@ -0,0 +1,143 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/*
+applicationsWithSecrets opts the Applications in to serialising their secrets, the API serves them to trusted
+services holding an API key so that clients get the same Applications as from the driver
+*/
+func applicationsWithSecrets(apps []*types.Application) []types.ApplicationWithSecrets {
+	withSecrets := make([]types.ApplicationWithSecrets, 0, len(apps))
+	for _, app := range apps {
+		withSecrets = append(withSecrets, app.WithSecrets())
+	}
+
+	return withSecrets
+}
+
+func (s *Server) readPayPlans(w http.ResponseWriter, r *http.Request, params []string) {
+	payPlans, err := s.driver.ReadPayPlans(r.Context())
+	s.writeResult(w, http.StatusOK, payPlans, err)
+}
+
+func (s *Server) readApplications(w http.ResponseWriter, r *http.Request, params []string) {
+	apps, err := s.driver.ReadApplications(r.Context())
+	s.writeResult(w, http.StatusOK, applicationsWithSecrets(apps), err)
+}
+
+/* readApplication answers with the Application with the ID, or types.ErrNotFound if there is none */
+func (s *Server) readApplication(w http.ResponseWriter, r *http.Request, params []string) {
+	apps, err := s.driver.ReadApplications(r.Context())
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	for _, app := range apps {
+		if app.ID == params[0] {
+			s.writeJSON(w, http.StatusOK, app.WithSecrets())
+			return
+		}
+	}
+
+	s.writeError(w, types.ErrNotFound)
+}
+
+func (s *Server) writeApplication(w http.ResponseWriter, r *http.Request, params []string) {
+	var app types.Application
+	err := decodeBody(r, &app)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	written, err := s.driver.WriteApplication(r.Context(), &app)
+	s.writeResult(w, http.StatusCreated, written.WithSecrets(), err)
+}
+
+func (s *Server) updateApplication(w http.ResponseWriter, r *http.Request, params []string) {
+	var update types.UpdateApplication
+	err := decodeBody(r, &update)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.UpdateApplication(r.Context(), params[0], &update))
+}
+
+func (s *Server) updateAppFirstDateSurpassed(w http.ResponseWriter, r *http.Request, params []string) {
+	var update types.UpdateFirstDateSurpassed
+	err := decodeBody(r, &update)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.UpdateAppFirstDateSurpassed(r.Context(), &update))
+}
+
+func (s *Server) removeApplication(w http.ResponseWriter, r *http.Request, params []string) {
+	s.writeNoContent(w, s.driver.RemoveApplication(r.Context(), params[0]))
+}
+
+func (s *Server) rotateSecretKey(w http.ResponseWriter, r *http.Request, params []string) {
+	var request httpapi.RotateSecretKeyRequest
+	err := decodeBody(r, &request)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	var overlap time.Duration
+	if request.Overlap != "" {
+		overlap, err = time.ParseDuration(request.Overlap)
+		if err != nil {
+			s.writeError(w, fmt.Errorf("%w: overlap: %s", httpapi.ErrInvalidBody, err))
+			return
+		}
+	}
+
+	secretKey, err := s.driver.RotateSecretKey(r.Context(), params[0], params[1], overlap)
+	s.writeResult(w, http.StatusOK, secretKey.WithSecrets(), err)
+}
+
+func (s *Server) revokeSecretKey(w http.ResponseWriter, r *http.Request, params []string) {
+	s.writeNoContent(w, s.driver.RevokeSecretKey(r.Context(), params[0], params[1]))
+}
+
+/* readAppPlanHistory answers with the pay plan periods overlapping the from and to query parameters */
+func (s *Server) readAppPlanHistory(w http.ResponseWriter, r *http.Request, params []string) {
+	from, err := queryTime(r, "from")
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	to, err := queryTime(r, "to")
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	periods, err := s.driver.ReadAppPlanHistory(r.Context(), params[0], from, to)
+	s.writeResult(w, http.StatusOK, periods, err)
+}
+
+/* readAppPlanAt answers with the pay plan period in effect at the time of the at query parameter */
+func (s *Server) readAppPlanAt(w http.ResponseWriter, r *http.Request, params []string) {
+	at, err := queryTime(r, "at")
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	period, err := s.driver.ReadAppPlanAt(r.Context(), params[0], at)
+	s.writeResult(w, http.StatusOK, period, err)
+}
@@ -0,0 +1,112 @@
+package httpserver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func Test_Applications(t *testing.T) {
+	c := require.New(t)
+
+	server, _ := newTestServer(t)
+
+	var app types.Application
+	resp := doRequest(t, server, http.MethodPost, "/applications", testApplication(), &app)
+	c.Equal(http.StatusCreated, resp.StatusCode)
+	c.NotEmpty(app.ID)
+
+	var read types.Application
+	resp = doRequest(t, server, http.MethodGet, "/applications/"+app.ID, nil, &read)
+	c.Equal(http.StatusOK, resp.StatusCode)
+	c.Equal("pokt_app_http", read.Name)
+
+	resp = doRequest(t, server, http.MethodPut, "/applications/"+app.ID, &types.UpdateApplication{Name: "pokt_app_updated"}, nil)
+	c.Equal(http.StatusNoContent, resp.StatusCode)
+
+	var apps []*types.Application
+	resp = doRequest(t, server, http.MethodGet, "/applications", nil, &apps)
+	c.Equal(http.StatusOK, resp.StatusCode)
+	c.Len(apps, 1)
+	c.Equal("pokt_app_updated", apps[0].Name)
+
+	var rotated types.SecretKey
+	resp = doRequest(t, server, http.MethodPost, "/applications/"+app.ID+"/secret-keys/ci/rotate", &httpapi.RotateSecretKeyRequest{Overlap: "1h"}, &rotated)
+	c.Equal(http.StatusOK, resp.StatusCode)
+	c.Equal("ci", rotated.Name)
+	c.NotEqual(types.RedactedSecret, rotated.Key)
+	c.True(types.VerifySecretKey(apps[0].GatewaySettings.SecretKey, app.GatewaySettings.SecretKey))
+
+	var periods []*types.AppPlanPeriod
+	resp = doRequest(t, server, http.MethodGet, "/applications/"+app.ID+"/plan-history", nil, &periods)
+	c.Equal(http.StatusOK, resp.StatusCode)
+	c.Len(periods, 1)
+
+	resp = doRequest(t, server, http.MethodDelete, "/applications/"+app.ID, nil, nil)
+	c.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func Test_Applications_Errors(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var app types.Application
+	doRequest(t, server, http.MethodPost, "/applications", testApplication(), &app)
+
+	invalidApp := testApplication()
+	invalidApp.Status = "NOT_A_STATUS"
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           any
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "Should fail reading an Application that does not exist",
+			method:         http.MethodGet,
+			path:           "/applications/not_an_id",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "not_found",
+		},
+		{
+			name:           "Should fail writing an invalid Application",
+			method:         http.MethodPost,
+			path:           "/applications",
+			body:           invalidApp,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   httpapi.CodeValidationFailed,
+		},
+		{
+			name:           "Should fail updating with an outdated version",
+			method:         http.MethodPut,
+			path:           "/applications/" + app.ID,
+			body:           &types.UpdateApplication{Name: "pokt_app_conflict", ExpectedVersion: app.Version + 100},
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "conflict",
+		},
+		{
+			name:           "Should fail revoking a secret key that does not exist",
+			method:         http.MethodDelete,
+			path:           "/applications/" + app.ID + "/secret-keys/not_a_key",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "secret_key_not_found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			var response httpapi.ErrorResponse
+			resp := doRequest(t, server, test.method, test.path, test.body, &response)
+
+			c.Equal(test.expectedStatus, resp.StatusCode)
+			c.Equal(test.expectedCode, response.Code)
+		})
+	}
+}
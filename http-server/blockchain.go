@@ -0,0 +1,66 @@
+package httpserver
+
+import (
+	"net/http"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func (s *Server) readBlockchains(w http.ResponseWriter, r *http.Request, params []string) {
+	blockchains, err := s.driver.ReadBlockchains(r.Context())
+	s.writeResult(w, http.StatusOK, blockchains, err)
+}
+
+/* readBlockchain answers with the blockchain with the ID, or types.ErrNotFound if there is none */
+func (s *Server) readBlockchain(w http.ResponseWriter, r *http.Request, params []string) {
+	blockchains, err := s.driver.ReadBlockchains(r.Context())
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	for _, blockchain := range blockchains {
+		if blockchain.ID == params[0] {
+			s.writeJSON(w, http.StatusOK, blockchain)
+			return
+		}
+	}
+
+	s.writeError(w, types.ErrNotFound)
+}
+
+func (s *Server) writeBlockchain(w http.ResponseWriter, r *http.Request, params []string) {
+	var blockchain types.Blockchain
+	err := decodeBody(r, &blockchain)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	written, err := s.driver.WriteBlockchain(r.Context(), &blockchain)
+	s.writeResult(w, http.StatusCreated, written, err)
+}
+
+func (s *Server) writeRedirect(w http.ResponseWriter, r *http.Request, params []string) {
+	var redirect types.Redirect
+	err := decodeBody(r, &redirect)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	written, err := s.driver.WriteRedirect(r.Context(), &redirect)
+	s.writeResult(w, http.StatusCreated, written, err)
+}
+
+func (s *Server) activateChain(w http.ResponseWriter, r *http.Request, params []string) {
+	var request httpapi.ActivateChainRequest
+	err := decodeBody(r, &request)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.ActivateChain(r.Context(), params[0], request.Active))
+}
@@ -0,0 +1,159 @@
+package httpserver
+
+import (
+	"net/http"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func (s *Server) readLoadBalancers(w http.ResponseWriter, r *http.Request, params []string) {
+	loadBalancers, err := s.driver.ReadLoadBalancers(r.Context())
+	s.writeResult(w, http.StatusOK, loadBalancersWithSecrets(loadBalancers), err)
+}
+
+/* loadBalancersWithSecrets opts the LoadBalancers in to serialising the secrets of their Applications */
+func loadBalancersWithSecrets(loadBalancers []*types.LoadBalancer) []types.LoadBalancerWithSecrets {
+	withSecrets := make([]types.LoadBalancerWithSecrets, 0, len(loadBalancers))
+	for _, lb := range loadBalancers {
+		withSecrets = append(withSecrets, lb.WithSecrets())
+	}
+
+	return withSecrets
+}
+
+/* readLoadBalancer answers with the LoadBalancer with the ID, or types.ErrNotFound if there is none */
+func (s *Server) readLoadBalancer(w http.ResponseWriter, r *http.Request, params []string) {
+	loadBalancers, err := s.driver.ReadLoadBalancers(r.Context())
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	for _, lb := range loadBalancers {
+		if lb.ID == params[0] {
+			s.writeJSON(w, http.StatusOK, lb.WithSecrets())
+			return
+		}
+	}
+
+	s.writeError(w, types.ErrNotFound)
+}
+
+func (s *Server) readUserRoles(w http.ResponseWriter, r *http.Request, params []string) {
+	userRoles, err := s.driver.ReadUserRoles(r.Context())
+	s.writeResult(w, http.StatusOK, userRoles, err)
+}
+
+/* readPendingInvitations answers with the invitations sent to the email query parameter */
+func (s *Server) readPendingInvitations(w http.ResponseWriter, r *http.Request, params []string) {
+	invitations, err := s.driver.ReadPendingInvitations(r.Context(), r.URL.Query().Get("email"))
+	s.writeResult(w, http.StatusOK, invitations, err)
+}
+
+func (s *Server) writeLoadBalancer(w http.ResponseWriter, r *http.Request, params []string) {
+	var lb types.LoadBalancer
+	err := decodeBody(r, &lb)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	written, err := s.driver.WriteLoadBalancer(r.Context(), &lb)
+	s.writeResult(w, http.StatusCreated, written.WithSecrets(), err)
+}
+
+func (s *Server) updateLoadBalancer(w http.ResponseWriter, r *http.Request, params []string) {
+	var update types.UpdateLoadBalancer
+	err := decodeBody(r, &update)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.UpdateLoadBalancer(r.Context(), params[0], &update))
+}
+
+func (s *Server) removeLoadBalancer(w http.ResponseWriter, r *http.Request, params []string) {
+	s.writeNoContent(w, s.driver.RemoveLoadBalancer(r.Context(), params[0]))
+}
+
+func (s *Server) writeLoadBalancerUser(w http.ResponseWriter, r *http.Request, params []string) {
+	var userAccess types.UserAccess
+	err := decodeBody(r, &userAccess)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.WriteLoadBalancerUser(r.Context(), params[0], userAccess))
+}
+
+func (s *Server) updateLoadBalancerUser(w http.ResponseWriter, r *http.Request, params []string) {
+	var update types.UpdateUserAccess
+	err := decodeBody(r, &update)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.UpdateLoadBalancerUser(r.Context(), params[0], &update))
+}
+
+func (s *Server) updateUserAccessRole(w http.ResponseWriter, r *http.Request, params []string) {
+	var request httpapi.UpdateUserAccessRoleRequest
+	err := decodeBody(r, &request)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.UpdateUserAccessRole(r.Context(), params[1], params[0], request.RoleName))
+}
+
+func (s *Server) removeUserAccess(w http.ResponseWriter, r *http.Request, params []string) {
+	s.writeNoContent(w, s.driver.RemoveUserAccess(r.Context(), params[1], params[0]))
+}
+
+func (s *Server) transferLoadBalancerOwnership(w http.ResponseWriter, r *http.Request, params []string) {
+	var request httpapi.TransferOwnershipRequest
+	err := decodeBody(r, &request)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.TransferLoadBalancerOwnership(r.Context(), params[0], request.FromUserID, request.ToUserID))
+}
+
+func (s *Server) writeLoadBalancerInvitation(w http.ResponseWriter, r *http.Request, params []string) {
+	var userAccess types.UserAccess
+	err := decodeBody(r, &userAccess)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	invitation, err := s.driver.WriteLoadBalancerInvitation(r.Context(), params[0], userAccess)
+	s.writeResult(w, http.StatusCreated, invitation, err)
+}
+
+func (s *Server) acceptInvitation(w http.ResponseWriter, r *http.Request, params []string) {
+	var request httpapi.AcceptInvitationRequest
+	err := decodeBody(r, &request)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.AcceptInvitation(r.Context(), params[0], request.UserID))
+}
+
+func (s *Server) declineInvitation(w http.ResponseWriter, r *http.Request, params []string) {
+	s.writeNoContent(w, s.driver.DeclineInvitation(r.Context(), params[0]))
+}
+
+func (s *Server) removeExpiredInvitations(w http.ResponseWriter, r *http.Request, params []string) {
+	removed, err := s.driver.RemoveExpiredInvitations(r.Context())
+	s.writeResult(w, http.StatusOK, &httpapi.RemoveExpiredInvitationsResponse{Removed: removed}, err)
+}
@@ -0,0 +1,63 @@
+package httpserver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func Test_LoadBalancers(t *testing.T) {
+	c := require.New(t)
+
+	server, _ := newTestServer(t)
+
+	var app types.Application
+	doRequest(t, server, http.MethodPost, "/applications", testApplication(), &app)
+
+	var lb types.LoadBalancer
+	resp := doRequest(t, server, http.MethodPost, "/load-balancers", &types.LoadBalancer{
+		Name:           "pokt_lb_http",
+		UserID:         "owner_http",
+		ApplicationIDs: []string{app.ID},
+		Users:          []types.UserAccess{{UserID: "owner_http", Email: "owner@test.com"}},
+	}, &lb)
+	c.Equal(http.StatusCreated, resp.StatusCode)
+
+	var invitation types.Invitation
+	resp = doRequest(t, server, http.MethodPost, "/load-balancers/"+lb.ID+"/invitations",
+		&types.UserAccess{Email: "admin@test.com", RoleName: types.RoleAdmin}, &invitation)
+	c.Equal(http.StatusCreated, resp.StatusCode)
+
+	var invitations []*types.Invitation
+	resp = doRequest(t, server, http.MethodGet, "/invitations?email=admin@test.com", nil, &invitations)
+	c.Equal(http.StatusOK, resp.StatusCode)
+	c.Len(invitations, 1)
+
+	resp = doRequest(t, server, http.MethodPost, "/invitations/"+invitation.Token+"/accept", &httpapi.AcceptInvitationRequest{UserID: "admin_http"}, nil)
+	c.Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp = doRequest(t, server, http.MethodPost, "/load-balancers/"+lb.ID+"/transfer",
+		&httpapi.TransferOwnershipRequest{FromUserID: "owner_http", ToUserID: "admin_http"}, nil)
+	c.Equal(http.StatusNoContent, resp.StatusCode)
+
+	var userRoles map[string]map[string][]types.PermissionsEnum
+	resp = doRequest(t, server, http.MethodGet, "/user-roles", nil, &userRoles)
+	c.Equal(http.StatusOK, resp.StatusCode)
+	c.Contains(userRoles["admin_http"], lb.ID)
+
+	var read types.LoadBalancer
+	resp = doRequest(t, server, http.MethodGet, "/load-balancers/"+lb.ID, nil, &read)
+	c.Equal(http.StatusOK, resp.StatusCode)
+	c.Equal("admin_http", read.UserID)
+
+	var errorResponse httpapi.ErrorResponse
+	resp = doRequest(t, server, http.MethodPost, "/invitations/"+invitation.Token+"/accept", &httpapi.AcceptInvitationRequest{UserID: "admin_http"}, &errorResponse)
+	c.Equal(http.StatusNotFound, resp.StatusCode)
+	c.Equal("invitation_not_found", errorResponse.Code)
+
+	resp = doRequest(t, server, http.MethodDelete, "/load-balancers/"+lb.ID, nil, nil)
+	c.Equal(http.StatusNoContent, resp.StatusCode)
+}
@@ -0,0 +1,227 @@
+package httpserver
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const (
+	defaultNotificationBuffer = 1024
+	subscriberBuffer          = 256
+	keepAliveInterval         = 15 * time.Second
+)
+
+type (
+	event struct {
+		id   uint64
+		data []byte
+	}
+
+	/*
+		broker forwards the notifications of a driver to every subscriber, keeping the last ones so a subscriber
+		that reconnects can resume. Subscribers that fall behind are dropped and have to reconnect.
+		Event IDs are <epoch>-<n>, the epoch is random for each broker so IDs sent by another process are told apart.
+	*/
+	broker struct {
+		epoch       string
+		mu          sync.Mutex
+		events      []event
+		size        int
+		lastID      uint64
+		subscribers map[chan event]bool
+		done        chan struct{}
+		closeOnce   sync.Once
+	}
+)
+
+func newBroker(notifications <-chan *types.Notification, size int) *broker {
+	b := &broker{
+		epoch:       newEpoch(),
+		size:        size,
+		subscribers: make(map[chan event]bool),
+		done:        make(chan struct{}),
+	}
+
+	go b.run(notifications)
+
+	return b
+}
+
+func (b *broker) run(notifications <-chan *types.Notification) {
+	for {
+		select {
+		case notification, ok := <-notifications:
+			if !ok {
+				b.close()
+				return
+			}
+			if notification == nil {
+				continue
+			}
+
+			data, err := json.Marshal(notification.WithSecrets().Data)
+			if err != nil {
+				continue
+			}
+			data, _ = json.Marshal(httpapi.NotificationEvent{Table: notification.Table, Action: notification.Action, Data: data})
+
+			b.publish(data)
+		case <-b.done:
+			return
+		}
+	}
+}
+
+func (b *broker) publish(data []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	e := event{id: b.lastID, data: data}
+
+	b.events = append(b.events, e)
+	if len(b.events) > b.size {
+		b.events = b.events[len(b.events)-b.size:]
+	}
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- e:
+		default:
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+/*
+subscribe returns a channel receiving the events published from now on, closed when the subscriber is dropped.
+A subscriber resuming after lastEventID also gets the events it missed, gap is true if some are no longer held.
+*/
+func (b *broker) subscribe(lastEventID string) (subscriber chan event, missed []event, gap bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriber = make(chan event, subscriberBuffer)
+	select {
+	case <-b.done:
+		close(subscriber)
+		return subscriber, nil, false
+	default:
+		b.subscribers[subscriber] = true
+	}
+
+	if lastEventID == "" {
+		return subscriber, nil, false
+	}
+
+	// An ID of another epoch, or after the last one, was sent by another process like the server before it restarted
+	epoch, n, _ := strings.Cut(lastEventID, "-")
+	id, err := strconv.ParseUint(n, 10, 64)
+	if epoch != b.epoch || err != nil || id > b.lastID {
+		return subscriber, append([]event{}, b.events...), true
+	}
+
+	for i, e := range b.events {
+		if e.id > id {
+			return subscriber, append([]event{}, b.events[i:]...), i == 0 && e.id > id+1
+		}
+	}
+
+	return subscriber, nil, false
+}
+
+func (b *broker) unsubscribe(subscriber chan event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers[subscriber] {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+/* newEpoch returns a random hex string, or the current time in hex if no random bytes can be read */
+func newEpoch() string {
+	bytes := make([]byte, 8)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+
+	return hex.EncodeToString(bytes)
+}
+
+func (b *broker) close() {
+	b.closeOnce.Do(func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		close(b.done)
+		for subscriber := range b.subscribers {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	})
+}
+
+/*
+streamNotifications streams the driver's notifications as server-sent events until the client disconnects.
+A client sending the Last-Event-ID header first receives the notifications sent after it.
+*/
+func (s *Server) streamNotifications(w http.ResponseWriter, r *http.Request, params []string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, errors.New("error: response writer does not support streaming"))
+		return
+	}
+
+	subscriber, missed, gap := s.broker.subscribe(r.Header.Get("Last-Event-ID"))
+	defer s.broker.unsubscribe(subscriber)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if gap {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", httpapi.EventGap)
+	}
+	for _, e := range missed {
+		writeEvent(w, s.broker.epoch, e)
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case e, ok := <-subscriber:
+			if !ok {
+				return
+			}
+			writeEvent(w, s.broker.epoch, e)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, epoch string, e event) {
+	fmt.Fprintf(w, "id: %s-%d\nevent: %s\ndata: %s\n\n", epoch, e.id, httpapi.EventNotification, e.data)
+}
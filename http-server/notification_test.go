@@ -0,0 +1,111 @@
+package httpserver
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+type testEvent struct {
+	id, name, data string
+}
+
+/* openStream opens the notifications route, resuming after lastEventID if it is set, and returns its events */
+func openStream(t *testing.T, server *httptest.Server, lastEventID string) <-chan testEvent {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/notifications", nil)
+	require.NoError(t, err)
+	req.Header.Set(httpapi.APIKeyHeader, testAPIKey)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	t.Cleanup(func() { resp.Body.Close() })
+
+	events := make(chan testEvent, 100)
+	go func() {
+		defer close(events)
+
+		var e testEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				events <- e
+				e = testEvent{}
+			case strings.HasPrefix(line, "id: "):
+				e.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				e.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				e.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+
+	return events
+}
+
+func receiveEvents(t *testing.T, events <-chan testEvent, n int) []testEvent {
+	t.Helper()
+
+	var received []testEvent
+	for len(received) < n {
+		select {
+		case e := <-events:
+			received = append(received, e)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d events, expected %d", len(received), n)
+		}
+	}
+
+	return received
+}
+
+func Test_StreamNotifications(t *testing.T) {
+	c := require.New(t)
+
+	server, _ := newTestServer(t)
+	events := openStream(t, server, "")
+
+	var app types.Application
+	doRequest(t, server, http.MethodPost, "/applications", testApplication(), &app)
+
+	// applications, app_limits, gateway_settings, secret_keys and notification_settings
+	received := receiveEvents(t, events, 5)
+	c.Equal(httpapi.EventNotification, received[0].name)
+	epoch, n, ok := strings.Cut(received[0].id, "-")
+	c.True(ok)
+	c.NotEmpty(epoch)
+	c.Equal("1", n)
+
+	notification, err := httpapi.ParseNotification([]byte(received[0].data))
+	c.NoError(err)
+	c.Equal(types.TableApplications, notification.Table)
+	c.Equal(types.ActionInsert, notification.Action)
+	c.Equal(app.ID, notification.Data.(*types.Application).ID)
+
+	// A stream resuming after an event receives the ones sent after it
+	resumed := receiveEvents(t, openStream(t, server, received[2].id), 2)
+	c.Equal(received[3:], resumed)
+
+	// A stream resuming after an event sent by another server process is told some were missed
+	for _, lastEventID := range []string{"3", "0123456789abcdef-3", epoch + "-100"} {
+		resumed = receiveEvents(t, openStream(t, server, lastEventID), 6)
+		c.Equal(httpapi.EventGap, resumed[0].name)
+		c.Equal(received, resumed[1:])
+	}
+}
@@ -0,0 +1,294 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/driver"
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const maxBodyBytes = 1 << 20
+
+var ErrNoAPIKeys = errors.New("error: at least one API key is required")
+
+type (
+	/*
+		The Server struct is an http.Handler serving every Reader and Writer method of a driver as a JSON API.
+		Requests must be authenticated with one of its API keys in the httpapi.APIKeyHeader, failed ones are answered with
+		an httpapi.ErrorResponse. Only requests with one of its admin API keys may override status transitions.
+		Notifications of the driver are streamed to every client of the notifications route.
+	*/
+	Server struct {
+		driver        driver.Driver
+		apiKeys       [][]byte
+		adminAPIKeys  [][]byte
+		routes        []route
+		broker        *broker
+		reportProblem func(err error)
+	}
+
+	/* route matches a method and path, segments in braces match any single segment and are passed to handle in order */
+	route struct {
+		method   string
+		segments []string
+		handle   func(w http.ResponseWriter, r *http.Request, params []string)
+	}
+)
+
+/*
+NewServer returns a Server for the driver accepting any of the API keys or admin API keys, and starts forwarding
+the driver's notifications. Either set may be empty but not both. reportProblem receives the internal errors that
+are not sent to clients, it may be nil.
+*/
+func NewServer(d driver.Driver, apiKeys, adminAPIKeys []string, reportProblem func(err error)) (*Server, error) {
+	if len(apiKeys) == 0 && len(adminAPIKeys) == 0 {
+		return nil, ErrNoAPIKeys
+	}
+	if reportProblem == nil {
+		reportProblem = func(err error) {}
+	}
+
+	s := &Server{
+		driver:        d,
+		broker:        newBroker(d.NotificationChannel(), defaultNotificationBuffer),
+		reportProblem: reportProblem,
+	}
+	for _, apiKey := range apiKeys {
+		if apiKey == "" {
+			return nil, ErrNoAPIKeys
+		}
+		s.apiKeys = append(s.apiKeys, []byte(apiKey))
+	}
+	for _, apiKey := range adminAPIKeys {
+		if apiKey == "" {
+			return nil, ErrNoAPIKeys
+		}
+		s.adminAPIKeys = append(s.adminAPIKeys, []byte(apiKey))
+	}
+
+	s.routes = []route{
+		{http.MethodGet, path("pay-plans"), s.readPayPlans},
+		{http.MethodGet, path("user-roles"), s.readUserRoles},
+		{http.MethodGet, path("audit-log"), s.readAuditLog},
+		{http.MethodGet, path("notifications"), s.streamNotifications},
+
+		// Fixed segments are listed before the ID ones they would also match
+		{http.MethodGet, path("applications"), s.readApplications},
+		{http.MethodPost, path("applications"), s.writeApplication},
+		{http.MethodPut, path("applications", "first-date-surpassed"), s.updateAppFirstDateSurpassed},
+		{http.MethodGet, path("applications", "{id}"), s.readApplication},
+		{http.MethodPut, path("applications", "{id}"), s.updateApplication},
+		{http.MethodDelete, path("applications", "{id}"), s.removeApplication},
+		{http.MethodGet, path("applications", "{id}", "plan-history"), s.readAppPlanHistory},
+		{http.MethodGet, path("applications", "{id}", "plan"), s.readAppPlanAt},
+		{http.MethodPost, path("applications", "{id}", "secret-keys", "{name}", "rotate"), s.rotateSecretKey},
+		{http.MethodDelete, path("applications", "{id}", "secret-keys", "{name}"), s.revokeSecretKey},
+		{http.MethodPost, path("applications", "{id}", "usage"), s.incrementUsage},
+		{http.MethodPost, path("applications", "{id}", "usage-thresholds"), s.evaluateUsageThresholds},
+
+		{http.MethodGet, path("usage"), s.readUsage},
+		{http.MethodPost, path("usage"), s.flushUsage},
+		{http.MethodGet, path("usage", "over-limit"), s.readApplicationsOverLimit},
+
+		{http.MethodGet, path("load-balancers"), s.readLoadBalancers},
+		{http.MethodPost, path("load-balancers"), s.writeLoadBalancer},
+		{http.MethodGet, path("load-balancers", "{id}"), s.readLoadBalancer},
+		{http.MethodPut, path("load-balancers", "{id}"), s.updateLoadBalancer},
+		{http.MethodDelete, path("load-balancers", "{id}"), s.removeLoadBalancer},
+		{http.MethodPost, path("load-balancers", "{id}", "users"), s.writeLoadBalancerUser},
+		{http.MethodPut, path("load-balancers", "{id}", "users"), s.updateLoadBalancerUser},
+		{http.MethodPut, path("load-balancers", "{id}", "users", "{userID}", "role"), s.updateUserAccessRole},
+		{http.MethodDelete, path("load-balancers", "{id}", "users", "{userID}"), s.removeUserAccess},
+		{http.MethodPost, path("load-balancers", "{id}", "invitations"), s.writeLoadBalancerInvitation},
+		{http.MethodPost, path("load-balancers", "{id}", "transfer"), s.transferLoadBalancerOwnership},
+
+		{http.MethodGet, path("invitations"), s.readPendingInvitations},
+		{http.MethodDelete, path("invitations", "expired"), s.removeExpiredInvitations},
+		{http.MethodPost, path("invitations", "{token}", "accept"), s.acceptInvitation},
+		{http.MethodPost, path("invitations", "{token}", "decline"), s.declineInvitation},
+
+		{http.MethodGet, path("blockchains"), s.readBlockchains},
+		{http.MethodPost, path("blockchains"), s.writeBlockchain},
+		{http.MethodGet, path("blockchains", "{id}"), s.readBlockchain},
+		{http.MethodPut, path("blockchains", "{id}", "active"), s.activateChain},
+		{http.MethodPost, path("redirects"), s.writeRedirect},
+	}
+
+	return s, nil
+}
+
+func path(segments ...string) []string {
+	return segments
+}
+
+/* Close stops streaming notifications and ends the open notification streams */
+func (s *Server) Close() {
+	s.broker.close()
+}
+
+/* ServeHTTP authenticates the request and calls the handler of its route */
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authenticated, admin := s.authenticate(r)
+	if !authenticated {
+		s.writeError(w, httpapi.ErrUnauthorized)
+		return
+	}
+
+	// The escaped path is split so IDs may hold escaped slashes, empty IDs are passed on for the driver to reject
+	segments := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), "/"), "/")
+
+	methodAllowed := false
+	for _, route := range s.routes {
+		params, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		if route.method != r.Method {
+			methodAllowed = true
+			continue
+		}
+
+		route.handle(w, r.WithContext(requestContext(r, admin)), params)
+		return
+	}
+
+	if methodAllowed {
+		s.writeError(w, httpapi.ErrMethodNotAllowed)
+		return
+	}
+	s.writeError(w, httpapi.ErrRouteNotFound)
+}
+
+/* authenticate returns whether the request holds one of the API keys and whether it is an admin one */
+func (s *Server) authenticate(r *http.Request) (authenticated, admin bool) {
+	apiKey := []byte(r.Header.Get(httpapi.APIKeyHeader))
+	for _, allowed := range s.adminAPIKeys {
+		if subtle.ConstantTimeCompare(apiKey, allowed) == 1 {
+			return true, true
+		}
+	}
+	for _, allowed := range s.apiKeys {
+		if subtle.ConstantTimeCompare(apiKey, allowed) == 1 {
+			return true, false
+		}
+	}
+
+	return false, false
+}
+
+/*
+requestContext returns the request's context carrying the actor set in its headers,
+and the status override for requests authenticated with an admin API key
+*/
+func requestContext(r *http.Request, admin bool) context.Context {
+	ctx := r.Context()
+	if actor := r.Header.Get(httpapi.ActorHeader); actor != "" {
+		ctx = types.WithActor(ctx, actor)
+	}
+	if override, _ := strconv.ParseBool(r.Header.Get(httpapi.StatusOverrideHeader)); override && admin {
+		ctx = types.WithStatusOverride(ctx)
+	}
+
+	return ctx
+}
+
+func (rt route) match(segments []string) ([]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+
+	var params []string
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, "{") {
+			param, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			params = append(params, param)
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+/* decodeBody decodes the JSON body of the request into v, unknown fields are rejected */
+func decodeBody(r *http.Request, v any) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err != nil {
+		return fmt.Errorf("%w: %s", httpapi.ErrInvalidBody, err)
+	}
+
+	return nil
+}
+
+/* queryTime parses an RFC 3339 time query parameter, missing ones are the zero time */
+func queryTime(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s: %s", httpapi.ErrInvalidParameter, name, err)
+	}
+
+	return parsed, nil
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		s.reportProblem(err)
+	}
+}
+
+/* writeResult writes v as the response if err is nil and the httpapi.ErrorResponse of err otherwise */
+func (s *Server) writeResult(w http.ResponseWriter, status int, v any, err error) {
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, status, v)
+}
+
+/* writeNoContent answers with no content if err is nil and the httpapi.ErrorResponse of err otherwise */
+func (s *Server) writeNoContent(w http.ResponseWriter, err error) {
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	response, status := httpapi.NewErrorResponse(err)
+	if status == http.StatusInternalServerError {
+		s.reportProblem(err)
+	}
+
+	s.writeJSON(w, status, response)
+}
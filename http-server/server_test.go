@@ -0,0 +1,243 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	memorydriver "github.com/vishruthsk/portal-db-main/memory-driver"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const (
+	testAPIKey      = "test_api_key"       // pragma: allowlist secret
+	testAdminAPIKey = "test_admin_api_key" // pragma: allowlist secret
+)
+
+var _ http.Handler = &Server{}
+
+/* newTestServer returns an httptest server serving a Server for a new MemoryDriver */
+func newTestServer(t *testing.T) (*httptest.Server, *memorydriver.MemoryDriver) {
+	t.Helper()
+
+	memoryDriver := memorydriver.NewMemoryDriver()
+	server, err := NewServer(memoryDriver, []string{testAPIKey}, []string{testAdminAPIKey}, func(err error) { t.Log(err) })
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		server.Close()
+		httpServer.Close()
+	})
+
+	return httpServer, memoryDriver
+}
+
+/* doRequest sends the request with the test API key and decodes the response body into out if it is not nil */
+func doRequest(t *testing.T, server *httptest.Server, method, path string, body, out any) *http.Response {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequest(method, server.URL+path, reader)
+	require.NoError(t, err)
+	req.Header.Set(httpapi.APIKeyHeader, testAPIKey)
+
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	return resp
+}
+
+func testApplication() *types.Application {
+	return &types.Application{
+		Name:   "pokt_app_http",
+		UserID: "test_user_http",
+		Status: types.InService,
+		Limit: types.AppLimit{
+			PayPlan: types.PayPlan{Type: types.FreetierV0},
+		},
+	}
+}
+
+func Test_NewServer(t *testing.T) {
+	c := require.New(t)
+
+	_, err := NewServer(memorydriver.NewMemoryDriver(), nil, nil, nil)
+	c.ErrorIs(err, ErrNoAPIKeys)
+
+	_, err = NewServer(memorydriver.NewMemoryDriver(), []string{""}, nil, nil)
+	c.ErrorIs(err, ErrNoAPIKeys)
+
+	_, err = NewServer(memorydriver.NewMemoryDriver(), []string{testAPIKey}, []string{""}, nil)
+	c.ErrorIs(err, ErrNoAPIKeys)
+
+	server, err := NewServer(memorydriver.NewMemoryDriver(), nil, []string{testAdminAPIKey}, nil)
+	c.NoError(err)
+	server.Close()
+}
+
+func Test_Authentication(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		expectedStatus int
+	}{
+		{
+			name:           "Should read with a valid API key",
+			apiKey:         testAPIKey,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Should read with an admin API key",
+			apiKey:         testAdminAPIKey,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Should fail without an API key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Should fail with an invalid API key",
+			apiKey:         "not_the_key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/pay-plans", nil)
+			c.NoError(err)
+			req.Header.Set(httpapi.APIKeyHeader, test.apiKey)
+
+			resp, err := server.Client().Do(req)
+			c.NoError(err)
+			defer resp.Body.Close()
+
+			c.Equal(test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func Test_StatusOverride(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		expectedStatus int
+	}{
+		{
+			name:           "Should ignore the status override of an API key",
+			apiKey:         testAPIKey,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Should allow any status transition with an admin API key",
+			apiKey:         testAdminAPIKey,
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			var app types.Application
+			resp := doRequest(t, server, http.MethodPost, "/applications", testApplication(), &app)
+			c.Equal(http.StatusCreated, resp.StatusCode)
+
+			content, err := json.Marshal(&types.UpdateApplication{Status: types.Decomissioned})
+			c.NoError(err)
+			req, err := http.NewRequest(http.MethodPut, server.URL+"/applications/"+app.ID, bytes.NewReader(content))
+			c.NoError(err)
+			req.Header.Set(httpapi.APIKeyHeader, test.apiKey)
+			req.Header.Set(httpapi.StatusOverrideHeader, "true")
+
+			resp, err = server.Client().Do(req)
+			c.NoError(err)
+			defer resp.Body.Close()
+
+			c.Equal(test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func Test_Routes(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "Should fail on an unknown route",
+			method:         http.MethodGet,
+			path:           "/not-a-route",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   httpapi.CodeRouteNotFound,
+		},
+		{
+			name:           "Should fail on a method the route does not allow",
+			method:         http.MethodPatch,
+			path:           "/applications",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedCode:   httpapi.CodeMethodNotAllowed,
+		},
+		{
+			name:           "Should fail on a malformed body",
+			method:         http.MethodPost,
+			path:           "/applications",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   httpapi.CodeInvalidBody,
+		},
+		{
+			name:           "Should fail on a malformed query parameter",
+			method:         http.MethodGet,
+			path:           "/usage?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   httpapi.CodeInvalidParameter,
+		},
+		{
+			name:           "Should pass empty IDs on to the driver",
+			method:         http.MethodDelete,
+			path:           "/applications/",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   "missing_id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			var response httpapi.ErrorResponse
+			resp := doRequest(t, server, test.method, test.path, nil, &response)
+
+			c.Equal(test.expectedStatus, resp.StatusCode)
+			c.Equal(test.expectedCode, response.Code)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	httpapi "github.com/vishruthsk/portal-db-main/http-api"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* readUsage answers with the relay usage matching the applicationID, from and to query parameters */
+func (s *Server) readUsage(w http.ResponseWriter, r *http.Request, params []string) {
+	from, err := queryTime(r, "from")
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	to, err := queryTime(r, "to")
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	usage, err := s.driver.ReadUsage(r.Context(), types.RelayUsageFilter{
+		ApplicationID: r.URL.Query().Get("applicationID"),
+		From:          from,
+		To:            to,
+	})
+	s.writeResult(w, http.StatusOK, usage, err)
+}
+
+func (s *Server) readApplicationsOverLimit(w http.ResponseWriter, r *http.Request, params []string) {
+	usage, err := s.driver.ReadApplicationsOverLimit(r.Context())
+	s.writeResult(w, http.StatusOK, usage, err)
+}
+
+func (s *Server) incrementUsage(w http.ResponseWriter, r *http.Request, params []string) {
+	var request httpapi.IncrementUsageRequest
+	err := decodeBody(r, &request)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	relays, err := s.driver.IncrementUsage(r.Context(), params[0], request.Relays)
+	s.writeResult(w, http.StatusOK, &httpapi.IncrementUsageResponse{Relays: relays}, err)
+}
+
+func (s *Server) flushUsage(w http.ResponseWriter, r *http.Request, params []string) {
+	var usage []types.RelayUsage
+	err := decodeBody(r, &usage)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeNoContent(w, s.driver.FlushUsage(r.Context(), usage))
+}
+
+func (s *Server) evaluateUsageThresholds(w http.ResponseWriter, r *http.Request, params []string) {
+	events, err := s.driver.EvaluateUsageThresholds(r.Context(), params[0])
+	s.writeResult(w, http.StatusOK, events, err)
+}
+
+/* readAuditLog answers with the audit log entries matching the entityType, entityID, actorID and limit query parameters */
+func (s *Server) readAuditLog(w http.ResponseWriter, r *http.Request, params []string) {
+	query := r.URL.Query()
+
+	filter := types.AuditLogFilter{
+		EntityType: types.Table(query.Get("entityType")),
+		EntityID:   query.Get("entityID"),
+		ActorID:    query.Get("actorID"),
+	}
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			s.writeError(w, fmt.Errorf("%w: limit: %s", httpapi.ErrInvalidParameter, err))
+			return
+		}
+	}
+
+	entries, err := s.driver.ReadAuditLog(r.Context(), filter)
+	s.writeResult(w, http.StatusOK, entries, err)
+}
@@ -0,0 +1,724 @@
+package memorydriver
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+type (
+	applicationRow struct {
+		ApplicationID      string    `json:"application_id"`
+		ContactEmail       string    `json:"contact_email"`
+		Description        string    `json:"description"`
+		Name               string    `json:"name"`
+		Status             string    `json:"status"`
+		Owner              string    `json:"owner"`
+		URL                string    `json:"url"`
+		UserID             string    `json:"user_id"`
+		Dummy              bool      `json:"dummy"`
+		FirstDateSurpassed timestamp `json:"first_date_surpassed"`
+		Version            int       `json:"version"`
+		CreatedAt          timestamp `json:"created_at"`
+		UpdatedAt          timestamp `json:"updated_at"`
+	}
+	appLimitRow struct {
+		ApplicationID string `json:"application_id"`
+		PayPlan       string `json:"pay_plan"`
+		CustomLimit   *int   `json:"custom_limit"`
+	}
+	gatewayAATRow struct {
+		ApplicationID   string `json:"application_id"`
+		Address         string `json:"address"`
+		PublicKey       string `json:"public_key"`
+		PrivateKey      string `json:"private_key"`
+		Signature       string `json:"signature"`
+		ClientPublicKey string `json:"client_public_key"`
+		Version         string `json:"version"`
+	}
+	gatewaySettingsRow struct {
+		ApplicationID        string   `json:"application_id"`
+		SecretKey            *string  `json:"secret_key"`
+		SecretKeyRequired    bool     `json:"secret_key_required"`
+		WhitelistBlockchains []string `json:"whitelist_blockchains"`
+		WhitelistOrigins     []string `json:"whitelist_origins"`
+		WhitelistUserAgents  []string `json:"whitelist_user_agents"`
+	}
+	whitelistContractRow struct {
+		ApplicationID string   `json:"application_id"`
+		BlockchainID  string   `json:"blockchain_id"`
+		Contracts     []string `json:"contracts"`
+	}
+	whitelistMethodRow struct {
+		ApplicationID string   `json:"application_id"`
+		BlockchainID  string   `json:"blockchain_id"`
+		Methods       []string `json:"methods"`
+	}
+	notificationSettingsRow struct {
+		ApplicationID   string `json:"application_id"`
+		SignedUp        bool   `json:"signed_up"`
+		OnQuarter       bool   `json:"on_quarter"`
+		OnHalf          bool   `json:"on_half"`
+		OnThreeQuarters bool   `json:"on_three_quarters"`
+		OnFull          bool   `json:"on_full"`
+	}
+)
+
+/* ReadPayPlans returns all pay plans ordered by type */
+func (m *MemoryDriver) ReadPayPlans(ctx context.Context) ([]*types.PayPlan, error) {
+	var payPlans []*types.PayPlan
+
+	err := m.read(ctx, func(s *state) error {
+		for planType, limit := range s.payPlans {
+			payPlan := types.PayPlan{Type: planType, Limit: limit}
+
+			err := payPlan.Validate()
+			if err != nil {
+				return err
+			}
+
+			payPlans = append(payPlans, &payPlan)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(payPlans, func(i, j int) bool { return payPlans[i].Type < payPlans[j].Type })
+
+	return payPlans, nil
+}
+
+/*
+ReadApplications returns all Applications ordered by ID.
+Their whitelisted contracts and methods keep their BlockchainID, which the PostgresDriver does not read back.
+*/
+func (m *MemoryDriver) ReadApplications(ctx context.Context) ([]*types.Application, error) {
+	var applications []*types.Application
+
+	err := m.read(ctx, func(s *state) error {
+		for _, app := range s.applications {
+			applications = append(applications, s.toApplication(app))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(applications, func(i, j int) bool { return applications[i].ID < applications[j].ID })
+
+	return applications, nil
+}
+
+func (s *state) toApplication(app *applicationRow) *types.Application {
+	application := &types.Application{
+		ID:                 app.ApplicationID,
+		UserID:             app.UserID,
+		Name:               app.Name,
+		Status:             types.AppStatus(app.Status),
+		ContactEmail:       app.ContactEmail,
+		Description:        app.Description,
+		Owner:              app.Owner,
+		URL:                app.URL,
+		Dummy:              app.Dummy,
+		FirstDateSurpassed: app.FirstDateSurpassed.Time,
+		Version:            app.Version,
+		CreatedAt:          app.CreatedAt.Time,
+		UpdatedAt:          app.UpdatedAt.Time,
+	}
+
+	if aat, ok := s.gatewayAAT[app.ApplicationID]; ok {
+		application.GatewayAAT = types.GatewayAAT{
+			Address:              aat.Address,
+			ApplicationPublicKey: aat.PublicKey,
+			ApplicationSignature: aat.Signature,
+			ClientPublicKey:      aat.ClientPublicKey,
+			PrivateKey:           aat.PrivateKey,
+			Version:              aat.Version,
+		}
+	}
+
+	if settings, ok := s.gatewaySettings[app.ApplicationID]; ok {
+		application.GatewaySettings = types.GatewaySettings{
+			SecretKey:            stringValue(settings.SecretKey),
+			SecretKeyRequired:    settings.SecretKeyRequired,
+			WhitelistBlockchains: copyStrings(settings.WhitelistBlockchains),
+			WhitelistOrigins:     copyStrings(settings.WhitelistOrigins),
+			WhitelistUserAgents:  copyStrings(settings.WhitelistUserAgents),
+		}
+	}
+	application.GatewaySettings.SecretKeys = s.applicationSecretKeys(app.ApplicationID)
+	for _, contract := range s.whitelistContracts {
+		if contract.ApplicationID == app.ApplicationID {
+			application.GatewaySettings.WhitelistContracts = append(application.GatewaySettings.WhitelistContracts, types.WhitelistContract{
+				BlockchainID: contract.BlockchainID,
+				Contracts:    copyStrings(contract.Contracts),
+			})
+		}
+	}
+	for _, method := range s.whitelistMethods {
+		if method.ApplicationID == app.ApplicationID {
+			application.GatewaySettings.WhitelistMethods = append(application.GatewaySettings.WhitelistMethods, types.WhitelistMethod{
+				BlockchainID: method.BlockchainID,
+				Methods:      copyStrings(method.Methods),
+			})
+		}
+	}
+
+	if limit, ok := s.appLimits[app.ApplicationID]; ok {
+		application.Limit = types.AppLimit{
+			PayPlan: types.PayPlan{
+				Type:  types.PayPlanType(limit.PayPlan),
+				Limit: s.payPlans[types.PayPlanType(limit.PayPlan)],
+			},
+			CustomLimit: intValue(limit.CustomLimit),
+		}
+	}
+
+	if settings, ok := s.notificationSettings[app.ApplicationID]; ok {
+		application.NotificationSettings = types.NotificationSettings{
+			SignedUp:      settings.SignedUp,
+			Quarter:       settings.OnQuarter,
+			Half:          settings.OnHalf,
+			ThreeQuarters: settings.OnThreeQuarters,
+			Full:          settings.OnFull,
+		}
+	}
+
+	return application
+}
+
+/* WriteApplication saves input Application and returns it with its generated secret key, which is not readable afterwards */
+func (m *MemoryDriver) WriteApplication(ctx context.Context, app *types.Application) (*types.Application, error) {
+	appIsInvalid := app.Validate()
+	if appIsInvalid != nil {
+		return nil, appIsInvalid
+	}
+
+	id, err := generateRandomID()
+	if err != nil {
+		return nil, err
+	}
+
+	secretKey, err := generateRandomSecretKey()
+	if err != nil {
+		return nil, err
+	}
+	hashedSecretKey, err := types.HashSecretKey(secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	app.ID = id
+	now := time.Now()
+	app.CreatedAt = now
+	app.UpdatedAt = now
+	// The generated secret key is only ever returned here, the MemoryDriver holds its hash
+	app.GatewaySettings.SecretKey = secretKey
+	app.GatewaySettings.SecretKeys = []types.SecretKey{{
+		ID:        id,
+		Name:      defaultSecretKeyName,
+		Key:       secretKey,
+		CreatedAt: now,
+	}}
+
+	err = m.write(ctx, func(tx *transaction) error {
+		err := tx.state.validateWhitelistBlockchains(app.GatewaySettings.WhitelistContracts, app.GatewaySettings.WhitelistMethods)
+		if err != nil {
+			return err
+		}
+
+		appRow := &applicationRow{
+			ApplicationID: id,
+			ContactEmail:  app.ContactEmail,
+			Description:   app.Description,
+			Name:          app.Name,
+			Status:        string(app.Status),
+			Owner:         app.Owner,
+			URL:           app.URL,
+			UserID:        app.UserID,
+			Dummy:         app.Dummy,
+			Version:       1,
+			CreatedAt:     newTimestamp(now),
+			UpdatedAt:     newTimestamp(now),
+		}
+		tx.state.applications[id] = appRow
+		tx.changed(types.ActionInsert, nil, appRow)
+
+		err = tx.upsertAppLimit(&appLimitRow{
+			ApplicationID: id,
+			PayPlan:       string(app.Limit.PayPlan.Type),
+			CustomLimit:   nullableInt(app.Limit.CustomLimit, false),
+		})
+		if err != nil {
+			return err
+		}
+		tx.recordAppPlanChange(id, now)
+
+		if app.GatewayAAT.Version != "" || app.GatewayAAT.PrivateKey != "" {
+			aatRow := &gatewayAATRow{
+				ApplicationID:   id,
+				Address:         app.GatewayAAT.Address,
+				PublicKey:       app.GatewayAAT.ApplicationPublicKey,
+				PrivateKey:      app.GatewayAAT.PrivateKey,
+				Signature:       app.GatewayAAT.ApplicationSignature,
+				ClientPublicKey: app.GatewayAAT.ClientPublicKey,
+				Version:         app.GatewayAAT.Version,
+			}
+			tx.state.gatewayAAT[id] = aatRow
+			tx.changed(types.ActionInsert, nil, aatRow)
+		}
+
+		tx.upsertGatewaySettings(&gatewaySettingsRow{
+			ApplicationID:     id,
+			SecretKey:         &hashedSecretKey,
+			SecretKeyRequired: app.GatewaySettings.SecretKeyRequired,
+		})
+
+		err = tx.insertSecretKey(&secretKeyRow{
+			ApplicationID: id,
+			Name:          defaultSecretKeyName,
+			SecretKey:     hashedSecretKey,
+			CreatedAt:     newTimestamp(now),
+		})
+		if err != nil {
+			return err
+		}
+
+		tx.upsertNotificationSettings(&notificationSettingsRow{
+			ApplicationID:   id,
+			SignedUp:        app.NotificationSettings.SignedUp,
+			OnQuarter:       app.NotificationSettings.Quarter,
+			OnHalf:          app.NotificationSettings.Half,
+			OnThreeQuarters: app.NotificationSettings.ThreeQuarters,
+			OnFull:          app.NotificationSettings.Full,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+/*
+UpdateApplication updates Application and related table rows.
+
+It returns types.ErrNotFound if the Application does not exist. A new Status must be allowed from the current one
+by types.AppStatusTransitions, unless ctx has a status override
+*/
+func (m *MemoryDriver) UpdateApplication(ctx context.Context, id string, update *types.UpdateApplication) error {
+	if id == "" {
+		return types.ErrMissingID
+	}
+
+	invalidUpdate := update.Validate()
+	if invalidUpdate != nil {
+		return invalidUpdate
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		app, ok := s.applications[id]
+		if !ok {
+			return types.ErrNotFound
+		}
+		err := checkStatusTransition(ctx, types.AppStatus(app.Status), update.Status)
+		if err != nil {
+			return err
+		}
+
+		if update.GatewaySettings != nil {
+			err = s.validateWhitelistBlockchains(update.GatewaySettings.WhitelistContracts, update.GatewaySettings.WhitelistMethods)
+			if err != nil {
+				return err
+			}
+		}
+
+		if update.ExpectedVersion != 0 && update.ExpectedVersion != app.Version {
+			return types.ErrConflict
+		}
+
+		updatedApp := *app
+		if update.Name != "" {
+			updatedApp.Name = update.Name
+		}
+		if update.Status != "" {
+			updatedApp.Status = string(update.Status)
+		}
+		if !update.FirstDateSurpassed.IsZero() {
+			updatedApp.FirstDateSurpassed = newTimestamp(update.FirstDateSurpassed)
+		}
+		updatedApp.Version++
+		s.applications[id] = &updatedApp
+		tx.changed(types.ActionUpdate, app, &updatedApp)
+
+		if update.Limit != nil {
+			customLimit := update.Limit.CustomLimit
+			if update.Limit.PayPlan.Type != types.Enterprise {
+				customLimit = 0
+			}
+
+			err = tx.upsertAppLimit(&appLimitRow{
+				ApplicationID: id,
+				PayPlan:       string(update.Limit.PayPlan.Type),
+				CustomLimit:   nullableInt(customLimit, true),
+			})
+			if err != nil {
+				return err
+			}
+			tx.recordAppPlanChange(id, time.Now())
+		}
+
+		if settings := update.GatewaySettings; settings != nil {
+			if settings.SecretKeyRequired != nil || len(settings.WhitelistOrigins) != 0 ||
+				len(settings.WhitelistUserAgents) != 0 || len(settings.WhitelistBlockchains) != 0 {
+				updatedSettings := &gatewaySettingsRow{ApplicationID: id}
+				if current, ok := s.gatewaySettings[id]; ok {
+					copied := *current
+					updatedSettings = &copied
+				}
+				if settings.SecretKeyRequired != nil {
+					updatedSettings.SecretKeyRequired = *settings.SecretKeyRequired
+				}
+				if settings.WhitelistOrigins != nil {
+					updatedSettings.WhitelistOrigins = copyStrings(settings.WhitelistOrigins)
+				}
+				if settings.WhitelistUserAgents != nil {
+					updatedSettings.WhitelistUserAgents = copyStrings(settings.WhitelistUserAgents)
+				}
+				if settings.WhitelistBlockchains != nil {
+					updatedSettings.WhitelistBlockchains = copyStrings(settings.WhitelistBlockchains)
+				}
+				tx.upsertGatewaySettings(updatedSettings)
+			}
+
+			for _, contract := range settings.WhitelistContracts {
+				if len(contract.Contracts) != 0 {
+					tx.upsertWhitelistContract(&whitelistContractRow{
+						ApplicationID: id,
+						BlockchainID:  contract.BlockchainID,
+						Contracts:     copyStrings(contract.Contracts),
+					})
+				}
+			}
+			for _, method := range settings.WhitelistMethods {
+				if len(method.Methods) != 0 {
+					tx.upsertWhitelistMethod(&whitelistMethodRow{
+						ApplicationID: id,
+						BlockchainID:  method.BlockchainID,
+						Methods:       copyStrings(method.Methods),
+					})
+				}
+			}
+		}
+
+		if settings := update.NotificationSettings; settings != nil {
+			updatedSettings := &notificationSettingsRow{ApplicationID: id}
+			if current, ok := s.notificationSettings[id]; ok {
+				copied := *current
+				updatedSettings = &copied
+			}
+			setBool(&updatedSettings.SignedUp, settings.SignedUp)
+			setBool(&updatedSettings.OnQuarter, settings.Quarter)
+			setBool(&updatedSettings.OnHalf, settings.Half)
+			setBool(&updatedSettings.OnThreeQuarters, settings.ThreeQuarters)
+			setBool(&updatedSettings.OnFull, settings.Full)
+			tx.upsertNotificationSettings(updatedSettings)
+		}
+
+		return nil
+	})
+}
+
+/*
+UpdateAppFirstDateSurpassed updates Application's firstDateSurpassed field, nothing is updated if any of the IDs does not exist.
+IncrementUsage and FlushUsage already set it the first time an Application goes over its DailyLimit.
+*/
+func (m *MemoryDriver) UpdateAppFirstDateSurpassed(ctx context.Context, update *types.UpdateFirstDateSurpassed) error {
+	return m.write(ctx, func(tx *transaction) error {
+		updated := make(map[string]bool, len(update.ApplicationIDs))
+		for _, id := range update.ApplicationIDs {
+			app, ok := tx.state.applications[id]
+			if !ok || updated[id] {
+				continue
+			}
+
+			updatedApp := *app
+			updatedApp.FirstDateSurpassed = newTimestamp(update.FirstDateSurpassed)
+			tx.state.applications[id] = &updatedApp
+			tx.changed(types.ActionUpdate, app, &updatedApp)
+			updated[id] = true
+		}
+
+		if len(updated) < countUnique(update.ApplicationIDs) {
+			return types.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+/*
+RemoveApplication updates Application's status field to AwaitingGracePeriod.
+
+It returns types.ErrNotFound if the Application does not exist and ErrInvalidStatusTransition if it cannot be removed
+from its current status, unless ctx has a status override
+*/
+func (m *MemoryDriver) RemoveApplication(ctx context.Context, id string) error {
+	if id == "" {
+		return types.ErrMissingID
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		app, ok := tx.state.applications[id]
+		if !ok {
+			return types.ErrNotFound
+		}
+		err := checkStatusTransition(ctx, types.AppStatus(app.Status), types.AwaitingGracePeriod)
+		if err != nil {
+			return err
+		}
+
+		updatedApp := *app
+		updatedApp.Status = string(types.AwaitingGracePeriod)
+		tx.state.applications[id] = &updatedApp
+		tx.changed(types.ActionUpdate, app, &updatedApp)
+
+		return nil
+	})
+}
+
+/*
+validateWhitelistBlockchains returns a ValidationError if a whitelisted contract or method refers to a blockchain that does not exist,
+or a contract of an EVM blockchain is not a valid address
+*/
+func (s *state) validateWhitelistBlockchains(contracts []types.WhitelistContract, methods []types.WhitelistMethod) error {
+	if len(contracts) == 0 && len(methods) == 0 {
+		return nil
+	}
+
+	blockchains := make(map[string]*types.Blockchain, len(s.blockchains))
+	for blockchainID, blockchain := range s.blockchains {
+		blockchains[blockchainID] = &types.Blockchain{ID: blockchainID, ChainIDCheck: blockchain.ChainIDCheck}
+	}
+
+	errs := &types.ValidationError{}
+	errs.Merge("gatewaySettings", types.ValidateWhitelistBlockchains(contracts, methods, blockchains))
+
+	return errs.ErrorOrNil()
+}
+
+/* checkStatusTransition returns ErrInvalidStatusTransition if an Application cannot move to status, contexts returned by types.WithStatusOverride skip the check */
+func checkStatusTransition(ctx context.Context, currentStatus, status types.AppStatus) error {
+	if types.StatusOverrideFromContext(ctx) {
+		return nil
+	}
+
+	return types.ValidateStatusTransition(currentStatus, status)
+}
+
+func (t *transaction) upsertAppLimit(limit *appLimitRow) error {
+	if _, ok := t.state.payPlans[types.PayPlanType(limit.PayPlan)]; !ok {
+		return referenceViolation(types.TableAppLimits, "fk_pay_plan")
+	}
+
+	current, ok := t.state.appLimits[limit.ApplicationID]
+	t.state.appLimits[limit.ApplicationID] = limit
+	if !ok {
+		t.changed(types.ActionInsert, nil, limit)
+		return nil
+	}
+
+	t.changed(types.ActionUpdate, current, limit)
+	return nil
+}
+
+func (t *transaction) upsertGatewaySettings(settings *gatewaySettingsRow) {
+	current, ok := t.state.gatewaySettings[settings.ApplicationID]
+	t.state.gatewaySettings[settings.ApplicationID] = settings
+	if !ok {
+		t.changed(types.ActionInsert, nil, settings)
+		return
+	}
+
+	t.changed(types.ActionUpdate, current, settings)
+}
+
+func (t *transaction) upsertWhitelistContract(contract *whitelistContractRow) {
+	for i, current := range t.state.whitelistContracts {
+		if current.ApplicationID == contract.ApplicationID && current.BlockchainID == contract.BlockchainID {
+			t.state.whitelistContracts[i] = contract
+			t.changed(types.ActionUpdate, current, contract)
+			return
+		}
+	}
+
+	t.state.whitelistContracts = append(t.state.whitelistContracts, contract)
+	t.changed(types.ActionInsert, nil, contract)
+}
+
+func (t *transaction) upsertWhitelistMethod(method *whitelistMethodRow) {
+	for i, current := range t.state.whitelistMethods {
+		if current.ApplicationID == method.ApplicationID && current.BlockchainID == method.BlockchainID {
+			t.state.whitelistMethods[i] = method
+			t.changed(types.ActionUpdate, current, method)
+			return
+		}
+	}
+
+	t.state.whitelistMethods = append(t.state.whitelistMethods, method)
+	t.changed(types.ActionInsert, nil, method)
+}
+
+func (t *transaction) upsertNotificationSettings(settings *notificationSettingsRow) {
+	current, ok := t.state.notificationSettings[settings.ApplicationID]
+	t.state.notificationSettings[settings.ApplicationID] = settings
+	if !ok {
+		t.changed(types.ActionInsert, nil, settings)
+		return
+	}
+
+	t.changed(types.ActionUpdate, current, settings)
+}
+
+func countUnique(values []string) int {
+	unique := make(map[string]bool, len(values))
+	for _, value := range values {
+		unique[value] = true
+	}
+
+	return len(unique)
+}
+
+/* nullableInt returns the value an INT column stores, zero is NULL unless allowZero is set */
+func nullableInt(value int, allowZero bool) *int {
+	if value == 0 && !allowZero {
+		return nil
+	}
+
+	return &value
+}
+
+func intValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+
+	return *value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func setBool(value *bool, update *bool) {
+	if update != nil {
+		*value = *update
+	}
+}
+
+func (r *applicationRow) table() types.Table { return types.TableApplications }
+func (r *applicationRow) entityID() string   { return r.ApplicationID }
+func (r *applicationRow) toOutput() types.SavedOnDB {
+	return &types.Application{
+		ID:                 r.ApplicationID,
+		UserID:             r.UserID,
+		Name:               r.Name,
+		ContactEmail:       r.ContactEmail,
+		Description:        r.Description,
+		Owner:              r.Owner,
+		URL:                r.URL,
+		Status:             types.AppStatus(r.Status),
+		CreatedAt:          r.CreatedAt.Time,
+		UpdatedAt:          r.UpdatedAt.Time,
+		FirstDateSurpassed: r.FirstDateSurpassed.Time,
+		Dummy:              r.Dummy,
+		Version:            r.Version,
+	}
+}
+
+func (r *appLimitRow) table() types.Table { return types.TableAppLimits }
+func (r *appLimitRow) entityID() string   { return r.ApplicationID }
+func (r *appLimitRow) toOutput() types.SavedOnDB {
+	return &types.AppLimit{
+		ID: r.ApplicationID,
+		PayPlan: types.PayPlan{
+			Type: types.PayPlanType(r.PayPlan),
+		},
+		CustomLimit: intValue(r.CustomLimit),
+	}
+}
+
+func (r *gatewayAATRow) table() types.Table { return types.TableGatewayAAT }
+func (r *gatewayAATRow) entityID() string   { return r.ApplicationID }
+func (r *gatewayAATRow) toOutput() types.SavedOnDB {
+	return &types.GatewayAAT{
+		ID:                   r.ApplicationID,
+		Address:              r.Address,
+		ClientPublicKey:      r.ClientPublicKey,
+		PrivateKey:           r.PrivateKey,
+		ApplicationPublicKey: r.PublicKey,
+		ApplicationSignature: r.Signature,
+		Version:              r.Version,
+	}
+}
+
+func (r *gatewaySettingsRow) table() types.Table { return types.TableGatewaySettings }
+func (r *gatewaySettingsRow) entityID() string   { return r.ApplicationID }
+func (r *gatewaySettingsRow) toOutput() types.SavedOnDB {
+	return &types.GatewaySettings{
+		ID:                   r.ApplicationID,
+		SecretKey:            stringValue(r.SecretKey),
+		SecretKeyRequired:    r.SecretKeyRequired,
+		WhitelistOrigins:     copyStrings(r.WhitelistOrigins),
+		WhitelistUserAgents:  copyStrings(r.WhitelistUserAgents),
+		WhitelistBlockchains: copyStrings(r.WhitelistBlockchains),
+	}
+}
+
+func (r *whitelistContractRow) table() types.Table { return types.TableWhitelistContracts }
+func (r *whitelistContractRow) entityID() string   { return r.ApplicationID }
+func (r *whitelistContractRow) toOutput() types.SavedOnDB {
+	return &types.WhitelistContract{
+		ID:           r.ApplicationID,
+		BlockchainID: r.BlockchainID,
+		Contracts:    copyStrings(r.Contracts),
+	}
+}
+
+func (r *whitelistMethodRow) table() types.Table { return types.TableWhitelistMethods }
+func (r *whitelistMethodRow) entityID() string   { return r.ApplicationID }
+func (r *whitelistMethodRow) toOutput() types.SavedOnDB {
+	return &types.WhitelistMethod{
+		ID:           r.ApplicationID,
+		BlockchainID: r.BlockchainID,
+		Methods:      copyStrings(r.Methods),
+	}
+}
+
+func (r *notificationSettingsRow) table() types.Table { return types.TableNotificationSettings }
+func (r *notificationSettingsRow) entityID() string   { return r.ApplicationID }
+func (r *notificationSettingsRow) toOutput() types.SavedOnDB {
+	return &types.NotificationSettings{
+		ID:            r.ApplicationID,
+		SignedUp:      r.SignedUp,
+		Quarter:       r.OnQuarter,
+		Half:          r.OnHalf,
+		ThreeQuarters: r.OnThreeQuarters,
+		Full:          r.OnFull,
+	}
+}
@@ -0,0 +1,257 @@
+package memorydriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func Test_WriteApplication(t *testing.T) {
+	tests := []struct {
+		name          string
+		app           *types.Application
+		expectedTypes []types.Table
+		err           error
+	}{
+		{
+			name: "Should write an Application and send a notification for each of its rows",
+			app:  testApplication(),
+			expectedTypes: []types.Table{
+				types.TableApplications, types.TableAppLimits, types.TableGatewaySettings,
+				types.TableSecretKeys, types.TableNotificationSettings,
+			},
+		},
+		{
+			name: "Should also write the gateway AAT if it is set",
+			app: func() *types.Application {
+				app := testApplication()
+				app.GatewayAAT = types.GatewayAAT{
+					Address:    "test_address_memory",
+					PrivateKey: "test_private_key_memory",
+					Version:    "0.0.1",
+				}
+				return app
+			}(),
+			expectedTypes: []types.Table{
+				types.TableApplications, types.TableAppLimits, types.TableGatewayAAT, types.TableGatewaySettings,
+				types.TableSecretKeys, types.TableNotificationSettings,
+			},
+		},
+		{
+			name: "Should fail if the Application is invalid",
+			app: func() *types.Application {
+				app := testApplication()
+				app.Status = "NOT_A_STATUS"
+				return app
+			}(),
+			err: types.ErrInvalidAppStatus,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			m := NewMemoryDriver()
+
+			app, err := m.WriteApplication(testCtx, test.app)
+			c.ErrorIs(err, test.err)
+			if test.err != nil {
+				receiveNotifications(t, m, 0)
+				return
+			}
+
+			c.Len(app.ID, idLength)
+			c.Len(app.GatewaySettings.SecretKey, secretKeyLength)
+			c.Equal(test.expectedTypes, notificationTables(receiveNotifications(t, m, len(test.expectedTypes))))
+
+			apps, err := m.ReadApplications(testCtx)
+			c.NoError(err)
+			c.Len(apps, 1)
+			c.Equal(app.ID, apps[0].ID)
+			c.Equal(1, apps[0].Version)
+			c.Equal(250_000, apps[0].Limit.PayPlan.Limit)
+			c.True(types.VerifySecretKey(apps[0].GatewaySettings.SecretKey, app.GatewaySettings.SecretKey))
+		})
+	}
+}
+
+func Test_UpdateApplication(t *testing.T) {
+	tests := []struct {
+		name                  string
+		ctx                   context.Context
+		id                    string
+		update                *types.UpdateApplication
+		expectedApp           func(c *require.Assertions, app *types.Application)
+		expectedNotifications int
+		err                   error
+	}{
+		{
+			name: "Should update the Application and its related rows",
+			update: &types.UpdateApplication{
+				Name: "vipr_app_memory_updated",
+				GatewaySettings: &types.UpdateGatewaySettings{
+					WhitelistOrigins: []string{"https://www.example.com"},
+				},
+				NotificationSettings: &types.UpdateNotificationSettings{Half: boolPointer(true)},
+				Limit: &types.AppLimit{
+					PayPlan:     types.PayPlan{Type: types.Enterprise},
+					CustomLimit: 4_200_000,
+				},
+			},
+			expectedApp: func(c *require.Assertions, app *types.Application) {
+				c.Equal("vipr_app_memory_updated", app.Name)
+				c.Equal(2, app.Version)
+				c.Equal([]string{"https://www.example.com"}, app.GatewaySettings.WhitelistOrigins)
+				c.True(app.NotificationSettings.Half)
+				c.Equal(4_200_000, app.Limit.DailyLimit())
+			},
+			expectedNotifications: 4,
+		},
+		{
+			name: "Should drop the custom limit of a plan that is not enterprise",
+			update: &types.UpdateApplication{
+				Limit: &types.AppLimit{PayPlan: types.PayPlan{Type: types.PayAsYouGoV0}},
+			},
+			expectedApp: func(c *require.Assertions, app *types.Application) {
+				c.Equal(types.PayAsYouGoV0, app.Limit.PayPlan.Type)
+				c.Zero(app.Limit.CustomLimit)
+			},
+			expectedNotifications: 2,
+		},
+		{
+			name:   "Should fail if the expected version is not the stored one",
+			update: &types.UpdateApplication{Name: "vipr_app_memory_updated", ExpectedVersion: 2},
+			err:    types.ErrConflict,
+		},
+		{
+			name:   "Should fail if the status transition is not allowed",
+			update: &types.UpdateApplication{Status: types.Decomissioned},
+			err:    types.ErrInvalidStatusTransition,
+		},
+		{
+			name:   "Should allow any status transition with an override",
+			ctx:    types.WithStatusOverride(testCtx),
+			update: &types.UpdateApplication{Status: types.Decomissioned},
+			expectedApp: func(c *require.Assertions, app *types.Application) {
+				c.Equal(types.Decomissioned, app.Status)
+			},
+			expectedNotifications: 1,
+		},
+		{
+			name: "Should fail if a whitelisted blockchain does not exist",
+			update: &types.UpdateApplication{
+				GatewaySettings: &types.UpdateGatewaySettings{
+					WhitelistContracts: []types.WhitelistContract{{BlockchainID: "0404", Contracts: []string{"0xdac17f958d2ee523a2206206994597c13d831ec7"}}},
+				},
+			},
+			err: types.ErrUnknownBlockchain,
+		},
+		{
+			name:   "Should fail if the Application does not exist",
+			id:     "not_an_app",
+			update: &types.UpdateApplication{Name: "vipr_app_memory_updated"},
+			err:    types.ErrNotFound,
+		},
+		{
+			name:   "Should fail if the ID is missing",
+			id:     " ",
+			update: &types.UpdateApplication{Name: "vipr_app_memory_updated"},
+			err:    types.ErrMissingID,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			m := NewMemoryDriver()
+			app, err := m.WriteApplication(testCtx, testApplication())
+			c.NoError(err)
+			receiveNotifications(t, m, 5)
+
+			ctx, id := test.ctx, test.id
+			if ctx == nil {
+				ctx = testCtx
+			}
+			switch id {
+			case "":
+				id = app.ID
+			case " ":
+				id = ""
+			}
+
+			err = m.UpdateApplication(ctx, id, test.update)
+			c.ErrorIs(err, test.err)
+			receiveNotifications(t, m, test.expectedNotifications)
+			if test.err != nil {
+				return
+			}
+
+			apps, err := m.ReadApplications(testCtx)
+			c.NoError(err)
+			test.expectedApp(c, apps[0])
+		})
+	}
+}
+
+func Test_RemoveApplication(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	app, err := m.WriteApplication(testCtx, testApplication())
+	c.NoError(err)
+	receiveNotifications(t, m, 5)
+
+	err = m.RemoveApplication(testCtx, app.ID)
+	c.NoError(err)
+
+	notifications := receiveNotifications(t, m, 1)
+	c.Equal(types.ActionUpdate, notifications[0].Action)
+	c.Equal(types.AwaitingGracePeriod, notifications[0].Data.(*types.Application).Status)
+
+	// The Application is kept with its new status
+	apps, err := m.ReadApplications(testCtx)
+	c.NoError(err)
+	c.Len(apps, 1)
+	c.Equal(types.AwaitingGracePeriod, apps[0].Status)
+
+	err = m.RemoveApplication(testCtx, "not_an_app")
+	c.ErrorIs(err, types.ErrNotFound)
+}
+
+func Test_UpdateAppFirstDateSurpassed(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	app, err := m.WriteApplication(testCtx, testApplication())
+	c.NoError(err)
+	receiveNotifications(t, m, 5)
+
+	surpassed := time.Date(2022, time.November, 11, 11, 11, 11, 0, time.UTC)
+
+	err = m.UpdateAppFirstDateSurpassed(testCtx, &types.UpdateFirstDateSurpassed{
+		ApplicationIDs:     []string{app.ID, "not_an_app"},
+		FirstDateSurpassed: surpassed,
+	})
+	c.ErrorIs(err, types.ErrNotFound)
+	receiveNotifications(t, m, 0)
+
+	err = m.UpdateAppFirstDateSurpassed(testCtx, &types.UpdateFirstDateSurpassed{
+		ApplicationIDs:     []string{app.ID, app.ID},
+		FirstDateSurpassed: surpassed,
+	})
+	c.NoError(err)
+	receiveNotifications(t, m, 1)
+
+	apps, err := m.ReadApplications(testCtx)
+	c.NoError(err)
+	c.Equal(surpassed, apps[0].FirstDateSurpassed)
+}
+
+func boolPointer(value bool) *bool {
+	return &value
+}
@@ -0,0 +1,117 @@
+package memorydriver
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+var (
+	jsonNull = json.RawMessage("null")
+
+	// secretColumns are stored in the audit log as their MD5 so changes to them are visible without storing them
+	secretColumns = []string{"private_key", "secret_key"}
+)
+
+/* ReadAuditLog returns the audit log entries matching the filter, newest first */
+func (m *MemoryDriver) ReadAuditLog(ctx context.Context, filter types.AuditLogFilter) ([]*types.AuditLogEntry, error) {
+	var entries []*types.AuditLogEntry
+
+	err := m.read(ctx, func(s *state) error {
+		for i := len(s.auditLog) - 1; i >= 0; i-- {
+			entry := s.auditLog[i]
+
+			if filter.EntityType != "" && entry.EntityType != filter.EntityType {
+				continue
+			}
+			if filter.EntityID != "" && entry.EntityID != filter.EntityID {
+				continue
+			}
+			if filter.ActorID != "" && entry.ActorID != filter.ActorID {
+				continue
+			}
+
+			copied := *entry
+			copied.Diff = copyMap(entry.Diff)
+			entries = append(entries, &copied)
+
+			if filter.Limit > 0 && len(entries) == filter.Limit {
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+/* audit appends the entry the audit_event trigger records for a row change, nothing is recorded if no column changed */
+func (s *state) audit(tx *transaction, action types.Action, before, after row) {
+	oldRow, newRow := auditColumns(before), auditColumns(after)
+
+	diff := make(map[string]types.AuditChange)
+	for column, value := range newRow {
+		if oldValue, ok := oldRow[column]; !ok || !bytes.Equal(oldValue, value) {
+			diff[column] = types.AuditChange{Before: orNull(oldValue), After: value}
+		}
+	}
+	for column, oldValue := range oldRow {
+		if _, ok := newRow[column]; !ok {
+			diff[column] = types.AuditChange{Before: oldValue, After: jsonNull}
+		}
+	}
+	if len(diff) == 0 {
+		return
+	}
+
+	changedRow := after
+	if action == types.ActionDelete {
+		changedRow = before
+	}
+
+	s.auditLog = append(s.auditLog, &types.AuditLogEntry{
+		ID:         len(s.auditLog) + 1,
+		EntityType: changedRow.table(),
+		EntityID:   changedRow.entityID(),
+		Action:     action,
+		ActorID:    types.ActorFromContext(tx.ctx),
+		Diff:       diff,
+		CreatedAt:  dbTime(tx.startedAt),
+	})
+}
+
+func auditColumns(r row) map[string]json.RawMessage {
+	columns := make(map[string]json.RawMessage)
+	if r == nil {
+		return columns
+	}
+
+	rawRow, _ := json.Marshal(r)
+	_ = json.Unmarshal(rawRow, &columns)
+
+	for _, column := range secretColumns {
+		var secret *string
+		if value, ok := columns[column]; ok && json.Unmarshal(value, &secret) == nil && secret != nil {
+			hash := md5.Sum([]byte(*secret))
+			columns[column], _ = json.Marshal(hex.EncodeToString(hash[:]))
+		}
+	}
+
+	return columns
+}
+
+func orNull(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return jsonNull
+	}
+
+	return value
+}
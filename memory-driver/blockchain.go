@@ -0,0 +1,285 @@
+package memorydriver
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+type (
+	blockchainRow struct {
+		BlockchainID      string    `json:"blockchain_id"`
+		Active            bool      `json:"active"`
+		Altruist          string    `json:"altruist"`
+		Blockchain        string    `json:"blockchain"`
+		BlockchainAliases []string  `json:"blockchain_aliases"`
+		ChainID           string    `json:"chain_id"`
+		ChainIDCheck      string    `json:"chain_id_check"`
+		Description       string    `json:"description"`
+		EnforceResult     string    `json:"enforce_result"`
+		LogLimitBlocks    int       `json:"log_limit_blocks"`
+		Network           string    `json:"network"`
+		Path              string    `json:"path"`
+		RequestTimeout    int       `json:"request_timeout"`
+		Ticker            string    `json:"ticker"`
+		CreatedAt         timestamp `json:"created_at"`
+		UpdatedAt         timestamp `json:"updated_at"`
+	}
+	syncCheckOptionsRow struct {
+		BlockchainID string `json:"blockchain_id"`
+		SyncCheck    string `json:"synccheck"`
+		Allowance    int    `json:"allowance"`
+		Body         string `json:"body"`
+		Path         string `json:"path"`
+		ResultKey    string `json:"result_key"`
+	}
+	redirectRow struct {
+		BlockchainID   string    `json:"blockchain_id"`
+		Alias          string    `json:"alias"`
+		LoadBalancerID string    `json:"loadbalancer"`
+		Domain         string    `json:"domain"`
+		CreatedAt      timestamp `json:"created_at"`
+		UpdatedAt      timestamp `json:"updated_at"`
+	}
+)
+
+/* ReadBlockchains returns all blockchains ordered by ID */
+func (m *MemoryDriver) ReadBlockchains(ctx context.Context) ([]*types.Blockchain, error) {
+	var blockchains []*types.Blockchain
+
+	err := m.read(ctx, func(s *state) error {
+		for _, blockchain := range s.blockchains {
+			blockchains = append(blockchains, s.toBlockchain(blockchain))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(blockchains, func(i, j int) bool { return blockchains[i].ID < blockchains[j].ID })
+
+	return blockchains, nil
+}
+
+func (s *state) toBlockchain(b *blockchainRow) *types.Blockchain {
+	blockchain := &types.Blockchain{
+		ID:                b.BlockchainID,
+		Altruist:          b.Altruist,
+		Blockchain:        b.Blockchain,
+		ChainID:           b.ChainID,
+		ChainIDCheck:      b.ChainIDCheck,
+		Description:       b.Description,
+		EnforceResult:     b.EnforceResult,
+		Network:           b.Network,
+		Path:              b.Path,
+		Ticker:            b.Ticker,
+		BlockchainAliases: copyStrings(b.BlockchainAliases),
+		LogLimitBlocks:    b.LogLimitBlocks,
+		RequestTimeout:    b.RequestTimeout,
+		Active:            b.Active,
+		Redirects:         []types.Redirect{},
+		CreatedAt:         b.CreatedAt.Time,
+		UpdatedAt:         b.UpdatedAt.Time,
+	}
+
+	if options, ok := s.syncCheckOptions[b.BlockchainID]; ok {
+		blockchain.SyncCheck = options.SyncCheck
+		blockchain.SyncCheckOptions = types.SyncCheckOptions{
+			Body:      options.Body,
+			ResultKey: options.ResultKey,
+			Path:      options.Path,
+			Allowance: options.Allowance,
+		}
+	}
+
+	for _, redirect := range s.redirects {
+		if redirect.BlockchainID == b.BlockchainID {
+			blockchain.Redirects = append(blockchain.Redirects, types.Redirect{
+				Alias:          redirect.Alias,
+				LoadBalancerID: redirect.LoadBalancerID,
+				Domain:         redirect.Domain,
+			})
+		}
+	}
+
+	return blockchain
+}
+
+/* WriteBlockchain saves input Blockchain struct */
+func (m *MemoryDriver) WriteBlockchain(ctx context.Context, blockchain *types.Blockchain) (*types.Blockchain, error) {
+	blockchainIsInvalid := blockchain.Validate()
+	if blockchainIsInvalid != nil {
+		return nil, blockchainIsInvalid
+	}
+
+	now := time.Now()
+	blockchain.CreatedAt = now
+	blockchain.UpdatedAt = now
+
+	err := m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		if _, ok := s.blockchains[blockchain.ID]; ok {
+			return uniqueViolation("blockchains_blockchain_id_key")
+		}
+
+		blockchainRow := &blockchainRow{
+			BlockchainID:      blockchain.ID,
+			Active:            blockchain.Active,
+			Altruist:          blockchain.Altruist,
+			Blockchain:        blockchain.Blockchain,
+			BlockchainAliases: copyStrings(blockchain.BlockchainAliases),
+			ChainID:           blockchain.ChainID,
+			ChainIDCheck:      blockchain.ChainIDCheck,
+			Description:       blockchain.Description,
+			EnforceResult:     blockchain.EnforceResult,
+			LogLimitBlocks:    blockchain.LogLimitBlocks,
+			Network:           blockchain.Network,
+			Path:              blockchain.Path,
+			RequestTimeout:    blockchain.RequestTimeout,
+			Ticker:            blockchain.Ticker,
+			CreatedAt:         newTimestamp(now),
+			UpdatedAt:         newTimestamp(now),
+		}
+		s.blockchains[blockchain.ID] = blockchainRow
+		tx.changed(types.ActionInsert, nil, blockchainRow)
+
+		options := blockchain.SyncCheckOptions
+		if blockchain.SyncCheck != "" || options.Body != "" || options.Path != "" || options.ResultKey != "" || options.Allowance != 0 {
+			optionsRow := &syncCheckOptionsRow{
+				BlockchainID: blockchain.ID,
+				SyncCheck:    blockchain.SyncCheck,
+				Allowance:    options.Allowance,
+				Body:         options.Body,
+				Path:         options.Path,
+				ResultKey:    options.ResultKey,
+			}
+			s.syncCheckOptions[blockchain.ID] = optionsRow
+			tx.changed(types.ActionInsert, nil, optionsRow)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blockchain, nil
+}
+
+/*
+	WriteRedirect saves input Redirect struct.
+
+It must be called separately from WriteBlockchain due to how new chains are added to the dB
+*/
+func (m *MemoryDriver) WriteRedirect(ctx context.Context, redirect *types.Redirect) (*types.Redirect, error) {
+	redirectIsInvalid := redirect.Validate()
+	if redirectIsInvalid != nil {
+		return nil, redirectIsInvalid
+	}
+
+	now := time.Now()
+	redirect.CreatedAt = now
+	redirect.UpdatedAt = now
+
+	err := m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		if _, ok := s.blockchains[redirect.BlockchainID]; !ok {
+			return referenceViolation(types.TableRedirects, "fk_blockchain")
+		}
+		for _, current := range s.redirects {
+			if current.BlockchainID == redirect.BlockchainID && current.Domain == redirect.Domain {
+				return uniqueViolation("redirects_blockchain_id_domain_key")
+			}
+		}
+
+		redirectRow := &redirectRow{
+			BlockchainID:   redirect.BlockchainID,
+			Alias:          redirect.Alias,
+			LoadBalancerID: redirect.LoadBalancerID,
+			Domain:         redirect.Domain,
+			CreatedAt:      newTimestamp(now),
+			UpdatedAt:      newTimestamp(now),
+		}
+		s.redirects = append(s.redirects, redirectRow)
+		tx.changed(types.ActionInsert, nil, redirectRow)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return redirect, nil
+}
+
+/* Activate chain toggles chain.active field on or off, it returns types.ErrNotFound if the chain does not exist */
+func (m *MemoryDriver) ActivateChain(ctx context.Context, id string, active bool) error {
+	return m.write(ctx, func(tx *transaction) error {
+		blockchain, ok := tx.state.blockchains[id]
+		if !ok {
+			return types.ErrNotFound
+		}
+
+		updated := *blockchain
+		updated.Active = active
+		updated.UpdatedAt = newTimestamp(time.Now())
+		tx.state.blockchains[id] = &updated
+		tx.changed(types.ActionUpdate, blockchain, &updated)
+
+		return nil
+	})
+}
+
+func (r *blockchainRow) table() types.Table { return types.TableBlockchains }
+func (r *blockchainRow) entityID() string   { return r.BlockchainID }
+func (r *blockchainRow) toOutput() types.SavedOnDB {
+	return &types.Blockchain{
+		ID:                r.BlockchainID,
+		Altruist:          r.Altruist,
+		Blockchain:        r.Blockchain,
+		ChainID:           r.ChainID,
+		ChainIDCheck:      r.ChainIDCheck,
+		Path:              r.Path,
+		Description:       r.Description,
+		EnforceResult:     r.EnforceResult,
+		Network:           r.Network,
+		Ticker:            r.Ticker,
+		BlockchainAliases: copyStrings(r.BlockchainAliases),
+		LogLimitBlocks:    r.LogLimitBlocks,
+		RequestTimeout:    r.RequestTimeout,
+		Active:            r.Active,
+		CreatedAt:         r.CreatedAt.Time,
+		UpdatedAt:         r.UpdatedAt.Time,
+	}
+}
+
+func (r *syncCheckOptionsRow) table() types.Table { return types.TableSyncCheckOptions }
+func (r *syncCheckOptionsRow) entityID() string   { return r.BlockchainID }
+func (r *syncCheckOptionsRow) toOutput() types.SavedOnDB {
+	return &types.SyncCheckOptions{
+		BlockchainID: r.BlockchainID,
+		Body:         r.Body,
+		Path:         r.Path,
+		ResultKey:    r.ResultKey,
+		Allowance:    r.Allowance,
+	}
+}
+
+func (r *redirectRow) table() types.Table { return types.TableRedirects }
+func (r *redirectRow) entityID() string   { return r.BlockchainID }
+func (r *redirectRow) toOutput() types.SavedOnDB {
+	return &types.Redirect{
+		BlockchainID:   r.BlockchainID,
+		Alias:          r.Alias,
+		LoadBalancerID: r.LoadBalancerID,
+		Domain:         r.Domain,
+		CreatedAt:      r.CreatedAt.Time,
+		UpdatedAt:      r.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,855 @@
+package memorydriver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+type (
+	loadBalancerRow struct {
+		LbID              string    `json:"lb_id"`
+		UserID            string    `json:"user_id"`
+		Name              string    `json:"name"`
+		RequestTimeout    int       `json:"request_timeout"`
+		Gigastake         bool      `json:"gigastake"`
+		GigastakeRedirect bool      `json:"gigastake_redirect"`
+		Version           int       `json:"version"`
+		CreatedAt         timestamp `json:"created_at"`
+		UpdatedAt         timestamp `json:"updated_at"`
+	}
+	stickinessOptionsRow struct {
+		LbID       string   `json:"lb_id"`
+		Duration   string   `json:"duration"`
+		StickyMax  int      `json:"sticky_max"`
+		Stickiness bool     `json:"stickiness"`
+		Origins    []string `json:"origins"`
+	}
+	lbLimitRow struct {
+		LbID        string `json:"lb_id"`
+		PayPlan     string `json:"pay_plan"`
+		CustomLimit *int   `json:"custom_limit"`
+	}
+	// An empty string is a NULL column, so users invited only by email never collide on (lb_id, user_id)
+	userAccessRow struct {
+		LbID            string    `json:"lb_id"`
+		UserID          string    `json:"user_id"`
+		RoleName        string    `json:"role_name"`
+		Email           string    `json:"email"`
+		Accepted        bool      `json:"accepted"`
+		InviteToken     string    `json:"invite_token"`
+		InviteExpiresAt timestamp `json:"invite_expires_at"`
+		CreatedAt       timestamp `json:"created_at"`
+		UpdatedAt       timestamp `json:"updated_at"`
+	}
+	lbAppRow struct {
+		LbID  string `json:"lb_id"`
+		AppID string `json:"app_id"`
+	}
+)
+
+/* ReadLoadBalancers returns all LoadBalancers ordered by ID, removed ones included */
+func (m *MemoryDriver) ReadLoadBalancers(ctx context.Context) ([]*types.LoadBalancer, error) {
+	var loadBalancers []*types.LoadBalancer
+
+	err := m.read(ctx, func(s *state) error {
+		for _, lb := range s.loadBalancers {
+			loadBalancers = append(loadBalancers, s.toLoadBalancer(lb))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(loadBalancers, func(i, j int) bool { return loadBalancers[i].ID < loadBalancers[j].ID })
+
+	return loadBalancers, nil
+}
+
+func (s *state) toLoadBalancer(lb *loadBalancerRow) *types.LoadBalancer {
+	var appIDs []string
+	for _, lbApp := range s.lbApps {
+		if lbApp.LbID == lb.LbID {
+			appIDs = append(appIDs, lbApp.AppID)
+		}
+	}
+
+	loadBalancer := &types.LoadBalancer{
+		ID:                lb.LbID,
+		Name:              lb.Name,
+		UserID:            lb.UserID,
+		ApplicationIDs:    strings.Split(strings.Join(appIDs, ","), ","),
+		RequestTimeout:    lb.RequestTimeout,
+		Gigastake:         lb.Gigastake,
+		GigastakeRedirect: lb.GigastakeRedirect,
+		Users:             []types.UserAccess{},
+		Version:           lb.Version,
+		CreatedAt:         lb.CreatedAt.Time,
+		UpdatedAt:         lb.UpdatedAt.Time,
+	}
+
+	if stickiness, ok := s.stickinessOptions[lb.LbID]; ok {
+		loadBalancer.StickyOptions = types.StickyOptions{
+			Duration:      stickiness.Duration,
+			StickyOrigins: copyStrings(stickiness.Origins),
+			StickyMax:     stickiness.StickyMax,
+			Stickiness:    stickiness.Stickiness,
+		}
+	}
+
+	if limit, ok := s.lbLimits[lb.LbID]; ok {
+		loadBalancer.Limit = &types.LBLimit{
+			PayPlan: types.PayPlan{
+				Type:  types.PayPlanType(limit.PayPlan),
+				Limit: s.payPlans[types.PayPlanType(limit.PayPlan)],
+			},
+			CustomLimit: intValue(limit.CustomLimit),
+		}
+	}
+
+	for _, userAccess := range s.userAccess {
+		if userAccess.LbID == lb.LbID {
+			loadBalancer.Users = append(loadBalancer.Users, types.UserAccess{
+				UserID:   userAccess.UserID,
+				RoleName: types.RoleName(userAccess.RoleName),
+				Email:    userAccess.Email,
+				Accepted: userAccess.Accepted,
+			})
+		}
+	}
+
+	return loadBalancer
+}
+
+/* ReadUserRoles returns all User Roles as a map that takes the form map[User ID]map[LB ID][]types.PermissionsEnum */
+func (m *MemoryDriver) ReadUserRoles(ctx context.Context) (map[string]map[string][]types.PermissionsEnum, error) {
+	userRolesMap := make(map[string]map[string][]types.PermissionsEnum)
+
+	err := m.read(ctx, func(s *state) error {
+		for _, userAccess := range s.userAccess {
+			userRoles, ok := userRolesMap[userAccess.UserID]
+			if !ok {
+				userRoles = make(map[string][]types.PermissionsEnum)
+				userRolesMap[userAccess.UserID] = userRoles
+			}
+
+			permissions := s.userRoles[types.RoleName(userAccess.RoleName)]
+			if permissions != nil {
+				permissions = append([]types.PermissionsEnum{}, permissions...)
+			}
+			userRoles[userAccess.LbID] = permissions
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return userRolesMap, nil
+}
+
+/* WriteLoadBalancer saves input LoadBalancer */
+func (m *MemoryDriver) WriteLoadBalancer(ctx context.Context, loadBalancer *types.LoadBalancer) (*types.LoadBalancer, error) {
+	if len(loadBalancer.Users) < 1 {
+		return nil, types.ErrLBMustHaveUser
+	}
+
+	loadBalancerIsInvalid := loadBalancer.Validate()
+	if loadBalancerIsInvalid != nil {
+		return nil, loadBalancerIsInvalid
+	}
+
+	id, err := generateRandomID()
+	if err != nil {
+		return nil, err
+	}
+	loadBalancer.ID = id
+	now := time.Now()
+	loadBalancer.CreatedAt = now
+	loadBalancer.UpdatedAt = now
+
+	loadBalancer.Users[0].RoleName = types.RoleOwner // The first User will be the initial creater (owner) of the LoadBalancer
+
+	err = m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		lbRow := &loadBalancerRow{
+			LbID:              id,
+			UserID:            loadBalancer.UserID,
+			Name:              loadBalancer.Name,
+			RequestTimeout:    loadBalancer.RequestTimeout,
+			Gigastake:         loadBalancer.Gigastake,
+			GigastakeRedirect: loadBalancer.GigastakeRedirect,
+			Version:           1,
+			CreatedAt:         newTimestamp(now),
+			UpdatedAt:         newTimestamp(now),
+		}
+		s.loadBalancers[id] = lbRow
+		tx.changed(types.ActionInsert, nil, lbRow)
+
+		stickiness := loadBalancer.StickyOptions
+		if stickiness.Duration != "" || len(stickiness.StickyOrigins) > 0 || stickiness.StickyMax != 0 {
+			stickinessRow := &stickinessOptionsRow{
+				LbID:       id,
+				Duration:   stickiness.Duration,
+				StickyMax:  stickiness.StickyMax,
+				Stickiness: stickiness.Stickiness,
+				Origins:    copyStrings(stickiness.StickyOrigins),
+			}
+			s.stickinessOptions[id] = stickinessRow
+			tx.changed(types.ActionInsert, nil, stickinessRow)
+		}
+
+		if loadBalancer.Limit != nil {
+			err := tx.upsertLBLimit(id, loadBalancer.Limit)
+			if err != nil {
+				return err
+			}
+		}
+
+		owner := loadBalancer.Users[0]
+		err := tx.insertUserAccess(&userAccessRow{
+			LbID:      id,
+			UserID:    owner.UserID,
+			RoleName:  string(owner.RoleName),
+			Email:     owner.Email,
+			Accepted:  true, // New LB owners always start with accepted = true
+			CreatedAt: newTimestamp(now),
+			UpdatedAt: newTimestamp(now),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, appID := range loadBalancer.ApplicationIDs {
+			if _, ok := s.applications[appID]; !ok {
+				return referenceViolation(types.TableLbApps, "fk_app")
+			}
+			for _, lbApp := range s.lbApps {
+				if lbApp.LbID == id && lbApp.AppID == appID {
+					return uniqueViolation("lb_apps_lb_id_app_id_key")
+				}
+			}
+
+			lbApp := &lbAppRow{LbID: id, AppID: appID}
+			s.lbApps = append(s.lbApps, lbApp)
+			tx.changed(types.ActionInsert, nil, lbApp)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loadBalancer, nil
+}
+
+/* WriteLoadBalancerUser saves a UserAccess row for a LoadBalancer, the user starts with accepted = false */
+func (m *MemoryDriver) WriteLoadBalancerUser(ctx context.Context, lbID string, userAccess types.UserAccess) error {
+	if lbID == "" {
+		return types.ErrMissingID
+	}
+	if userAccess.RoleName == types.RoleOwner {
+		return types.ErrCannotSetToOwner
+	}
+
+	missingField := ""
+	switch {
+	case userAccess.UserID == "":
+		missingField = "UserID"
+	case userAccess.RoleName == "":
+		missingField = "RoleName"
+	case userAccess.Email == "":
+		missingField = "Email"
+	}
+	if missingField != "" {
+		return fmt.Errorf("%w: %s", types.ErrUserInputIsMissingField, missingField)
+	}
+
+	now := time.Now()
+
+	return m.write(ctx, func(tx *transaction) error {
+		return tx.insertUserAccess(&userAccessRow{
+			LbID:      lbID,
+			UserID:    userAccess.UserID,
+			RoleName:  string(userAccess.RoleName),
+			Email:     userAccess.Email,
+			CreatedAt: newTimestamp(now),
+			UpdatedAt: newTimestamp(now),
+		})
+	})
+}
+
+/*
+	WriteLoadBalancerInvitation saves a pending UserAccess row with an invitation token.
+
+The UserID may be left empty for users who have not yet signed up; it is then set when the invitation is accepted
+*/
+func (m *MemoryDriver) WriteLoadBalancerInvitation(ctx context.Context, lbID string, userAccess types.UserAccess) (*types.Invitation, error) {
+	if lbID == "" {
+		return nil, types.ErrMissingID
+	}
+	if userAccess.RoleName == types.RoleOwner {
+		return nil, types.ErrCannotSetToOwner
+	}
+	if userAccess.RoleName == "" {
+		return nil, fmt.Errorf("%w: %s", types.ErrUserInputIsMissingField, "RoleName")
+	}
+	if userAccess.Email == "" {
+		return nil, fmt.Errorf("%w: %s", types.ErrUserInputIsMissingField, "Email")
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &types.Invitation{
+		LbID:      lbID,
+		UserID:    userAccess.UserID,
+		RoleName:  userAccess.RoleName,
+		Email:     userAccess.Email,
+		Token:     token,
+		ExpiresAt: now.Add(invitationTTL),
+		CreatedAt: now,
+	}
+
+	err = m.write(ctx, func(tx *transaction) error {
+		return tx.insertUserAccess(&userAccessRow{
+			LbID:            lbID,
+			UserID:          invitation.UserID,
+			RoleName:        string(invitation.RoleName),
+			Email:           invitation.Email,
+			InviteToken:     token,
+			InviteExpiresAt: newTimestamp(invitation.ExpiresAt),
+			CreatedAt:       newTimestamp(now),
+			UpdatedAt:       newTimestamp(now),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+/* ReadPendingInvitations returns all unexpired invitations that have not been accepted for an email, oldest first */
+func (m *MemoryDriver) ReadPendingInvitations(ctx context.Context, email string) ([]*types.Invitation, error) {
+	var invitations []*types.Invitation
+
+	now := time.Now()
+
+	err := m.read(ctx, func(s *state) error {
+		for _, userAccess := range s.userAccess {
+			if email == "" || userAccess.Email != email || userAccess.Accepted ||
+				userAccess.InviteToken == "" || !userAccess.InviteExpiresAt.After(now) {
+				continue
+			}
+
+			invitations = append(invitations, userAccess.toInvitation())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(invitations, func(i, j int) bool { return invitations[i].CreatedAt.Before(invitations[j].CreatedAt) })
+
+	return invitations, nil
+}
+
+/* AcceptInvitation marks the invitation as accepted and sets the UserID for invitations sent only to an email */
+func (m *MemoryDriver) AcceptInvitation(ctx context.Context, token, userID string) error {
+	if token == "" || userID == "" {
+		return types.ErrMissingID
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		for i, userAccess := range s.userAccess {
+			if userAccess.InviteToken != token {
+				continue
+			}
+
+			if userAccess.toInvitation().IsExpired(time.Now()) {
+				return types.ErrInvitationExpired
+			}
+			if userAccess.UserID != "" && userAccess.UserID != userID {
+				return types.ErrInvitationUserMismatch
+			}
+			if s.hasUserAccess(userAccess.LbID, userID, i) {
+				return uniqueViolation("user_access_lb_id_user_id_key")
+			}
+
+			accepted := *userAccess
+			accepted.UserID = userID
+			accepted.Accepted = true
+			accepted.InviteToken = ""
+			accepted.InviteExpiresAt = timestamp{}
+			accepted.UpdatedAt = newTimestamp(time.Now())
+			s.userAccess[i] = &accepted
+			tx.changed(types.ActionUpdate, userAccess, &accepted)
+
+			return nil
+		}
+
+		return types.ErrInvitationNotFound
+	})
+}
+
+/* DeclineInvitation deletes a pending invitation */
+func (m *MemoryDriver) DeclineInvitation(ctx context.Context, token string) error {
+	if token == "" {
+		return types.ErrMissingID
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		deleted := tx.deleteUserAccess(func(userAccess *userAccessRow) bool {
+			return userAccess.InviteToken == token && !userAccess.Accepted
+		})
+		if deleted == 0 {
+			return types.ErrInvitationNotFound
+		}
+
+		return nil
+	})
+}
+
+/* RemoveExpiredInvitations deletes all pending invitations past their expiry and returns how many were removed */
+func (m *MemoryDriver) RemoveExpiredInvitations(ctx context.Context) (int64, error) {
+	var removed int64
+
+	now := time.Now()
+
+	err := m.write(ctx, func(tx *transaction) error {
+		removed = tx.deleteUserAccess(func(userAccess *userAccessRow) bool {
+			return !userAccess.Accepted && !userAccess.InviteExpiresAt.IsZero() && userAccess.InviteExpiresAt.Before(now)
+		})
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+/* UpdateLoadBalancer updates LoadBalancer and related table rows, it returns types.ErrNotFound if the LoadBalancer does not exist */
+func (m *MemoryDriver) UpdateLoadBalancer(ctx context.Context, id string, update *types.UpdateLoadBalancer) error {
+	if id == "" {
+		return types.ErrMissingID
+	}
+
+	invalidUpdate := update.Validate()
+	if invalidUpdate != nil {
+		return invalidUpdate
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		lb, err := s.versionedLoadBalancer(id, update.ExpectedVersion)
+		if err != nil {
+			return err
+		}
+
+		updatedLB := *lb
+		if update.Name != "" {
+			updatedLB.Name = update.Name
+		}
+		updatedLB.UpdatedAt = newTimestamp(time.Now())
+		updatedLB.Version++
+		s.loadBalancers[id] = &updatedLB
+		tx.changed(types.ActionUpdate, lb, &updatedLB)
+
+		if stickiness := update.StickyOptions; stickiness != nil {
+			if stickiness.Duration != "" || stickiness.StickyMax != 0 || stickiness.Stickiness != nil || len(stickiness.StickyOrigins) != 0 {
+				current, ok := s.stickinessOptions[id]
+				updatedStickiness := &stickinessOptionsRow{LbID: id}
+				if ok {
+					copied := *current
+					updatedStickiness = &copied
+				}
+				if stickiness.Duration != "" {
+					updatedStickiness.Duration = stickiness.Duration
+				}
+				if stickiness.StickyMax != 0 {
+					updatedStickiness.StickyMax = stickiness.StickyMax
+				}
+				setBool(&updatedStickiness.Stickiness, stickiness.Stickiness)
+				if stickiness.StickyOrigins != nil {
+					updatedStickiness.Origins = copyStrings(stickiness.StickyOrigins)
+				}
+
+				s.stickinessOptions[id] = updatedStickiness
+				if ok {
+					tx.changed(types.ActionUpdate, current, updatedStickiness)
+				} else {
+					tx.changed(types.ActionInsert, nil, updatedStickiness)
+				}
+			}
+		}
+
+		if update.Limit != nil {
+			err = tx.upsertLBLimit(id, update.Limit)
+			if err != nil {
+				return err
+			}
+		}
+		if update.RemoveLimit {
+			if limit, ok := s.lbLimits[id]; ok {
+				delete(s.lbLimits, id)
+				tx.changed(types.ActionDelete, limit, nil)
+			}
+		}
+
+		return nil
+	})
+}
+
+/* UpdateUserAccessRole updates the RoleName for a UserAccess row */
+func (m *MemoryDriver) UpdateUserAccessRole(ctx context.Context, userID, lbID string, roleName types.RoleName) error {
+	return m.UpdateLoadBalancerUser(ctx, lbID, &types.UpdateUserAccess{UserID: userID, RoleName: roleName})
+}
+
+/*
+	UpdateLoadBalancerUser updates the RoleName for a UserAccess row.
+
+If ExpectedVersion is set the update only succeeds if it matches the version of the load balancer
+*/
+func (m *MemoryDriver) UpdateLoadBalancerUser(ctx context.Context, lbID string, update *types.UpdateUserAccess) error {
+	if update.UserID == "" || lbID == "" {
+		return types.ErrMissingID
+	}
+	if update.RoleName == types.RoleOwner {
+		return types.ErrCannotSetToOwner
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		lb, err := s.versionedLoadBalancer(lbID, update.ExpectedVersion)
+		if err != nil {
+			return err
+		}
+
+		updatedLB := *lb
+		updatedLB.Version++
+		s.loadBalancers[lbID] = &updatedLB
+		tx.changed(types.ActionUpdate, lb, &updatedLB)
+
+		updatedRows, err := tx.updateUserAccessRole(lbID, update.UserID, update.RoleName, time.Now())
+		if err != nil {
+			return err
+		}
+		if updatedRows == 0 {
+			return types.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+/*
+	TransferLoadBalancerOwnership moves the owner role of a LoadBalancer from one user to another.
+
+The previous owner is demoted to admin, the new owner (who must have accepted their invite) is promoted
+and the LoadBalancer's UserID is updated, all in a single transaction
+*/
+func (m *MemoryDriver) TransferLoadBalancerOwnership(ctx context.Context, lbID, fromUserID, toUserID string) error {
+	if lbID == "" || fromUserID == "" || toUserID == "" {
+		return types.ErrMissingID
+	}
+	if fromUserID == toUserID {
+		return types.ErrSameOwner
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		currentOwner := s.findUserAccess(lbID, fromUserID)
+		if currentOwner == nil || currentOwner.RoleName != string(types.RoleOwner) {
+			return types.ErrUserIsNotOwner
+		}
+
+		newOwner := s.findUserAccess(lbID, toUserID)
+		if newOwner == nil || !newOwner.Accepted {
+			return types.ErrNewOwnerNotAccepted
+		}
+
+		now := time.Now()
+
+		_, err := tx.updateUserAccessRole(lbID, fromUserID, types.RoleAdmin, now)
+		if err != nil {
+			return err
+		}
+		_, err = tx.updateUserAccessRole(lbID, toUserID, types.RoleOwner, now)
+		if err != nil {
+			return err
+		}
+
+		if lb, ok := s.loadBalancers[lbID]; ok {
+			updatedLB := *lb
+			updatedLB.UserID = toUserID
+			updatedLB.UpdatedAt = newTimestamp(now)
+			updatedLB.Version++
+			s.loadBalancers[lbID] = &updatedLB
+			tx.changed(types.ActionUpdate, lb, &updatedLB)
+		}
+
+		return nil
+	})
+}
+
+/* RemoveLoadBalancer sets the user ID to an empty string (will not appear in Portal API or UI), it returns types.ErrNotFound if the LoadBalancer does not exist */
+func (m *MemoryDriver) RemoveLoadBalancer(ctx context.Context, id string) error {
+	if id == "" {
+		return types.ErrMissingID
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		lb, ok := tx.state.loadBalancers[id]
+		if !ok {
+			return types.ErrNotFound
+		}
+
+		removedLB := *lb
+		removedLB.UserID = ""
+		removedLB.UpdatedAt = newTimestamp(time.Now())
+		tx.state.loadBalancers[id] = &removedLB
+		tx.changed(types.ActionUpdate, lb, &removedLB)
+
+		return nil
+	})
+}
+
+/* RemoveUserAccess deletes a UserAccess row, it returns types.ErrNotFound if the user has no access to the LoadBalancer */
+func (m *MemoryDriver) RemoveUserAccess(ctx context.Context, userID, lbID string) error {
+	if userID == "" || lbID == "" {
+		return types.ErrMissingID
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		deleted := tx.deleteUserAccess(func(userAccess *userAccessRow) bool {
+			return userAccess.UserID == userID && userAccess.LbID == lbID
+		})
+		if deleted == 0 {
+			return types.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+/* versionedLoadBalancer tells apart the two reasons a versioned LoadBalancer update can match no rows */
+func (s *state) versionedLoadBalancer(id string, expectedVersion int) (*loadBalancerRow, error) {
+	lb, ok := s.loadBalancers[id]
+	if !ok {
+		return nil, types.ErrNotFound
+	}
+	if expectedVersion != 0 && expectedVersion != lb.Version {
+		return nil, types.ErrConflict
+	}
+
+	return lb, nil
+}
+
+func (s *state) findUserAccess(lbID, userID string) *userAccessRow {
+	for _, userAccess := range s.userAccess {
+		if userAccess.LbID == lbID && userAccess.UserID == userID {
+			return userAccess
+		}
+	}
+
+	return nil
+}
+
+/* hasUserAccess returns whether a user already has a UserAccess row for a LoadBalancer, other than the one at index skip */
+func (s *state) hasUserAccess(lbID, userID string, skip int) bool {
+	for i, userAccess := range s.userAccess {
+		if i != skip && userAccess.LbID == lbID && userAccess.UserID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *transaction) insertUserAccess(userAccess *userAccessRow) error {
+	if _, ok := t.state.loadBalancers[userAccess.LbID]; !ok {
+		return referenceViolation(types.TableUserAccess, "fk_lb")
+	}
+	if err := t.state.checkRole(userAccess.RoleName); err != nil {
+		return err
+	}
+	if userAccess.UserID != "" && t.state.hasUserAccess(userAccess.LbID, userAccess.UserID, -1) {
+		return uniqueViolation("user_access_lb_id_user_id_key")
+	}
+
+	t.state.userAccess = append(t.state.userAccess, userAccess)
+	t.changed(types.ActionInsert, nil, userAccess)
+
+	return nil
+}
+
+/* updateUserAccessRole sets the role of a user, an empty roleName keeps the current one */
+func (t *transaction) updateUserAccessRole(lbID, userID string, roleName types.RoleName, updatedAt time.Time) (int, error) {
+	if err := t.state.checkRole(string(roleName)); err != nil {
+		return 0, err
+	}
+
+	var updatedRows int
+	for i, userAccess := range t.state.userAccess {
+		if userAccess.LbID != lbID || userAccess.UserID != userID {
+			continue
+		}
+
+		updated := *userAccess
+		if roleName != "" {
+			updated.RoleName = string(roleName)
+		}
+		updated.UpdatedAt = newTimestamp(updatedAt)
+		t.state.userAccess[i] = &updated
+		t.changed(types.ActionUpdate, userAccess, &updated)
+		updatedRows++
+	}
+
+	return updatedRows, nil
+}
+
+/* deleteUserAccess deletes the UserAccess rows matching fn and returns how many were deleted */
+func (t *transaction) deleteUserAccess(fn func(userAccess *userAccessRow) bool) int64 {
+	var kept []*userAccessRow
+	var deleted int64
+	for _, userAccess := range t.state.userAccess {
+		if !fn(userAccess) {
+			kept = append(kept, userAccess)
+			continue
+		}
+
+		t.changed(types.ActionDelete, userAccess, nil)
+		deleted++
+	}
+	t.state.userAccess = kept
+
+	return deleted
+}
+
+func (s *state) checkRole(roleName string) error {
+	if roleName == "" {
+		return nil
+	}
+	if _, ok := s.userRoles[types.RoleName(roleName)]; !ok {
+		return referenceViolation(types.TableUserAccess, "fk_role")
+	}
+
+	return nil
+}
+
+func (t *transaction) upsertLBLimit(lbID string, limit *types.LBLimit) error {
+	if _, ok := t.state.payPlans[limit.PayPlan.Type]; !ok {
+		return referenceViolation(types.TableLbLimits, "fk_pay_plan")
+	}
+
+	updatedLimit := &lbLimitRow{
+		LbID:        lbID,
+		PayPlan:     string(limit.PayPlan.Type),
+		CustomLimit: nullableInt(limit.CustomLimit, false),
+	}
+
+	current, ok := t.state.lbLimits[lbID]
+	t.state.lbLimits[lbID] = updatedLimit
+	if !ok {
+		t.changed(types.ActionInsert, nil, updatedLimit)
+		return nil
+	}
+
+	t.changed(types.ActionUpdate, current, updatedLimit)
+	return nil
+}
+
+func (r *userAccessRow) toInvitation() *types.Invitation {
+	return &types.Invitation{
+		LbID:      r.LbID,
+		UserID:    r.UserID,
+		RoleName:  types.RoleName(r.RoleName),
+		Email:     r.Email,
+		Token:     r.InviteToken,
+		ExpiresAt: r.InviteExpiresAt.Time,
+		CreatedAt: r.CreatedAt.Time,
+	}
+}
+
+func (r *loadBalancerRow) table() types.Table { return types.TableLoadBalancers }
+func (r *loadBalancerRow) entityID() string   { return r.LbID }
+func (r *loadBalancerRow) toOutput() types.SavedOnDB {
+	return &types.LoadBalancer{
+		ID:                r.LbID,
+		Name:              r.Name,
+		UserID:            r.UserID,
+		RequestTimeout:    r.RequestTimeout,
+		Gigastake:         r.Gigastake,
+		GigastakeRedirect: r.GigastakeRedirect,
+		Version:           r.Version,
+		CreatedAt:         r.CreatedAt.Time,
+		UpdatedAt:         r.UpdatedAt.Time,
+	}
+}
+
+func (r *stickinessOptionsRow) table() types.Table { return types.TableStickinessOptions }
+func (r *stickinessOptionsRow) entityID() string   { return r.LbID }
+func (r *stickinessOptionsRow) toOutput() types.SavedOnDB {
+	return &types.StickyOptions{
+		ID:            r.LbID,
+		Duration:      r.Duration,
+		StickyOrigins: copyStrings(r.Origins),
+		StickyMax:     r.StickyMax,
+		Stickiness:    r.Stickiness,
+	}
+}
+
+func (r *lbLimitRow) table() types.Table { return types.TableLbLimits }
+func (r *lbLimitRow) entityID() string   { return r.LbID }
+func (r *lbLimitRow) toOutput() types.SavedOnDB {
+	return &types.LBLimit{
+		ID: r.LbID,
+		PayPlan: types.PayPlan{
+			Type: types.PayPlanType(r.PayPlan),
+		},
+		CustomLimit: intValue(r.CustomLimit),
+	}
+}
+
+func (r *userAccessRow) table() types.Table { return types.TableUserAccess }
+func (r *userAccessRow) entityID() string   { return r.LbID }
+func (r *userAccessRow) toOutput() types.SavedOnDB {
+	return &types.UserAccess{
+		ID:       r.LbID,
+		UserID:   r.UserID,
+		RoleName: types.RoleName(r.RoleName),
+		Email:    r.Email,
+		Accepted: r.Accepted,
+	}
+}
+
+func (r *lbAppRow) table() types.Table { return types.TableLbApps }
+func (r *lbAppRow) entityID() string   { return r.LbID }
+func (r *lbAppRow) toOutput() types.SavedOnDB {
+	return &types.LbApp{
+		LbID:  r.LbID,
+		AppID: r.AppID,
+	}
+}
@@ -0,0 +1,180 @@
+package memorydriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* writeTestLoadBalancer writes a LoadBalancer owned by test_user_memory with one Application and drains their notifications */
+func writeTestLoadBalancer(t *testing.T, m *MemoryDriver) *types.LoadBalancer {
+	t.Helper()
+	c := require.New(t)
+
+	app, err := m.WriteApplication(testCtx, testApplication())
+	c.NoError(err)
+
+	lb, err := m.WriteLoadBalancer(testCtx, &types.LoadBalancer{
+		Name:           "vipr_lb_memory",
+		UserID:         "test_user_memory",
+		ApplicationIDs: []string{app.ID},
+		StickyOptions: types.StickyOptions{
+			Duration:      "60",
+			StickyOrigins: []string{"chrome-extension://"},
+			StickyMax:     300,
+			Stickiness:    true,
+		},
+		Users: []types.UserAccess{{UserID: "test_user_memory", Email: "owner@test.com"}},
+	})
+	c.NoError(err)
+	receiveNotifications(t, m, 9)
+
+	return lb
+}
+
+func Test_WriteLoadBalancer(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	app, err := m.WriteApplication(testCtx, testApplication())
+	c.NoError(err)
+	receiveNotifications(t, m, 5)
+
+	lb, err := m.WriteLoadBalancer(testCtx, &types.LoadBalancer{
+		Name:           "vipr_lb_memory",
+		UserID:         "test_user_memory",
+		ApplicationIDs: []string{app.ID},
+		StickyOptions:  types.StickyOptions{Duration: "60", StickyMax: 300},
+		Users:          []types.UserAccess{{UserID: "test_user_memory", Email: "owner@test.com"}},
+	})
+	c.NoError(err)
+	c.Len(lb.ID, idLength)
+	c.Equal(types.RoleOwner, lb.Users[0].RoleName)
+
+	notifications := receiveNotifications(t, m, 4)
+	c.Equal([]types.Table{
+		types.TableLoadBalancers, types.TableStickinessOptions, types.TableUserAccess, types.TableLbApps,
+	}, notificationTables(notifications))
+	c.Equal(&types.UserAccess{
+		ID:       lb.ID,
+		UserID:   "test_user_memory",
+		RoleName: types.RoleOwner,
+		Email:    "owner@test.com",
+		Accepted: true,
+	}, notifications[2].Data)
+
+	loadBalancers, err := m.ReadLoadBalancers(testCtx)
+	c.NoError(err)
+	c.Len(loadBalancers, 1)
+	c.Equal([]string{app.ID}, loadBalancers[0].ApplicationIDs)
+	c.Equal(1, loadBalancers[0].Version)
+
+	_, err = m.WriteLoadBalancer(testCtx, &types.LoadBalancer{Name: "vipr_lb_memory"})
+	c.Equal(types.ErrLBMustHaveUser, err)
+}
+
+func Test_LoadBalancerInvitation(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	lb := writeTestLoadBalancer(t, m)
+
+	invitation, err := m.WriteLoadBalancerInvitation(testCtx, lb.ID, types.UserAccess{
+		RoleName: types.RoleMember,
+		Email:    "member@test.com",
+	})
+	c.NoError(err)
+	c.Len(invitation.Token, tokenLength)
+	receiveNotifications(t, m, 1)
+
+	invitations, err := m.ReadPendingInvitations(testCtx, "member@test.com")
+	c.NoError(err)
+	c.Len(invitations, 1)
+	c.Equal(invitation.Token, invitations[0].Token)
+	c.Equal(types.RoleMember, invitations[0].RoleName)
+	c.WithinDuration(invitation.ExpiresAt, invitations[0].ExpiresAt, time.Millisecond)
+
+	err = m.AcceptInvitation(testCtx, "not_a_token", "test_user_member")
+	c.Equal(types.ErrInvitationNotFound, err)
+
+	err = m.AcceptInvitation(testCtx, invitation.Token, "test_user_member")
+	c.NoError(err)
+	notifications := receiveNotifications(t, m, 1)
+	c.Equal(types.ActionUpdate, notifications[0].Action)
+	c.True(notifications[0].Data.(*types.UserAccess).Accepted)
+
+	invitations, err = m.ReadPendingInvitations(testCtx, "member@test.com")
+	c.NoError(err)
+	c.Empty(invitations)
+
+	userRoles, err := m.ReadUserRoles(testCtx)
+	c.NoError(err)
+	c.Equal([]types.PermissionsEnum{types.ReadEndpoint}, userRoles["test_user_member"][lb.ID])
+}
+
+func Test_TransferLoadBalancerOwnership(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	lb := writeTestLoadBalancer(t, m)
+
+	invitation, err := m.WriteLoadBalancerInvitation(testCtx, lb.ID, types.UserAccess{
+		UserID:   "test_user_admin",
+		RoleName: types.RoleAdmin,
+		Email:    "admin@test.com",
+	})
+	c.NoError(err)
+	receiveNotifications(t, m, 1)
+
+	err = m.TransferLoadBalancerOwnership(testCtx, lb.ID, "test_user_memory", "test_user_admin")
+	c.Equal(types.ErrNewOwnerNotAccepted, err)
+
+	err = m.AcceptInvitation(testCtx, invitation.Token, "test_user_admin")
+	c.NoError(err)
+	receiveNotifications(t, m, 1)
+
+	err = m.TransferLoadBalancerOwnership(testCtx, lb.ID, "test_user_memory", "test_user_admin")
+	c.NoError(err)
+
+	// The previous owner is demoted before the new one is promoted, then the LoadBalancer is updated
+	notifications := receiveNotifications(t, m, 3)
+	c.Equal([]types.Table{types.TableUserAccess, types.TableUserAccess, types.TableLoadBalancers}, notificationTables(notifications))
+	c.Equal(types.RoleAdmin, notifications[0].Data.(*types.UserAccess).RoleName)
+	c.Equal(types.RoleOwner, notifications[1].Data.(*types.UserAccess).RoleName)
+	c.Equal("test_user_admin", notifications[2].Data.(*types.LoadBalancer).UserID)
+
+	err = m.TransferLoadBalancerOwnership(testCtx, lb.ID, "test_user_memory", "test_user_admin")
+	c.Equal(types.ErrUserIsNotOwner, err)
+}
+
+func Test_RemoveLoadBalancer(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	lb := writeTestLoadBalancer(t, m)
+
+	err := m.RemoveLoadBalancer(testCtx, lb.ID)
+	c.NoError(err)
+	notifications := receiveNotifications(t, m, 1)
+	c.Equal(types.ActionUpdate, notifications[0].Action)
+	c.Empty(notifications[0].Data.(*types.LoadBalancer).UserID)
+
+	// The LoadBalancer is kept without its user
+	loadBalancers, err := m.ReadLoadBalancers(testCtx)
+	c.NoError(err)
+	c.Len(loadBalancers, 1)
+	c.Empty(loadBalancers[0].UserID)
+
+	err = m.RemoveLoadBalancer(testCtx, "not_a_lb")
+	c.ErrorIs(err, types.ErrNotFound)
+
+	// Deleting a UserAccess row sends no notification
+	err = m.RemoveUserAccess(testCtx, "test_user_memory", lb.ID)
+	c.NoError(err)
+	receiveNotifications(t, m, 0)
+
+	err = m.RemoveUserAccess(testCtx, "test_user_memory", lb.ID)
+	c.ErrorIs(err, types.ErrNotFound)
+}
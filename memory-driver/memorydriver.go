@@ -0,0 +1,217 @@
+package memorydriver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const (
+	idLength        = 24
+	tokenLength     = 64
+	secretKeyLength = 32
+	invitationTTL   = 7 * 24 * time.Hour
+	// defaultSecretKeyName names the key generated with an Application, or a SecretKey set before named keys were used
+	defaultSecretKeyName = "default"
+)
+
+var (
+	ErrReadOnlyTx = errors.New("error: cannot write in a read only transaction")
+
+	// DefaultPayPlans are the pay plans a new MemoryDriver starts with, the same the test database is seeded with
+	DefaultPayPlans = []types.PayPlan{
+		{Type: types.Enterprise, Limit: 0},
+		{Type: types.FreetierV0, Limit: 250_000},
+		{Type: types.PayAsYouGoV0, Limit: 0},
+		{Type: types.TestPlan10K, Limit: 10_000},
+		{Type: types.TestPlan90k, Limit: 90_000},
+		{Type: types.TestPlanV0, Limit: 100},
+	}
+
+	// DefaultUserRoles are the user roles and their permissions a new MemoryDriver starts with
+	DefaultUserRoles = map[types.RoleName][]types.PermissionsEnum{
+		types.RoleOwner:  {types.ReadEndpoint, types.WriteEndpoint},
+		types.RoleAdmin:  {types.ReadEndpoint, types.WriteEndpoint},
+		types.RoleMember: {types.ReadEndpoint},
+	}
+)
+
+/*
+The MemoryDriver struct satisfies the Driver interface keeping every table in memory.
+It runs the same validation as the PostgresDriver, returns the same errors and sends the notifications
+the database triggers would, so it can stand in for it in tests and local development.
+Writes are serialized, a transaction started by InTx holds every other write until it ends
+while reads keep seeing the last committed state.
+*/
+type MemoryDriver struct {
+	db *database
+	tx *transaction
+}
+
+/*
+database holds the committed state shared by a MemoryDriver and the drivers InTx passes to its function.
+writeMu is held for a whole write or transaction, mu only to read or swap the state, which is never changed once committed.
+*/
+type database struct {
+	writeMu  sync.Mutex
+	mu       sync.RWMutex
+	state    *state
+	notifier *notifier
+}
+
+/* NewMemoryDriver returns an empty MemoryDriver holding only the DefaultPayPlans and DefaultUserRoles */
+func NewMemoryDriver() *MemoryDriver {
+	return NewMemoryDriverWithPayPlans(DefaultPayPlans)
+}
+
+/* NewMemoryDriverWithPayPlans returns an empty MemoryDriver holding the given pay plans and the DefaultUserRoles */
+func NewMemoryDriverWithPayPlans(payPlans []types.PayPlan) *MemoryDriver {
+	state := newState()
+	for _, payPlan := range payPlans {
+		state.payPlans[payPlan.Type] = payPlan.Limit
+	}
+	for roleName, permissions := range DefaultUserRoles {
+		state.userRoles[roleName] = permissions
+	}
+
+	return &MemoryDriver{
+		db: &database{
+			state:    state,
+			notifier: newNotifier(),
+		},
+	}
+}
+
+/* NotificationChannel returns receiver Notification channel  */
+func (m *MemoryDriver) NotificationChannel() <-chan *types.Notification {
+	return m.db.notifier.out
+}
+
+/* CloseListener closes the Notification channel once the notifications already sent on it are received */
+func (m *MemoryDriver) CloseListener() {
+	m.db.notifier.close()
+}
+
+/* read runs fn on the committed state, or on the transaction's state inside InTx so it sees its own writes */
+func (m *MemoryDriver) read(ctx context.Context, fn func(s *state) error) error {
+	err := checkContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if m.tx != nil {
+		return fn(m.tx.state)
+	}
+
+	return fn(m.db.committed())
+}
+
+/*
+write runs fn on a copy of the state, which replaces it and sends its notifications only if fn succeeds.
+Inside InTx the copy is made of the transaction's state, like a savepoint, and kept until the transaction commits.
+*/
+func (m *MemoryDriver) write(ctx context.Context, fn func(tx *transaction) error) error {
+	err := checkContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if m.tx != nil {
+		if m.tx.readOnly {
+			return ErrReadOnlyTx
+		}
+
+		savepoint := m.tx.savepoint(ctx)
+		err = fn(savepoint)
+		if err != nil {
+			return err
+		}
+
+		m.tx.release(savepoint)
+		return nil
+	}
+
+	m.db.writeMu.Lock()
+	defer m.db.writeMu.Unlock()
+
+	tx := newTransaction(ctx, m.db.committed(), false)
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+
+	m.db.commit(tx)
+
+	return nil
+}
+
+/* committed returns the last committed state, it must not be changed */
+func (d *database) committed() *state {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.state
+}
+
+/* commit replaces the committed state with the transaction's and sends its notifications, writeMu must be held */
+func (d *database) commit(tx *transaction) {
+	d.mu.Lock()
+	d.state = tx.state
+	d.mu.Unlock()
+
+	d.notifier.send(tx.notifications)
+}
+
+/* checkContext returns the error the PostgresDriver would once ctx is done */
+func checkContext(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &types.TimeoutError{Kind: types.TimeoutContext, Err: err}
+	}
+
+	return err
+}
+
+func generateRandomID() (string, error) {
+	return generateRandomHex(idLength)
+}
+
+func generateRandomToken() (string, error) {
+	return generateRandomHex(tokenLength)
+}
+
+func generateRandomSecretKey() (string, error) {
+	return generateRandomHex(secretKeyLength)
+}
+
+func generateRandomHex(length int) (string, error) {
+	bytes := make([]byte, length/2)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
+
+/* dbTime drops what a Postgres TIMESTAMP column does not keep, times are stored in UTC with microsecond precision */
+func dbTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+/* copyStrings copies a stored array so callers cannot change it, keeping nil apart from empty like a NULL array */
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	return append([]string{}, values...)
+}
@@ -0,0 +1,113 @@
+package memorydriver
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const psqlDateLayout = "2006-01-02T15:04:05.999999"
+
+/* row is a stored table row, its JSON tags are the column names so it marshals like row_to_json does */
+type row interface {
+	table() types.Table
+	// entityID is the value of the column the audit log records the row under
+	entityID() string
+	// toOutput returns the Data of the notification sent for the row, as the PostgresDriver's listener parses it
+	toOutput() types.SavedOnDB
+}
+
+/* timestamp is a nullable TIMESTAMP column, the zero time is NULL */
+type timestamp struct {
+	time.Time
+}
+
+func newTimestamp(t time.Time) timestamp {
+	return timestamp{Time: dbTime(t)}
+}
+
+func (t timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.Format(psqlDateLayout))
+}
+
+/*
+notifier sends committed notifications on the Notification channel in order without blocking writers,
+the same way notifications queue up in Postgres until the listener receives them
+*/
+type notifier struct {
+	mu     sync.Mutex
+	queue  []*types.Notification
+	closed bool
+	wake   chan struct{}
+	out    chan *types.Notification
+}
+
+func newNotifier() *notifier {
+	n := &notifier{
+		wake: make(chan struct{}, 1),
+		out:  make(chan *types.Notification, 32),
+	}
+
+	go n.run()
+
+	return n
+}
+
+func (n *notifier) send(notifications []*types.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+
+	n.mu.Lock()
+	if !n.closed {
+		n.queue = append(n.queue, notifications...)
+	}
+	n.mu.Unlock()
+
+	n.signal()
+}
+
+func (n *notifier) close() {
+	n.mu.Lock()
+	n.closed = true
+	n.mu.Unlock()
+
+	n.signal()
+}
+
+func (n *notifier) signal() {
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (n *notifier) run() {
+	for {
+		n.mu.Lock()
+		if len(n.queue) == 0 {
+			closed := n.closed
+			n.mu.Unlock()
+
+			if closed {
+				close(n.out)
+				return
+			}
+
+			<-n.wake
+			continue
+		}
+
+		next := n.queue[0]
+		n.queue = n.queue[1:]
+		n.mu.Unlock()
+
+		n.out <- next
+	}
+}
@@ -0,0 +1,128 @@
+package memorydriver
+
+import (
+	"context"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* appPlanHistoryRow is a period an Application had a pay plan, the table has no triggers */
+type appPlanHistoryRow struct {
+	ApplicationID string
+	PayPlan       string
+	CustomLimit   *int
+	EffectiveFrom time.Time
+	EffectiveTo   time.Time
+}
+
+/*
+ReadAppPlanHistory returns every pay plan period of an Application that overlaps the time range, oldest first.
+A zero to reads until now.
+*/
+func (m *MemoryDriver) ReadAppPlanHistory(ctx context.Context, appID string, from, to time.Time) ([]*types.AppPlanPeriod, error) {
+	if appID == "" {
+		return nil, types.ErrMissingID
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.After(to) {
+		return nil, types.ErrInvalidTimeRange
+	}
+
+	var periods []*types.AppPlanPeriod
+	err := m.read(ctx, func(s *state) error {
+		// Periods are appended in the order they start
+		for _, period := range s.appPlanHistory {
+			if period.ApplicationID != appID || period.EffectiveFrom.After(to) {
+				continue
+			}
+			if !period.EffectiveTo.IsZero() && !period.EffectiveTo.After(from) {
+				continue
+			}
+
+			periods = append(periods, &types.AppPlanPeriod{
+				ApplicationID: period.ApplicationID,
+				Limit: types.AppLimit{
+					PayPlan: types.PayPlan{
+						Type:  types.PayPlanType(period.PayPlan),
+						Limit: s.payPlans[types.PayPlanType(period.PayPlan)],
+					},
+					CustomLimit: intValue(period.CustomLimit),
+				},
+				EffectiveFrom: period.EffectiveFrom,
+				EffectiveTo:   period.EffectiveTo,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+/* ReadAppPlanAt returns the pay plan period of an Application in effect at a time, or types.ErrNotFound if there is none */
+func (m *MemoryDriver) ReadAppPlanAt(ctx context.Context, appID string, at time.Time) (*types.AppPlanPeriod, error) {
+	periods, err := m.ReadAppPlanHistory(ctx, appID, at, at)
+	if err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return periods[0], nil
+}
+
+/*
+recordAppPlanChange ends the Application's current pay plan period and starts one with its stored limit at changedAt,
+nothing is recorded if the plan and custom limit did not change
+*/
+func (t *transaction) recordAppPlanChange(appID string, changedAt time.Time) {
+	s := t.state
+
+	limit, ok := s.appLimits[appID]
+	if !ok {
+		return
+	}
+	changedAt = dbTime(changedAt)
+
+	unchanged := false
+	for i, period := range s.appPlanHistory {
+		if period.ApplicationID != appID || !period.EffectiveTo.IsZero() {
+			continue
+		}
+
+		if period.PayPlan == limit.PayPlan && sameNullableInt(period.CustomLimit, limit.CustomLimit) {
+			unchanged = true
+			continue
+		}
+
+		closed := *period
+		closed.EffectiveTo = changedAt
+		s.appPlanHistory[i] = &closed
+	}
+	if unchanged {
+		return
+	}
+
+	s.appPlanHistory = append(s.appPlanHistory, &appPlanHistoryRow{
+		ApplicationID: appID,
+		PayPlan:       limit.PayPlan,
+		CustomLimit:   limit.CustomLimit,
+		EffectiveFrom: changedAt,
+	})
+}
+
+/* sameNullableInt compares two INT columns the way IS NOT DISTINCT FROM does */
+func sameNullableInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,222 @@
+package memorydriver
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+type secretKeyRow struct {
+	// id orders keys created at the same time, like the table's identity column
+	id            int
+	ApplicationID string    `json:"application_id"`
+	Name          string    `json:"name"`
+	SecretKey     string    `json:"secret_key"`
+	CreatedAt     timestamp `json:"created_at"`
+	ExpiresAt     timestamp `json:"expires_at"`
+	RevokedAt     timestamp `json:"revoked_at"`
+}
+
+/*
+RotateSecretKey generates a new named secret key for an Application and makes it the Application's SecretKey.
+Every key that is still valid, including a SecretKey set before named keys were used, expires once the overlap
+has passed so gateways keep accepting the old keys during the rollover.
+A name can be rotated again, like the default key every Application is created with, its previous key then
+expiring with the others.
+Only the hash of the new key is stored, so the returned key cannot be read again.
+*/
+func (m *MemoryDriver) RotateSecretKey(ctx context.Context, appID, name string, overlap time.Duration) (*types.SecretKey, error) {
+	if appID == "" {
+		return nil, types.ErrMissingID
+	}
+	if name == "" {
+		return nil, types.ErrMissingSecretKeyName
+	}
+	if overlap < 0 {
+		return nil, types.ErrNegativeOverlap
+	}
+
+	key, err := generateRandomSecretKey()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	secretKey := &types.SecretKey{
+		ID:        appID,
+		Name:      name,
+		Key:       key,
+		CreatedAt: now,
+	}
+	expiresAt := newTimestamp(now.Add(overlap))
+
+	hashedKey, err := types.HashSecretKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		// The legacy key is stored as the default key, already expiring, so the caller may rotate the default name too
+		settings, hasSettings := s.gatewaySettings[appID]
+		if hasSettings && settings.SecretKey != nil && len(s.applicationSecretKeys(appID)) == 0 {
+			err := tx.insertSecretKey(&secretKeyRow{
+				ApplicationID: appID,
+				Name:          defaultSecretKeyName,
+				SecretKey:     *settings.SecretKey,
+				CreatedAt:     newTimestamp(now),
+				ExpiresAt:     expiresAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for i, current := range s.secretKeys {
+			if current.ApplicationID != appID || !current.RevokedAt.IsZero() {
+				continue
+			}
+			if !current.ExpiresAt.IsZero() && !current.ExpiresAt.After(expiresAt.Time) {
+				continue
+			}
+
+			expired := *current
+			expired.ExpiresAt = expiresAt
+			s.secretKeys[i] = &expired
+			tx.changed(types.ActionUpdate, current, &expired)
+		}
+
+		err := tx.insertSecretKey(&secretKeyRow{
+			ApplicationID: appID,
+			Name:          name,
+			SecretKey:     hashedKey,
+			CreatedAt:     newTimestamp(now),
+		})
+		if err != nil {
+			return err
+		}
+
+		updatedSettings := &gatewaySettingsRow{ApplicationID: appID}
+		if hasSettings {
+			copied := *settings
+			updatedSettings = &copied
+		}
+		updatedSettings.SecretKey = &hashedKey
+		tx.upsertGatewaySettings(updatedSettings)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return secretKey, nil
+}
+
+/*
+RevokeSecretKey immediately invalidates a named secret key of an Application, with the previous keys of its name.
+If one of them is the Application's current SecretKey, the SecretKey is cleared.
+*/
+func (m *MemoryDriver) RevokeSecretKey(ctx context.Context, appID, name string) error {
+	if appID == "" {
+		return types.ErrMissingID
+	}
+	if name == "" {
+		return types.ErrMissingSecretKeyName
+	}
+
+	return m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		settings, hasSettings := s.gatewaySettings[appID]
+		var revokedAny, revokedCurrent bool
+		for i, current := range s.secretKeys {
+			if current.ApplicationID != appID || current.Name != name || !current.RevokedAt.IsZero() {
+				continue
+			}
+
+			revoked := *current
+			revoked.RevokedAt = newTimestamp(time.Now())
+			s.secretKeys[i] = &revoked
+			tx.changed(types.ActionUpdate, current, &revoked)
+			revokedAny = true
+			if hasSettings && settings.SecretKey != nil && *settings.SecretKey == revoked.SecretKey {
+				revokedCurrent = true
+			}
+		}
+		if !revokedAny {
+			return types.ErrSecretKeyNotFound
+		}
+
+		if revokedCurrent {
+			updatedSettings := *settings
+			updatedSettings.SecretKey = nil
+			tx.upsertGatewaySettings(&updatedSettings)
+		}
+
+		return nil
+	})
+}
+
+/* applicationSecretKeys returns the named secret keys of an Application, oldest first */
+func (s *state) applicationSecretKeys(appID string) []types.SecretKey {
+	var keys []*secretKeyRow
+	for _, key := range s.secretKeys {
+		if key.ApplicationID == appID {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt.Time) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt.Time)
+		}
+
+		return keys[i].id < keys[j].id
+	})
+
+	var secretKeys []types.SecretKey
+	for _, key := range keys {
+		secretKeys = append(secretKeys, *key.toOutput().(*types.SecretKey))
+	}
+
+	return secretKeys
+}
+
+func (t *transaction) insertSecretKey(key *secretKeyRow) error {
+	if _, ok := t.state.applications[key.ApplicationID]; !ok {
+		return referenceViolation(types.TableSecretKeys, "fk_application")
+	}
+	for _, current := range t.state.secretKeys {
+		if current.ApplicationID == key.ApplicationID && current.Name == key.Name && current.isCurrent() && key.isCurrent() {
+			return uniqueViolation("secret_keys_application_id_name_key")
+		}
+	}
+
+	key.id = t.state.nextID()
+	t.state.secretKeys = append(t.state.secretKeys, key)
+	t.changed(types.ActionInsert, nil, key)
+
+	return nil
+}
+
+/* isCurrent reports whether the key is neither revoked nor expiring, only one such key may have a name */
+func (r *secretKeyRow) isCurrent() bool {
+	return r.RevokedAt.IsZero() && r.ExpiresAt.IsZero()
+}
+
+func (r *secretKeyRow) table() types.Table { return types.TableSecretKeys }
+func (r *secretKeyRow) entityID() string   { return r.ApplicationID }
+func (r *secretKeyRow) toOutput() types.SavedOnDB {
+	return &types.SecretKey{
+		ID:        r.ApplicationID,
+		Name:      r.Name,
+		Key:       r.SecretKey,
+		CreatedAt: r.CreatedAt.Time,
+		ExpiresAt: r.ExpiresAt.Time,
+		RevokedAt: r.RevokedAt.Time,
+	}
+}
@@ -0,0 +1,103 @@
+package memorydriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func Test_RotateSecretKey(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	app, err := m.WriteApplication(testCtx, testApplication())
+	c.NoError(err)
+	receiveNotifications(t, m, 5)
+
+	secretKey, err := m.RotateSecretKey(testCtx, app.ID, "rotated", time.Hour)
+	c.NoError(err)
+	c.Len(secretKey.Key, secretKeyLength)
+
+	// The default key expires, then the new key is inserted and made the Application's SecretKey
+	c.Equal([]types.Table{
+		types.TableSecretKeys, types.TableSecretKeys, types.TableGatewaySettings,
+	}, notificationTables(receiveNotifications(t, m, 3)))
+
+	apps, err := m.ReadApplications(testCtx)
+	c.NoError(err)
+	settings := apps[0].GatewaySettings
+	c.True(types.VerifySecretKey(settings.SecretKey, secretKey.Key))
+	c.Len(settings.ValidSecretKeys(time.Now()), 2)
+	c.Len(settings.ValidSecretKeys(time.Now().Add(2*time.Hour)), 1)
+	c.True(settings.VerifySecretKey(app.GatewaySettings.SecretKey, time.Now()))
+
+	// Rotating a name again expires its previous key, like the default key the Application was created with
+	rotatedAgain, err := m.RotateSecretKey(testCtx, app.ID, "rotated", time.Hour)
+	c.NoError(err)
+	receiveNotifications(t, m, 3)
+
+	_, err = m.RotateSecretKey(testCtx, app.ID, defaultSecretKeyName, time.Hour)
+	c.NoError(err)
+	receiveNotifications(t, m, 3)
+
+	apps, err = m.ReadApplications(testCtx)
+	c.NoError(err)
+	settings = apps[0].GatewaySettings
+	c.Len(settings.SecretKeys, 4)
+	c.Len(settings.ValidSecretKeys(time.Now()), 4)
+	c.Len(settings.ValidSecretKeys(time.Now().Add(2*time.Hour)), 1)
+	c.False(settings.VerifySecretKey(rotatedAgain.Key, time.Now().Add(2*time.Hour)))
+
+	_, err = m.RotateSecretKey(testCtx, app.ID, "", time.Hour)
+	c.Equal(types.ErrMissingSecretKeyName, err)
+}
+
+func Test_RevokeSecretKey(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	app, err := m.WriteApplication(testCtx, testApplication())
+	c.NoError(err)
+	receiveNotifications(t, m, 5)
+
+	err = m.RevokeSecretKey(testCtx, app.ID, defaultSecretKeyName)
+	c.NoError(err)
+
+	// The revoked key was the Application's SecretKey so it is cleared
+	c.Equal([]types.Table{types.TableSecretKeys, types.TableGatewaySettings}, notificationTables(receiveNotifications(t, m, 2)))
+
+	apps, err := m.ReadApplications(testCtx)
+	c.NoError(err)
+	c.Empty(apps[0].GatewaySettings.SecretKey)
+	c.Empty(apps[0].GatewaySettings.ValidSecretKeys(time.Now()))
+
+	err = m.RevokeSecretKey(testCtx, app.ID, defaultSecretKeyName)
+	c.Equal(types.ErrSecretKeyNotFound, err)
+}
+
+func Test_RevokeSecretKey_RotatedName(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	app, err := m.WriteApplication(testCtx, testApplication())
+	c.NoError(err)
+	receiveNotifications(t, m, 5)
+
+	_, err = m.RotateSecretKey(testCtx, app.ID, defaultSecretKeyName, time.Hour)
+	c.NoError(err)
+	receiveNotifications(t, m, 3)
+
+	// Both keys of the name are revoked, the current one clearing the Application's SecretKey
+	err = m.RevokeSecretKey(testCtx, app.ID, defaultSecretKeyName)
+	c.NoError(err)
+	c.Equal([]types.Table{
+		types.TableSecretKeys, types.TableSecretKeys, types.TableGatewaySettings,
+	}, notificationTables(receiveNotifications(t, m, 3)))
+
+	apps, err := m.ReadApplications(testCtx)
+	c.NoError(err)
+	c.Empty(apps[0].GatewaySettings.SecretKey)
+	c.Empty(apps[0].GatewaySettings.ValidSecretKeys(time.Now()))
+}
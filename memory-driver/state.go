@@ -0,0 +1,110 @@
+package memorydriver
+
+import (
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/*
+state holds every table. Rows are never changed once stored, a write stores a changed copy instead,
+which lets a transaction copy the state by copying only its maps and slices.
+*/
+type state struct {
+	payPlans  map[types.PayPlanType]int
+	userRoles map[types.RoleName][]types.PermissionsEnum
+
+	blockchains      map[string]*blockchainRow
+	syncCheckOptions map[string]*syncCheckOptionsRow
+	redirects        []*redirectRow
+
+	loadBalancers     map[string]*loadBalancerRow
+	stickinessOptions map[string]*stickinessOptionsRow
+	lbLimits          map[string]*lbLimitRow
+	userAccess        []*userAccessRow
+	lbApps            []*lbAppRow
+
+	applications         map[string]*applicationRow
+	appLimits            map[string]*appLimitRow
+	appPlanHistory       []*appPlanHistoryRow
+	gatewayAAT           map[string]*gatewayAATRow
+	gatewaySettings      map[string]*gatewaySettingsRow
+	secretKeys           []*secretKeyRow
+	whitelistContracts   []*whitelistContractRow
+	whitelistMethods     []*whitelistMethodRow
+	notificationSettings map[string]*notificationSettingsRow
+
+	relayUsage          map[usageKey]int64
+	sentUsageThresholds map[usageThresholdKey]time.Time
+
+	auditLog []*types.AuditLogEntry
+
+	// lastID is the last value of the identity columns the order of some reads depends on
+	lastID int
+}
+
+func newState() *state {
+	return &state{
+		payPlans:             make(map[types.PayPlanType]int),
+		userRoles:            make(map[types.RoleName][]types.PermissionsEnum),
+		blockchains:          make(map[string]*blockchainRow),
+		syncCheckOptions:     make(map[string]*syncCheckOptionsRow),
+		loadBalancers:        make(map[string]*loadBalancerRow),
+		stickinessOptions:    make(map[string]*stickinessOptionsRow),
+		lbLimits:             make(map[string]*lbLimitRow),
+		applications:         make(map[string]*applicationRow),
+		appLimits:            make(map[string]*appLimitRow),
+		gatewayAAT:           make(map[string]*gatewayAATRow),
+		gatewaySettings:      make(map[string]*gatewaySettingsRow),
+		notificationSettings: make(map[string]*notificationSettingsRow),
+		relayUsage:           make(map[usageKey]int64),
+		sentUsageThresholds:  make(map[usageThresholdKey]time.Time),
+	}
+}
+
+func (s *state) clone() *state {
+	return &state{
+		payPlans:             copyMap(s.payPlans),
+		userRoles:            copyMap(s.userRoles),
+		blockchains:          copyMap(s.blockchains),
+		syncCheckOptions:     copyMap(s.syncCheckOptions),
+		redirects:            copySlice(s.redirects),
+		loadBalancers:        copyMap(s.loadBalancers),
+		stickinessOptions:    copyMap(s.stickinessOptions),
+		lbLimits:             copyMap(s.lbLimits),
+		userAccess:           copySlice(s.userAccess),
+		lbApps:               copySlice(s.lbApps),
+		applications:         copyMap(s.applications),
+		appLimits:            copyMap(s.appLimits),
+		appPlanHistory:       copySlice(s.appPlanHistory),
+		gatewayAAT:           copyMap(s.gatewayAAT),
+		gatewaySettings:      copyMap(s.gatewaySettings),
+		secretKeys:           copySlice(s.secretKeys),
+		whitelistContracts:   copySlice(s.whitelistContracts),
+		whitelistMethods:     copySlice(s.whitelistMethods),
+		notificationSettings: copyMap(s.notificationSettings),
+		relayUsage:           copyMap(s.relayUsage),
+		sentUsageThresholds:  copyMap(s.sentUsageThresholds),
+		auditLog:             copySlice(s.auditLog),
+		lastID:               s.lastID,
+	}
+}
+
+/* nextID returns the next value of the identity columns */
+func (s *state) nextID() int {
+	s.lastID++
+	return s.lastID
+}
+
+func copyMap[K comparable, V any](values map[K]V) map[K]V {
+	copied := make(map[K]V, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+func copySlice[V any](values []V) []V {
+	return append([]V(nil), values...)
+}
@@ -0,0 +1,139 @@
+package memorydriver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/driver"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/*
+transaction is a copy of the state being written, with the notifications the triggers would send once it commits.
+The rows in the state are never changed in place, so copying it only copies its maps and slices.
+*/
+type transaction struct {
+	ctx           context.Context
+	state         *state
+	notifications []*types.Notification
+	startedAt     time.Time
+	readOnly      bool
+}
+
+func newTransaction(ctx context.Context, committed *state, readOnly bool) *transaction {
+	return &transaction{
+		ctx:       ctx,
+		state:     committed.clone(),
+		startedAt: time.Now(),
+		readOnly:  readOnly,
+	}
+}
+
+/* InTx runs fn inside a single transaction, committing if fn returns nil and rolling back otherwise */
+func (m *MemoryDriver) InTx(ctx context.Context, opts *driver.TxOptions, fn func(w driver.Writer) error) error {
+	if fn == nil {
+		return types.ErrNilTxFunc
+	}
+
+	err := checkContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	readOnly := opts != nil && opts.ReadOnly
+
+	// A transaction started inside another one runs in a savepoint of it
+	if m.tx != nil {
+		savepoint := m.tx.savepoint(ctx)
+		savepoint.readOnly = savepoint.readOnly || readOnly
+
+		err = fn(&MemoryDriver{db: m.db, tx: savepoint})
+		if err != nil {
+			return err
+		}
+
+		m.tx.release(savepoint)
+		return nil
+	}
+
+	// Reads through m keep seeing the committed state, writes through it wait for the transaction to end
+	m.db.writeMu.Lock()
+	defer m.db.writeMu.Unlock()
+
+	tx := newTransaction(ctx, m.db.committed(), readOnly)
+	err = fn(&MemoryDriver{db: m.db, tx: tx})
+	if err != nil {
+		return err
+	}
+
+	err = checkContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.db.commit(tx)
+
+	return nil
+}
+
+/* savepoint returns a copy of the transaction a single Writer method runs on, so a failed method leaves it unchanged */
+func (t *transaction) savepoint(ctx context.Context) *transaction {
+	return &transaction{
+		ctx:       ctx,
+		state:     t.state.clone(),
+		startedAt: t.startedAt,
+		readOnly:  t.readOnly,
+	}
+}
+
+/* release keeps the changes made in a savepoint */
+func (t *transaction) release(savepoint *transaction) {
+	t.state = savepoint.state
+	t.notifications = append(t.notifications, savepoint.notifications...)
+}
+
+/*
+changed records a row change the way the database triggers do: an audit log entry with the changed columns
+and a notification if the table's notify trigger fires on the action. before is nil for inserts and after for deletes.
+*/
+func (t *transaction) changed(action types.Action, before, after row) {
+	changedRow := after
+	if action == types.ActionDelete {
+		changedRow = before
+	}
+
+	t.state.audit(t, action, before, after)
+
+	if types.NotifiedActions[changedRow.table()][action] {
+		data := changedRow.toOutput()
+		// Load balancer limits are sent with their plan's daily limit, like the PostgresDriver does
+		if limit, ok := data.(*types.LBLimit); ok {
+			limit.PayPlan.Limit = t.state.payPlans[limit.PayPlan.Type]
+		}
+
+		t.notifications = append(t.notifications, &types.Notification{
+			Table:  changedRow.table(),
+			Action: action,
+			Data:   data,
+		})
+	}
+}
+
+/* uniqueViolation returns the error the PostgresDriver returns when an insert breaks a unique constraint */
+func uniqueViolation(constraint string) error {
+	return &types.ConstraintError{
+		Kind:       types.ErrAlreadyExists,
+		Constraint: constraint,
+		Err:        fmt.Errorf("duplicate key value violates unique constraint %q", constraint),
+	}
+}
+
+/* referenceViolation returns the error the PostgresDriver returns when a row refers to one that does not exist */
+func referenceViolation(table types.Table, constraint string) error {
+	return &types.ConstraintError{
+		Kind:       types.ErrReferenceViolation,
+		Constraint: constraint,
+		Err:        fmt.Errorf("insert or update on table %q violates foreign key constraint %q", table, constraint),
+	}
+}
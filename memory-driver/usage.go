@@ -0,0 +1,286 @@
+package memorydriver
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const tableRelayUsage types.Table = "relay_usage"
+
+type (
+	// usageKey is the primary key of the relay_usage table, date is a types.UsageDay
+	usageKey struct {
+		appID string
+		date  time.Time
+	}
+	// usageThresholdKey is the primary key of the usage_threshold_notifications table
+	usageThresholdKey struct {
+		appID     string
+		date      time.Time
+		threshold types.UsageThreshold
+	}
+)
+
+/*
+IncrementUsage atomically adds relays to the Application's usage for the current UTC day and returns the day's total.
+The first time the usage goes over the Application's DailyLimit its FirstDateSurpassed is set.
+*/
+func (m *MemoryDriver) IncrementUsage(ctx context.Context, appID string, relays int64) (int64, error) {
+	if appID == "" {
+		return 0, types.ErrMissingID
+	}
+	if relays < 0 {
+		return 0, types.ErrNegativeRelays
+	}
+
+	now := time.Now()
+	key := usageKey{appID: appID, date: types.UsageDay(now)}
+
+	var total int64
+	err := m.write(ctx, func(tx *transaction) error {
+		if _, ok := tx.state.applications[appID]; !ok {
+			return referenceViolation(tableRelayUsage, "fk_application")
+		}
+
+		tx.state.relayUsage[key] += relays
+		total = tx.state.relayUsage[key]
+
+		tx.markFirstDateSurpassed([]usageKey{key}, now)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+/*
+FlushUsage adds a batch of relay counts at once. Counts for the same Application and day are summed
+and a zero Date counts as the current UTC day.
+Applications whose usage goes over their DailyLimit for the first time get their FirstDateSurpassed set.
+*/
+func (m *MemoryDriver) FlushUsage(ctx context.Context, usage []types.RelayUsage) error {
+	now := time.Now()
+
+	totals := make(map[usageKey]int64)
+	for _, count := range usage {
+		if count.ApplicationID == "" {
+			return types.ErrMissingID
+		}
+		if count.Relays < 0 {
+			return types.ErrNegativeRelays
+		}
+		if count.Relays == 0 {
+			continue
+		}
+
+		date := count.Date
+		if date.IsZero() {
+			date = now
+		}
+
+		totals[usageKey{appID: count.ApplicationID, date: types.UsageDay(date)}] += count.Relays
+	}
+	if len(totals) == 0 {
+		return nil
+	}
+
+	keys := make([]usageKey, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].appID != keys[j].appID {
+			return keys[i].appID < keys[j].appID
+		}
+		return keys[i].date.Before(keys[j].date)
+	})
+
+	return m.write(ctx, func(tx *transaction) error {
+		for _, key := range keys {
+			if _, ok := tx.state.applications[key.appID]; !ok {
+				return referenceViolation(tableRelayUsage, "fk_application")
+			}
+
+			tx.state.relayUsage[key] += totals[key]
+		}
+
+		tx.markFirstDateSurpassed(keys, now)
+
+		return nil
+	})
+}
+
+/* markFirstDateSurpassed sets FirstDateSurpassed of the Applications that have none and whose usage on one of the days is over their DailyLimit */
+func (t *transaction) markFirstDateSurpassed(keys []usageKey, now time.Time) {
+	s := t.state
+
+	for _, key := range keys {
+		app := s.applications[key.appID]
+		if app == nil || !app.FirstDateSurpassed.IsZero() {
+			continue
+		}
+
+		limit, ok := s.appLimits[key.appID]
+		if !ok {
+			continue
+		}
+		dailyLimit := types.AppLimit{
+			PayPlan: types.PayPlan{
+				Type:  types.PayPlanType(limit.PayPlan),
+				Limit: s.payPlans[types.PayPlanType(limit.PayPlan)],
+			},
+			CustomLimit: intValue(limit.CustomLimit),
+		}.DailyLimit()
+		if dailyLimit <= 0 || s.relayUsage[key] <= int64(dailyLimit) {
+			continue
+		}
+
+		updatedApp := *app
+		updatedApp.FirstDateSurpassed = newTimestamp(now)
+		s.applications[key.appID] = &updatedApp
+		t.changed(types.ActionUpdate, app, &updatedApp)
+	}
+}
+
+/* ReadUsage returns the daily relay usage matching the filter, oldest day first */
+func (m *MemoryDriver) ReadUsage(ctx context.Context, filter types.RelayUsageFilter) ([]*types.RelayUsage, error) {
+	from := types.UsageDay(filter.From)
+	to := types.UsageDay(filter.To)
+
+	var usage []*types.RelayUsage
+	err := m.read(ctx, func(s *state) error {
+		for key, relays := range s.relayUsage {
+			if filter.ApplicationID != "" && key.appID != filter.ApplicationID {
+				continue
+			}
+			if key.date.Before(from) || (!filter.To.IsZero() && key.date.After(to)) {
+				continue
+			}
+
+			usage = append(usage, &types.RelayUsage{
+				ApplicationID: key.appID,
+				Date:          key.date,
+				Relays:        relays,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if !usage[i].Date.Equal(usage[j].Date) {
+			return usage[i].Date.Before(usage[j].Date)
+		}
+		return usage[i].ApplicationID < usage[j].ApplicationID
+	})
+
+	return usage, nil
+}
+
+/*
+ReadApplicationsOverLimit returns the Applications whose usage today is over their DailyLimit.
+Applications without a limit are never over it.
+*/
+func (m *MemoryDriver) ReadApplicationsOverLimit(ctx context.Context) ([]*types.ApplicationUsage, error) {
+	today := types.UsageDay(time.Now())
+
+	var apps []*types.ApplicationUsage
+	err := m.read(ctx, func(s *state) error {
+		for key, relays := range s.relayUsage {
+			if !key.date.Equal(today) {
+				continue
+			}
+
+			app, ok := s.applications[key.appID]
+			if !ok {
+				continue
+			}
+			if _, ok := s.appLimits[key.appID]; !ok {
+				continue
+			}
+
+			quota := s.toApplication(app).Quota(relays)
+			if !quota.IsExceeded() {
+				continue
+			}
+
+			apps = append(apps, &types.ApplicationUsage{
+				ApplicationID:      key.appID,
+				Date:               today,
+				FirstDateSurpassed: app.FirstDateSurpassed.Time,
+				Quota:              quota,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(apps, func(i, j int) bool { return apps[i].ApplicationID < apps[j].ApplicationID })
+
+	return apps, nil
+}
+
+/*
+EvaluateUsageThresholds returns an event for each threshold of the daily limit the Application's usage
+reached today and that its NotificationSettings opted in to. Each threshold fires at most once per Application and UTC day.
+*/
+func (m *MemoryDriver) EvaluateUsageThresholds(ctx context.Context, appID string) ([]*types.UsageThresholdEvent, error) {
+	if appID == "" {
+		return nil, types.ErrMissingID
+	}
+
+	now := time.Now()
+	today := types.UsageDay(now)
+
+	var events []*types.UsageThresholdEvent
+	err := m.write(ctx, func(tx *transaction) error {
+		s := tx.state
+
+		appRow, ok := s.applications[appID]
+		if !ok {
+			return types.ErrNotFound
+		}
+
+		app := s.toApplication(appRow)
+		quota := app.Quota(s.relayUsage[usageKey{appID: appID, date: today}])
+
+		for _, threshold := range app.NotificationSettings.ReachedThresholds(quota) {
+			key := usageThresholdKey{appID: appID, date: today, threshold: threshold}
+			if _, sent := s.sentUsageThresholds[key]; sent {
+				continue
+			}
+			s.sentUsageThresholds[key] = now
+
+			events = append(events, &types.UsageThresholdEvent{
+				ApplicationID: app.ID,
+				Name:          app.Name,
+				UserID:        app.UserID,
+				ContactEmail:  app.ContactEmail,
+				Date:          today,
+				Threshold:     threshold,
+				Quota:         quota,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Threshold < events[j].Threshold })
+
+	return events, nil
+}
@@ -0,0 +1,129 @@
+package memorydriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/* writeTestPlanApplication writes an Application on the TestPlanV0 pay plan, which has a daily limit of 100 relays */
+func writeTestPlanApplication(t *testing.T, m *MemoryDriver) *types.Application {
+	t.Helper()
+
+	app := testApplication()
+	app.Limit.PayPlan.Type = types.TestPlanV0
+	app.NotificationSettings = types.NotificationSettings{Half: true, Full: true}
+
+	app, err := m.WriteApplication(testCtx, app)
+	require.NoError(t, err)
+	receiveNotifications(t, m, 5)
+
+	return app
+}
+
+func Test_IncrementUsage(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	app := writeTestPlanApplication(t, m)
+
+	total, err := m.IncrementUsage(testCtx, app.ID, 60)
+	c.NoError(err)
+	c.Equal(int64(60), total)
+	receiveNotifications(t, m, 0)
+
+	overLimit, err := m.ReadApplicationsOverLimit(testCtx)
+	c.NoError(err)
+	c.Empty(overLimit)
+
+	// Going over the daily limit sets FirstDateSurpassed once
+	total, err = m.IncrementUsage(testCtx, app.ID, 60)
+	c.NoError(err)
+	c.Equal(int64(120), total)
+	notifications := receiveNotifications(t, m, 1)
+	c.False(notifications[0].Data.(*types.Application).FirstDateSurpassed.IsZero())
+
+	_, err = m.IncrementUsage(testCtx, app.ID, 1)
+	c.NoError(err)
+	receiveNotifications(t, m, 0)
+
+	overLimit, err = m.ReadApplicationsOverLimit(testCtx)
+	c.NoError(err)
+	c.Len(overLimit, 1)
+	c.Equal(app.ID, overLimit[0].ApplicationID)
+	c.Equal(int64(121), overLimit[0].Quota.Used)
+
+	_, err = m.IncrementUsage(testCtx, "not_an_app", 1)
+	c.ErrorIs(err, types.ErrReferenceViolation)
+
+	_, err = m.IncrementUsage(testCtx, app.ID, -1)
+	c.Equal(types.ErrNegativeRelays, err)
+}
+
+func Test_FlushUsage(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	app := writeTestPlanApplication(t, m)
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	err := m.FlushUsage(testCtx, []types.RelayUsage{
+		{ApplicationID: app.ID, Relays: 10},
+		{ApplicationID: app.ID, Relays: 5},
+		{ApplicationID: app.ID, Date: yesterday, Relays: 7},
+	})
+	c.NoError(err)
+
+	usage, err := m.ReadUsage(testCtx, types.RelayUsageFilter{ApplicationID: app.ID})
+	c.NoError(err)
+	c.Equal([]*types.RelayUsage{
+		{ApplicationID: app.ID, Date: types.UsageDay(yesterday), Relays: 7},
+		{ApplicationID: app.ID, Date: types.UsageDay(time.Now()), Relays: 15},
+	}, usage)
+
+	// Nothing is counted if one of the Applications does not exist
+	err = m.FlushUsage(testCtx, []types.RelayUsage{
+		{ApplicationID: app.ID, Relays: 10},
+		{ApplicationID: "not_an_app", Relays: 10},
+	})
+	c.ErrorIs(err, types.ErrReferenceViolation)
+
+	usage, err = m.ReadUsage(testCtx, types.RelayUsageFilter{ApplicationID: app.ID, From: time.Now()})
+	c.NoError(err)
+	c.Equal(int64(15), usage[0].Relays)
+}
+
+func Test_EvaluateUsageThresholds(t *testing.T) {
+	c := require.New(t)
+
+	m := NewMemoryDriver()
+	app := writeTestPlanApplication(t, m)
+
+	_, err := m.IncrementUsage(testCtx, app.ID, 80)
+	c.NoError(err)
+
+	// Only the thresholds the Application opted in to are returned
+	events, err := m.EvaluateUsageThresholds(testCtx, app.ID)
+	c.NoError(err)
+	c.Len(events, 1)
+	c.Equal(types.ThresholdHalf, events[0].Threshold)
+	c.Equal(int64(80), events[0].Quota.Used)
+
+	events, err = m.EvaluateUsageThresholds(testCtx, app.ID)
+	c.NoError(err)
+	c.Empty(events)
+
+	_, err = m.IncrementUsage(testCtx, app.ID, 20)
+	c.NoError(err)
+
+	events, err = m.EvaluateUsageThresholds(testCtx, app.ID)
+	c.NoError(err)
+	c.Len(events, 1)
+	c.Equal(types.ThresholdFull, events[0].Threshold)
+
+	_, err = m.EvaluateUsageThresholds(testCtx, "not_an_app")
+	c.ErrorIs(err, types.ErrNotFound)
+}
@@ -11,15 +11,26 @@ import (
 )
 
 /* ReadApplications returns all Applications in the database */
-func (p *PostgresDriver) ReadApplications(ctx context.Context) ([]*types.Application, error) {
-	dbApplications, err := p.SelectApplications(ctx)
+func (p *PostgresDriver) ReadApplications(ctx context.Context) (_ []*types.Application, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	dbApplications, err := p.SelectApplications(readCtx)
 	if err != nil {
 		return nil, err
 	}
 
 	var applications []*types.Application
 	for _, dbApplication := range dbApplications {
-		applications = append(applications, dbApplication.toApplication())
+		application := dbApplication.toApplication()
+
+		err = p.decryptApplication(ctx, application)
+		if err != nil {
+			return nil, err
+		}
+
+		applications = append(applications, application)
 	}
 
 	return applications, nil
@@ -38,6 +49,7 @@ func (a *SelectApplicationsRow) toApplication() *types.Application {
 		URL:                a.Url.String,
 		Dummy:              a.Dummy.Bool,
 		FirstDateSurpassed: a.FirstDateSurpassed.Time,
+		Version:            int(a.Version),
 
 		GatewayAAT: types.GatewayAAT{
 			Address:              a.GaAddress.String,
@@ -50,6 +62,7 @@ func (a *SelectApplicationsRow) toApplication() *types.Application {
 		GatewaySettings: types.GatewaySettings{
 			SecretKey:            a.SecretKey.String,
 			SecretKeyRequired:    a.SecretKeyRequired.Bool,
+			SecretKeys:           stringToSecretKeys(fmt.Sprintf("%v", a.SecretKeys)),
 			WhitelistBlockchains: a.WhitelistBlockchains,
 			WhitelistContracts:   stringToWhitelistContracts(fmt.Sprintf("%v", a.WhitelistContracts)),
 			WhitelistMethods:     stringToWhitelistMethods(fmt.Sprintf("%v", a.WhitelistMethods)),
@@ -112,8 +125,12 @@ func stringToWhitelistMethods(rawMethods string) []types.WhitelistMethod {
 }
 
 /* ReadPayPlans returns all pay plans in the database and marshals to types struct */
-func (p *PostgresDriver) ReadPayPlans(ctx context.Context) ([]*types.PayPlan, error) {
-	dbPayPlans, err := p.SelectPayPlans(ctx)
+func (p *PostgresDriver) ReadPayPlans(ctx context.Context) (_ []*types.PayPlan, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	dbPayPlans, err := p.SelectPayPlans(readCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -146,8 +163,10 @@ func (p *SelectPayPlansRow) toPayPlan() (*types.PayPlan, error) {
 	return &payPlan, nil
 }
 
-/* WriteApplication saves input Application to the database */
-func (p *PostgresDriver) WriteApplication(ctx context.Context, app *types.Application) (*types.Application, error) {
+/* WriteApplication saves input Application to the database and returns it with its generated secret key, which is not readable afterwards */
+func (p *PostgresDriver) WriteApplication(ctx context.Context, app *types.Application) (_ *types.Application, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	appIsInvalid := app.Validate()
 	if appIsInvalid != nil {
 		return nil, appIsInvalid
@@ -158,18 +177,40 @@ func (p *PostgresDriver) WriteApplication(ctx context.Context, app *types.Applic
 		return nil, err
 	}
 
+	secretKey, err := generateRandomSecretKey()
+	if err != nil {
+		return nil, err
+	}
+	hashedSecretKey, err := types.HashSecretKey(secretKey)
+	if err != nil {
+		return nil, err
+	}
+
 	app.ID = id
 	time := time.Now()
 	app.CreatedAt = time
 	app.UpdatedAt = time
-
-	tx, err := p.db.Begin()
+	// The generated secret key is only ever returned here, the database holds its hash
+	app.GatewaySettings.SecretKey = secretKey
+	app.GatewaySettings.SecretKeys = []types.SecretKey{{
+		ID:        id,
+		Name:      defaultSecretKeyName,
+		Key:       secretKey,
+		CreatedAt: time,
+	}}
+
+	tx, err := p.beginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	qtx := p.WithTx(tx)
+	qtx := p.WithTx(tx.Tx)
+
+	err = validateWhitelistBlockchains(ctx, qtx, app.GatewaySettings.WhitelistContracts, app.GatewaySettings.WhitelistMethods)
+	if err != nil {
+		return nil, err
+	}
 
 	err = qtx.InsertApplication(ctx, extractInsertDBApp(app))
 	if err != nil {
@@ -180,20 +221,30 @@ func (p *PostgresDriver) WriteApplication(ctx context.Context, app *types.Applic
 	if err != nil {
 		return nil, err
 	}
+	err = recordAppPlanChange(ctx, qtx, app.ID, app.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
 	gatewayAATParams := extractInsertDBGatewayAAT(app)
 	if gatewayAATParams.isNotNull() {
-		err = qtx.InsertGatewayAAT(ctx, gatewayAATParams)
+		gatewayAATParams.PrivateKey, err = p.encryptSecret(ctx, gatewayAATParams.PrivateKey)
 		if err != nil {
 			return nil, err
 		}
-	}
-	gatewaySettingsParams := extractInsertDBGatewaySettings(app)
-	if gatewaySettingsParams.isNotNull() {
-		err = qtx.InsertGatewaySettings(ctx, gatewaySettingsParams)
+
+		err = qtx.InsertGatewayAAT(ctx, gatewayAATParams)
 		if err != nil {
 			return nil, err
 		}
 	}
+	err = qtx.InsertGatewaySettings(ctx, extractInsertDBGatewaySettings(app, hashedSecretKey))
+	if err != nil {
+		return nil, err
+	}
+	err = qtx.InsertSecretKey(ctx, extractInsertDBSecretKey(&app.GatewaySettings.SecretKeys[0], hashedSecretKey))
+	if err != nil {
+		return nil, err
+	}
 	notificationSettingsParams := extractInsertDBNotificationSettings(app)
 	if notificationSettingsParams.isNotNull() {
 		err = qtx.InsertNotificationSettings(ctx, notificationSettingsParams)
@@ -210,6 +261,30 @@ func (p *PostgresDriver) WriteApplication(ctx context.Context, app *types.Applic
 	return app, nil
 }
 
+/*
+validateWhitelistBlockchains returns a ValidationError if a whitelisted contract or method refers to a blockchain that does not exist,
+or a contract of an EVM blockchain is not a valid address
+*/
+func validateWhitelistBlockchains(ctx context.Context, q *Queries, contracts []types.WhitelistContract, methods []types.WhitelistMethod) error {
+	if len(contracts) == 0 && len(methods) == 0 {
+		return nil
+	}
+
+	dbBlockchains, err := q.SelectBlockchainChainIDChecks(ctx)
+	if err != nil {
+		return err
+	}
+	blockchains := make(map[string]*types.Blockchain, len(dbBlockchains))
+	for _, dbBlockchain := range dbBlockchains {
+		blockchains[dbBlockchain.BlockchainID] = &types.Blockchain{ID: dbBlockchain.BlockchainID, ChainIDCheck: dbBlockchain.ChainIDCheck.String}
+	}
+
+	errs := &types.ValidationError{}
+	errs.Merge("gatewaySettings", types.ValidateWhitelistBlockchains(contracts, methods, blockchains))
+
+	return errs.ErrorOrNil()
+}
+
 func extractInsertDBApp(app *types.Application) InsertApplicationParams {
 	return InsertApplicationParams{
 		ApplicationID: app.ID,
@@ -249,16 +324,13 @@ func (i *InsertGatewayAATParams) isNotNull() bool {
 	return i.Version.Valid || i.PrivateKey.Valid
 }
 
-func extractInsertDBGatewaySettings(app *types.Application) InsertGatewaySettingsParams {
+func extractInsertDBGatewaySettings(app *types.Application, hashedSecretKey string) InsertGatewaySettingsParams {
 	return InsertGatewaySettingsParams{
 		ApplicationID:     app.ID,
-		SecretKey:         newSQLNullString(app.GatewaySettings.SecretKey),
+		SecretKey:         newSQLNullString(hashedSecretKey),
 		SecretKeyRequired: newSQLNullBool(&app.GatewaySettings.SecretKeyRequired),
 	}
 }
-func (i *InsertGatewaySettingsParams) isNotNull() bool {
-	return i.SecretKey.Valid
-}
 
 func extractInsertDBNotificationSettings(app *types.Application) InsertNotificationSettingsParams {
 	return InsertNotificationSettingsParams{
@@ -274,8 +346,15 @@ func (i *InsertNotificationSettingsParams) isNotNull() bool {
 	return true
 }
 
-/* UpdateApplication updates Application and related table rows */
-func (p *PostgresDriver) UpdateApplication(ctx context.Context, id string, update *types.UpdateApplication) error {
+/*
+	UpdateApplication updates Application and related table rows.
+
+It returns types.ErrNotFound if the Application does not exist. A new Status must be allowed from the current one
+by types.AppStatusTransitions, unless ctx has a status override
+*/
+func (p *PostgresDriver) UpdateApplication(ctx context.Context, id string, update *types.UpdateApplication) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	if id == "" {
 		return ErrMissingID
 	}
@@ -285,25 +364,49 @@ func (p *PostgresDriver) UpdateApplication(ctx context.Context, id string, updat
 		return invalidUpdate
 	}
 
-	tx, err := p.db.Begin()
+	tx, err := p.beginTx(ctx)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	qtx := p.WithTx(tx)
+	qtx := p.WithTx(tx.Tx)
 
-	err = qtx.UpsertApplication(ctx, extractUpsertApplication(id, update))
+	currentStatus, err := qtx.SelectApplicationStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = checkStatusTransition(ctx, types.AppStatus(currentStatus.String), update.Status)
 	if err != nil {
 		return err
 	}
 
+	if update.GatewaySettings != nil {
+		err = validateWhitelistBlockchains(ctx, qtx, update.GatewaySettings.WhitelistContracts, update.GatewaySettings.WhitelistMethods)
+		if err != nil {
+			return err
+		}
+	}
+
+	updatedRows, err := qtx.UpsertApplication(ctx, extractUpsertApplication(id, update))
+	if err != nil {
+		return err
+	}
+	if updatedRows == 0 {
+		return types.ErrConflict
+	}
+
 	appLimitParams := extractUpsertAppLimit(id, update)
 	if appLimitParams.isNotNull() {
 		err = qtx.UpsertAppLimit(ctx, *appLimitParams)
 		if err != nil {
 			return err
 		}
+
+		err = recordAppPlanChange(ctx, qtx, id, time.Now())
+		if err != nil {
+			return err
+		}
 	}
 
 	gatewaySettingsParams := extractUpsertGatewaySettings(id, update)
@@ -313,21 +416,23 @@ func (p *PostgresDriver) UpdateApplication(ctx context.Context, id string, updat
 			return err
 		}
 	}
-	for _, contract := range update.GatewaySettings.WhitelistContracts {
-		whitelistContractParams := extractUpsertWhitelistContracts(id, &contract)
-		if whitelistContractParams != nil {
-			err = qtx.UpsertWhitelistContracts(ctx, *whitelistContractParams)
-			if err != nil {
-				return err
+	if update.GatewaySettings != nil {
+		for _, contract := range update.GatewaySettings.WhitelistContracts {
+			whitelistContractParams := extractUpsertWhitelistContracts(id, &contract)
+			if whitelistContractParams != nil {
+				err = qtx.UpsertWhitelistContracts(ctx, *whitelistContractParams)
+				if err != nil {
+					return err
+				}
 			}
 		}
-	}
-	for _, method := range update.GatewaySettings.WhitelistMethods {
-		whitelistMethodParams := extractUpsertWhitelistMethods(id, &method)
-		if whitelistMethodParams != nil {
-			err = qtx.UpsertWhitelistMethods(ctx, *whitelistMethodParams)
-			if err != nil {
-				return err
+		for _, method := range update.GatewaySettings.WhitelistMethods {
+			whitelistMethodParams := extractUpsertWhitelistMethods(id, &method)
+			if whitelistMethodParams != nil {
+				err = qtx.UpsertWhitelistMethods(ctx, *whitelistMethodParams)
+				if err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -355,6 +460,7 @@ func extractUpsertApplication(id string, update *types.UpdateApplication) Upsert
 		Status:             newSQLNullString(string(update.Status)),
 		FirstDateSurpassed: newSQLNullTime(update.FirstDateSurpassed),
 		UpdatedAt:          newSQLNullTime(time.Now()),
+		ExpectedVersion:    int32(update.ExpectedVersion),
 	}
 }
 
@@ -384,7 +490,6 @@ func extractUpsertGatewaySettings(id string, update *types.UpdateApplication) *U
 
 	return &UpsertGatewaySettingsParams{
 		ApplicationID:        id,
-		SecretKey:            newSQLNullString(update.GatewaySettings.SecretKey),
 		SecretKeyRequired:    newSQLNullBool(update.GatewaySettings.SecretKeyRequired),
 		WhitelistOrigins:     update.GatewaySettings.WhitelistOrigins,
 		WhitelistUserAgents:  update.GatewaySettings.WhitelistUserAgents,
@@ -392,7 +497,7 @@ func extractUpsertGatewaySettings(id string, update *types.UpdateApplication) *U
 	}
 }
 func (u *UpsertGatewaySettingsParams) isNotNull() bool {
-	return u != nil && (u.SecretKey.Valid || u.SecretKeyRequired.Valid ||
+	return u != nil && (u.SecretKeyRequired.Valid ||
 		len(u.WhitelistOrigins) != 0 || len(u.WhitelistUserAgents) != 0 || len(u.WhitelistBlockchains) != 0)
 }
 
@@ -438,14 +543,33 @@ func (u *UpsertNotificationSettingsParams) isNotNull() bool {
 	return u != nil && (u.SignedUp.Valid || u.OnQuarter.Valid || u.OnHalf.Valid || u.OnThreeQuarters.Valid || u.OnFull.Valid)
 }
 
-/* UpdateAppFirstDateSurpassed updates Application's firstDateSurpassed field */
-func (p *PostgresDriver) UpdateAppFirstDateSurpassed(ctx context.Context, update *types.UpdateFirstDateSurpassed) error {
+/*
+UpdateAppFirstDateSurpassed updates Application's firstDateSurpassed field, nothing is updated if any of the IDs does not exist.
+IncrementUsage and FlushUsage already set it the first time an Application goes over its DailyLimit.
+*/
+func (p *PostgresDriver) UpdateAppFirstDateSurpassed(ctx context.Context, update *types.UpdateFirstDateSurpassed) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	params := UpdateFirstDateSurpassedParams{
 		ApplicationIds:     update.ApplicationIDs,
 		FirstDateSurpassed: newSQLNullTime(update.FirstDateSurpassed),
 	}
 
-	err := p.UpdateFirstDateSurpassed(ctx, params)
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	updatedRows, err := p.WithTx(tx.Tx).UpdateFirstDateSurpassed(ctx, params)
+	if err != nil {
+		return err
+	}
+	if updatedRows < int64(countUnique(update.ApplicationIDs)) {
+		return types.ErrNotFound
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
@@ -453,8 +577,15 @@ func (p *PostgresDriver) UpdateAppFirstDateSurpassed(ctx context.Context, update
 	return nil
 }
 
-/* RemoveApplication updates Application's status field to AwaitingGracePeriod */
-func (p *PostgresDriver) RemoveApplication(ctx context.Context, id string) error {
+/*
+	RemoveApplication updates Application's status field to AwaitingGracePeriod.
+
+It returns types.ErrNotFound if the Application does not exist and ErrInvalidStatusTransition if it cannot be removed
+from its current status, unless ctx has a status override
+*/
+func (p *PostgresDriver) RemoveApplication(ctx context.Context, id string) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	if id == "" {
 		return ErrMissingID
 	}
@@ -464,7 +595,29 @@ func (p *PostgresDriver) RemoveApplication(ctx context.Context, id string) error
 		Status:        newSQLNullString(string(types.AwaitingGracePeriod)),
 	}
 
-	err := p.RemoveApp(ctx, params)
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	currentStatus, err := qtx.SelectApplicationStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = checkStatusTransition(ctx, types.AppStatus(currentStatus.String), types.AwaitingGracePeriod)
+	if err != nil {
+		return err
+	}
+
+	_, err = qtx.RemoveApp(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
@@ -472,6 +625,24 @@ func (p *PostgresDriver) RemoveApplication(ctx context.Context, id string) error
 	return nil
 }
 
+/* checkStatusTransition returns ErrInvalidStatusTransition if an Application cannot move to status, contexts returned by types.WithStatusOverride skip the check */
+func checkStatusTransition(ctx context.Context, currentStatus, status types.AppStatus) error {
+	if types.StatusOverrideFromContext(ctx) {
+		return nil
+	}
+
+	return types.ValidateStatusTransition(currentStatus, status)
+}
+
+func countUnique(values []string) int {
+	unique := make(map[string]bool, len(values))
+	for _, value := range values {
+		unique[value] = true
+	}
+
+	return len(unique)
+}
+
 /* Used by Listener */
 type (
 	dbAppJSON struct {
@@ -487,6 +658,7 @@ type (
 		UpdatedAt          string `json:"updated_at"`
 		FirstDateSurpassed string `json:"first_date_surpassed"`
 		Dummy              bool   `json:"dummy"`
+		Version            int    `json:"version"`
 	}
 	dbAppLimitJSON struct {
 		ApplicationID string            `json:"application_id"`
@@ -544,6 +716,7 @@ func (j dbAppJSON) toOutput() *types.Application {
 		UpdatedAt:          psqlDateToTime(j.UpdatedAt),
 		FirstDateSurpassed: psqlDateToTime(j.FirstDateSurpassed),
 		Dummy:              j.Dummy,
+		Version:            j.Version,
 	}
 }
 func (j dbAppLimitJSON) toOutput() *types.AppLimit {
@@ -1,6 +1,7 @@
 package postgresdriver
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -75,6 +76,35 @@ func (ts *PGDriverTestSuite) Test_ReadApplications() {
 						Full:          true,
 					},
 				},
+				{
+					ID:     "test_app_9thr3sh0ld5kq2",
+					UserID: "test_user_thresholds9876",
+					Name:   "vipr_app_thresholds",
+					URL:    "https://test.app789.io",
+					Dummy:  true,
+					Status: types.InService,
+					GatewayAAT: types.GatewayAAT{
+						Address:              "test_0d3a9e1f6b7c4d5e8f9a0b1c2d3e4f5a",
+						ApplicationPublicKey: "test_7e6d5c4b3a2918f7e6d5c4b3a2918f7e",
+						ApplicationSignature: "test_4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e",
+						ClientPublicKey:      "test_a1b2c3d4e5f60718293a4b5c6d7e8f90",
+						PrivateKey:           "test_9f8e7d6c5b4a39281706f5e4d3c2b1a0",
+					},
+					GatewaySettings: types.GatewaySettings{
+						SecretKey:         "test_5c1e8a2b7d4f9e3a6b0c1d2e3f4",
+						SecretKeyRequired: false,
+					},
+					Limit: types.AppLimit{
+						PayPlan: types.PayPlan{Type: types.FreetierV0, Limit: 250_000},
+					},
+					NotificationSettings: types.NotificationSettings{
+						SignedUp:      true,
+						Quarter:       true,
+						Half:          false,
+						ThreeQuarters: true,
+						Full:          true,
+					},
+				},
 			},
 			err: nil,
 		},
@@ -151,7 +181,6 @@ func (ts *PGDriverTestSuite) Test_WriteApplication() {
 						PrivateKey:           "test_f403700aed7e039c0a8fc2dd22da6fd9",
 					},
 					GatewaySettings: types.GatewaySettings{
-						SecretKey:         "test_489574398f34uhf4uhjf9328jf23f98j",
 						SecretKeyRequired: true,
 					},
 					Limit: types.AppLimit{
@@ -166,7 +195,7 @@ func (ts *PGDriverTestSuite) Test_WriteApplication() {
 					},
 				},
 			},
-			expectedNumOfApps: 3,
+			expectedNumOfApps: 4,
 			expectedApp: SelectOneApplicationRow{
 				Name:              sql.NullString{Valid: true, String: "vipr_app_789"},
 				UserID:            sql.NullString{Valid: true, String: "test_user_47fhsd75jd756sh"},
@@ -177,7 +206,6 @@ func (ts *PGDriverTestSuite) Test_WriteApplication() {
 				GaPrivateKey:      sql.NullString{Valid: true, String: "test_f403700aed7e039c0a8fc2dd22da6fd9"},
 				GaPublicKey:       sql.NullString{Valid: true, String: "test_b95c35affacf6df4a5585388490542f0"},
 				GaSignature:       sql.NullString{Valid: true, String: "test_e59760339d9ce02972d1080d73446c90"},
-				SecretKey:         sql.NullString{Valid: true, String: "test_489574398f34uhf4uhjf9328jf23f98j"},
 				SecretKeyRequired: sql.NullBool{Valid: true, Bool: true},
 				SignedUp:          sql.NullBool{Valid: true, Bool: true},
 				OnQuarter:         sql.NullBool{Valid: true, Bool: false},
@@ -220,17 +248,31 @@ func (ts *PGDriverTestSuite) Test_WriteApplication() {
 			},
 			err: types.ErrNotEnterprisePlan,
 		},
+		{
+			name: "Should fail if passing a secret key",
+			appInputs: []*types.Application{
+				{
+					Status: types.InService,
+					GatewaySettings: types.GatewaySettings{
+						SecretKey: "test_489574398f34uhf4uhjf9328jf23f98j",
+					},
+				},
+			},
+			err: types.ErrSecretKeyIsGenerated,
+		},
 	}
 
 	for _, test := range tests {
 		for _, input := range test.appInputs {
 			createdApp, err := ts.driver.WriteApplication(testCtx, input)
-			ts.Equal(test.err, err)
+			ts.ErrorIs(err, test.err)
 			if err == nil {
 				ts.Len(createdApp.ID, 24)
 				ts.Equal(input.Name, createdApp.Name)
 				ts.NotEmpty(createdApp.CreatedAt)
 				ts.NotEmpty(createdApp.UpdatedAt)
+				ts.Len(createdApp.GatewaySettings.SecretKey, secretKeyLength)
+				ts.Len(createdApp.GatewaySettings.SecretKeys, 1)
 
 				apps, err := ts.driver.ReadApplications(testCtx)
 				ts.Equal(test.err, err)
@@ -248,7 +290,8 @@ func (ts *PGDriverTestSuite) Test_WriteApplication() {
 						ts.Equal(test.expectedApp.GaPrivateKey, app.GaPrivateKey)
 						ts.Equal(test.expectedApp.GaPublicKey, app.GaPublicKey)
 						ts.Equal(test.expectedApp.GaSignature, app.GaSignature)
-						ts.Equal(test.expectedApp.SecretKey, app.SecretKey)
+						ts.True(types.IsHashedSecretKey(app.SecretKey.String))
+						ts.True(types.VerifySecretKey(app.SecretKey.String, createdApp.GatewaySettings.SecretKey))
 						ts.Equal(test.expectedApp.SecretKeyRequired, app.SecretKeyRequired)
 						ts.Equal(test.expectedApp.SignedUp, app.SignedUp)
 						ts.Equal(test.expectedApp.OnQuarter, app.OnQuarter)
@@ -280,18 +323,18 @@ func (ts *PGDriverTestSuite) Test_UpdateApplication() {
 			appUpdate: &types.UpdateApplication{
 				Name: "vipr_app_updated_lb",
 				GatewaySettings: &types.UpdateGatewaySettings{
-					WhitelistOrigins:    []string{"test-origin1", "test-origin2"},
+					WhitelistOrigins:    []string{"https://test-origin1.com", "https://*.test-origin2.com"},
 					WhitelistUserAgents: []string{"test-agent1"},
 					WhitelistContracts: []types.WhitelistContract{
 						{
-							BlockchainID: "01",
-							Contracts:    []string{"test-contract1"},
+							BlockchainID: "0021",
+							Contracts:    []string{"0xdAC17F958D2ee523a2206206994597C13D831ec7"},
 						},
 					},
 					WhitelistMethods: []types.WhitelistMethod{
 						{
-							BlockchainID: "01",
-							Methods:      []string{"test-method1"},
+							BlockchainID: "0021",
+							Methods:      []string{"eth_getBalance"},
 						},
 					},
 					WhitelistBlockchains: []string{"test-chain1"},
@@ -313,9 +356,9 @@ func (ts *PGDriverTestSuite) Test_UpdateApplication() {
 			expectedAfterUpdate: SelectOneApplicationRow{
 				Name:                 sql.NullString{Valid: true, String: "vipr_app_updated_lb"},
 				WhitelistBlockchains: []string{"test-chain1"},
-				WhitelistContracts:   "[{\"blockchain_id\" : \"01\", \"contracts\" : [\"test-contract1\"]}]",
-				WhitelistMethods:     "[{\"blockchain_id\" : \"01\", \"methods\" : [\"test-method1\"]}]",
-				WhitelistOrigins:     []string{"test-origin1", "test-origin2"},
+				WhitelistContracts:   "[{\"blockchain_id\" : \"0021\", \"contracts\" : [\"0xdAC17F958D2ee523a2206206994597C13D831ec7\"]}]",
+				WhitelistMethods:     "[{\"blockchain_id\" : \"0021\", \"methods\" : [\"eth_getBalance\"]}]",
+				WhitelistOrigins:     []string{"https://test-origin1.com", "https://*.test-origin2.com"},
 				WhitelistUserAgents:  []string{"test-agent1"},
 				SignedUp:             sql.NullBool{Valid: true, Bool: false},
 				OnQuarter:            sql.NullBool{Valid: true, Bool: true},
@@ -324,6 +367,7 @@ func (ts *PGDriverTestSuite) Test_UpdateApplication() {
 				OnFull:               sql.NullBool{Valid: true, Bool: false},
 				CustomLimit:          sql.NullInt32{Valid: true, Int32: 4_200_000},
 				PayPlan:              sql.NullString{Valid: true, String: "ENTERPRISE"},
+				Version:              2,
 			},
 			err: nil,
 		},
@@ -332,7 +376,7 @@ func (ts *PGDriverTestSuite) Test_UpdateApplication() {
 			appID: "test_app_5hdf7sh23jd828",
 			appUpdate: &types.UpdateApplication{
 				GatewaySettings: &types.UpdateGatewaySettings{
-					WhitelistOrigins:    []string{"test-origin1", "test-origin2"},
+					WhitelistOrigins:    []string{"https://test-origin1.com", "https://*.test-origin2.com"},
 					WhitelistUserAgents: []string{"test-agent1"},
 				},
 				NotificationSettings: &types.UpdateNotificationSettings{
@@ -345,7 +389,7 @@ func (ts *PGDriverTestSuite) Test_UpdateApplication() {
 			expectedAfterUpdate: SelectOneApplicationRow{
 				Name:                 sql.NullString{Valid: true, String: "vipr_app_456"},
 				WhitelistBlockchains: []string(nil),
-				WhitelistOrigins:     []string{"test-origin1", "test-origin2"},
+				WhitelistOrigins:     []string{"https://test-origin1.com", "https://*.test-origin2.com"},
 				WhitelistUserAgents:  []string{"test-agent1"},
 				SignedUp:             sql.NullBool{Valid: true, Bool: true},
 				OnQuarter:            sql.NullBool{Valid: true, Bool: false},
@@ -354,9 +398,45 @@ func (ts *PGDriverTestSuite) Test_UpdateApplication() {
 				OnFull:               sql.NullBool{Valid: true, Bool: false},
 				CustomLimit:          sql.NullInt32{Valid: true, Int32: 0},
 				PayPlan:              sql.NullString{Valid: true, String: "PAY_AS_YOU_GO_V0"},
+				Version:              2,
 			},
 			err: nil,
 		},
+		{
+			name:  "Should update a single application successfully if the expected version matches",
+			appID: "test_app_5hdf7sh23jd828",
+			appUpdate: &types.UpdateApplication{
+				Name:            "vipr_app_456_versioned",
+				ExpectedVersion: 2,
+			},
+			expectedAfterUpdate: SelectOneApplicationRow{
+				Name:                 sql.NullString{Valid: true, String: "vipr_app_456_versioned"},
+				WhitelistBlockchains: []string(nil),
+				WhitelistOrigins:     []string{"https://test-origin1.com", "https://*.test-origin2.com"},
+				WhitelistUserAgents:  []string{"test-agent1"},
+				SignedUp:             sql.NullBool{Valid: true, Bool: true},
+				OnQuarter:            sql.NullBool{Valid: true, Bool: false},
+				OnHalf:               sql.NullBool{Valid: true, Bool: false},
+				OnThreeQuarters:      sql.NullBool{Valid: true, Bool: true},
+				OnFull:               sql.NullBool{Valid: true, Bool: false},
+				CustomLimit:          sql.NullInt32{Valid: true, Int32: 0},
+				PayPlan:              sql.NullString{Valid: true, String: "PAY_AS_YOU_GO_V0"},
+				Version:              3,
+			},
+			err: nil,
+		},
+		{
+			name:  "Should fail with a conflict and leave the application unchanged if the expected version is stale",
+			appID: "test_app_5hdf7sh23jd828",
+			appUpdate: &types.UpdateApplication{
+				Name: "vipr_app_456_stale",
+				Limit: &types.AppLimit{
+					PayPlan: types.PayPlan{Type: types.FreetierV0},
+				},
+				ExpectedVersion: 2,
+			},
+			err: types.ErrConflict,
+		},
 		{
 			name:  "Should fail if passing an invalid status",
 			appID: "test_app_5hdf7sh23jd828",
@@ -397,6 +477,31 @@ func (ts *PGDriverTestSuite) Test_UpdateApplication() {
 			},
 			err: types.ErrEnterprisePlanNeedsCustomLimit,
 		},
+		{
+			name:  "Should fail when whitelisting an origin without a scheme",
+			appID: "test_app_5hdf7sh23jd828",
+			appUpdate: &types.UpdateApplication{
+				GatewaySettings: &types.UpdateGatewaySettings{
+					WhitelistOrigins: []string{"test-origin1.com"},
+				},
+			},
+			err: types.ErrInvalidWhitelistOrigin,
+		},
+		{
+			name:  "Should fail when whitelisting methods of a blockchain that does not exist",
+			appID: "test_app_5hdf7sh23jd828",
+			appUpdate: &types.UpdateApplication{
+				GatewaySettings: &types.UpdateGatewaySettings{
+					WhitelistMethods: []types.WhitelistMethod{
+						{
+							BlockchainID: "9999",
+							Methods:      []string{"eth_getBalance"},
+						},
+					},
+				},
+			},
+			err: types.ErrUnknownBlockchain,
+		},
 	}
 
 	for _, test := range tests {
@@ -404,7 +509,14 @@ func (ts *PGDriverTestSuite) Test_UpdateApplication() {
 		ts.NoError(err)
 
 		err = ts.driver.UpdateApplication(testCtx, test.appID, test.appUpdate)
-		ts.Equal(test.err, err)
+		ts.ErrorIs(err, test.err)
+		if err == types.ErrConflict {
+			appAfterConflict, err := ts.driver.SelectOneApplication(testCtx, test.appID)
+			ts.NoError(err)
+			ts.Equal("vipr_app_456_versioned", appAfterConflict.Name.String)
+			ts.Equal("PAY_AS_YOU_GO_V0", appAfterConflict.PayPlan.String)
+			ts.Equal(int32(3), appAfterConflict.Version)
+		}
 		if err == nil {
 			appAfterUpdate, err := ts.driver.SelectOneApplication(testCtx, test.appID)
 			ts.NoError(err)
@@ -421,7 +533,89 @@ func (ts *PGDriverTestSuite) Test_UpdateApplication() {
 			ts.Equal(test.expectedAfterUpdate.OnFull, appAfterUpdate.OnFull)
 			ts.Equal(test.expectedAfterUpdate.CustomLimit, appAfterUpdate.CustomLimit)
 			ts.Equal(test.expectedAfterUpdate.PayPlan, appAfterUpdate.PayPlan)
+			ts.Equal(test.expectedAfterUpdate.Version, appAfterUpdate.Version)
+		}
+	}
+}
+
+func (ts *PGDriverTestSuite) Test_UpdateApplicationStatus() {
+	appID := "test_app_5hdf7sh23jd828"
+	adminCtx := types.WithStatusOverride(testCtx)
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		status         types.AppStatus
+		remove         bool
+		expectedStatus string
+		err            error
+	}{
+		{
+			name:           "Should fail to move an app in service back to awaiting funds",
+			ctx:            testCtx,
+			status:         types.AwaitingFunds,
+			expectedStatus: "IN_SERVICE",
+			err:            types.ErrInvalidStatusTransition,
+		},
+		{
+			name:           "Should move an app in service to swappable",
+			ctx:            testCtx,
+			status:         types.Swappable,
+			expectedStatus: "SWAPPABLE",
+		},
+		{
+			name:           "Should remove a swappable app",
+			ctx:            testCtx,
+			remove:         true,
+			expectedStatus: "AWAITING_GRACE_PERIOD",
+		},
+		{
+			name:           "Should fail to decomission an app before it is unstaked",
+			ctx:            testCtx,
+			status:         types.Decomissioned,
+			expectedStatus: "AWAITING_GRACE_PERIOD",
+			err:            types.ErrInvalidStatusTransition,
+		},
+		{
+			name:           "Should decomission an app before it is unstaked with the override",
+			ctx:            adminCtx,
+			status:         types.Decomissioned,
+			expectedStatus: "DECOMISSIONED",
+		},
+		{
+			name:           "Should fail to remove a decomissioned app",
+			ctx:            testCtx,
+			remove:         true,
+			expectedStatus: "DECOMISSIONED",
+			err:            types.ErrInvalidStatusTransition,
+		},
+		{
+			name:           "Should fail to resurrect a decomissioned app",
+			ctx:            testCtx,
+			status:         types.InService,
+			expectedStatus: "DECOMISSIONED",
+			err:            types.ErrInvalidStatusTransition,
+		},
+		{
+			name:           "Should resurrect a decomissioned app with the override",
+			ctx:            adminCtx,
+			status:         types.InService,
+			expectedStatus: "IN_SERVICE",
+		},
+	}
+
+	for _, test := range tests {
+		var err error
+		if test.remove {
+			err = ts.driver.RemoveApplication(test.ctx, appID)
+		} else {
+			err = ts.driver.UpdateApplication(test.ctx, appID, &types.UpdateApplication{Status: test.status})
 		}
+		ts.ErrorIs(err, test.err, test.name)
+
+		app, err := ts.driver.SelectOneApplication(testCtx, appID)
+		ts.NoError(err)
+		ts.Equal(test.expectedStatus, app.Status.String, test.name)
 	}
 }
 
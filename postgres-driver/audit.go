@@ -0,0 +1,73 @@
+package postgresdriver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+var (
+	ErrInvalidAuditDiffJSON = errors.New("error: audit log diff JSON is invalid")
+)
+
+/* ReadAuditLog returns the audit log entries matching the filter, newest first */
+func (p *PostgresDriver) ReadAuditLog(ctx context.Context, filter types.AuditLogFilter) (_ []*types.AuditLogEntry, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	dbEntries, err := p.SelectAuditLog(readCtx, SelectAuditLogParams{
+		EntityType: string(filter.EntityType),
+		EntityID:   filter.EntityID,
+		Actor:      filter.ActorID,
+		MaxRows:    int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*types.AuditLogEntry
+	for _, dbEntry := range dbEntries {
+		entry, err := dbEntry.toAuditLogEntry()
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (a *AuditLog) toAuditLogEntry() (*types.AuditLogEntry, error) {
+	entry := types.AuditLogEntry{
+		ID:         int(a.ID),
+		EntityType: types.Table(a.EntityType),
+		EntityID:   a.EntityID,
+		Action:     types.Action(a.Action),
+		ActorID:    a.Actor.String,
+		CreatedAt:  a.CreatedAt,
+	}
+
+	err := json.Unmarshal(a.Diff, &entry.Diff)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAuditDiffJSON, err)
+	}
+
+	return &entry, nil
+}
+
+/* setLocalActor records the actor for the audit_event trigger until the transaction ends */
+func setLocalActor(ctx context.Context, tx *sql.Tx, actor string) error {
+	if actor == "" {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, "SELECT set_config('portal.actor', $1, true)", actor)
+
+	return err
+}
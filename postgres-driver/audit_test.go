@@ -0,0 +1,72 @@
+package postgresdriver
+
+import (
+	"encoding/json"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func (ts *PGDriverTestSuite) Test_ReadAuditLog() {
+	actorCtx := types.WithActor(testCtx, "test_user_auditor1234")
+
+	err := ts.driver.ActivateChain(actorCtx, "0021", false)
+	ts.NoError(err)
+	err = ts.driver.ActivateChain(actorCtx, "0021", true)
+	ts.NoError(err)
+
+	tests := []struct {
+		name            string
+		filter          types.AuditLogFilter
+		expectedEntries int
+		expectedActive  []json.RawMessage
+	}{
+		{
+			name:            "Should return the entries for an actor newest first",
+			filter:          types.AuditLogFilter{ActorID: "test_user_auditor1234"},
+			expectedEntries: 2,
+			expectedActive:  []json.RawMessage{json.RawMessage("true"), json.RawMessage("false")},
+		},
+		{
+			name: "Should return the entries for an entity and actor",
+			filter: types.AuditLogFilter{
+				EntityType: types.TableBlockchains,
+				EntityID:   "0021",
+				ActorID:    "test_user_auditor1234",
+			},
+			expectedEntries: 2,
+			expectedActive:  []json.RawMessage{json.RawMessage("true"), json.RawMessage("false")},
+		},
+		{
+			name:            "Should limit the number of entries returned",
+			filter:          types.AuditLogFilter{ActorID: "test_user_auditor1234", Limit: 1},
+			expectedEntries: 1,
+			expectedActive:  []json.RawMessage{json.RawMessage("true")},
+		},
+		{
+			name:            "Should return no entries for an entity the actor did not change",
+			filter:          types.AuditLogFilter{EntityType: types.TableBlockchains, EntityID: "0001", ActorID: "test_user_auditor1234"},
+			expectedEntries: 0,
+		},
+	}
+
+	for _, test := range tests {
+		entries, err := ts.driver.ReadAuditLog(testCtx, test.filter)
+		ts.NoError(err)
+		ts.Len(entries, test.expectedEntries)
+
+		for i, entry := range entries {
+			ts.Equal(types.TableBlockchains, entry.EntityType)
+			ts.Equal("0021", entry.EntityID)
+			ts.Equal(types.ActionUpdate, entry.Action)
+			ts.Equal("test_user_auditor1234", entry.ActorID)
+			ts.NotEmpty(entry.CreatedAt)
+			ts.Equal(test.expectedActive[i], entry.Diff["active"].After)
+			ts.Contains(entry.Diff, "updated_at")
+		}
+	}
+
+	entries, err := ts.driver.ReadAuditLog(testCtx, types.AuditLogFilter{EntityType: types.TableBlockchains, EntityID: "0001", Limit: 1})
+	ts.NoError(err)
+	ts.Len(entries, 1)
+	ts.Empty(entries[0].ActorID)
+}
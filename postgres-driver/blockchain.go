@@ -15,8 +15,12 @@ var (
 )
 
 /* ReadBlockchains returns all blockchains in the database and marshals to types struct */
-func (p *PostgresDriver) ReadBlockchains(ctx context.Context) ([]*types.Blockchain, error) {
-	dbBlockchains, err := p.SelectBlockchains(ctx)
+func (p *PostgresDriver) ReadBlockchains(ctx context.Context) (_ []*types.Blockchain, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	dbBlockchains, err := p.SelectBlockchains(readCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -73,18 +77,25 @@ func (b *SelectBlockchainsRow) toBlockchain() (*types.Blockchain, error) {
 }
 
 /* WriteBlockchain saves input Blockchain struct to the database */
-func (p *PostgresDriver) WriteBlockchain(ctx context.Context, blockchain *types.Blockchain) (*types.Blockchain, error) {
+func (p *PostgresDriver) WriteBlockchain(ctx context.Context, blockchain *types.Blockchain) (_ *types.Blockchain, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	blockchainIsInvalid := blockchain.Validate()
+	if blockchainIsInvalid != nil {
+		return nil, blockchainIsInvalid
+	}
+
 	time := time.Now()
 	blockchain.CreatedAt = time
 	blockchain.UpdatedAt = time
 
-	tx, err := p.db.Begin()
+	tx, err := p.beginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	qtx := p.WithTx(tx)
+	qtx := p.WithTx(tx.Tx)
 
 	err = qtx.InsertBlockchain(ctx, extractInsertDBBlockchain(blockchain))
 	if err != nil {
@@ -148,12 +159,30 @@ func (i *InsertSyncCheckOptionsParams) isNotNull() bool {
 
 It must be called separately from WriteBlockchain due to how new chains are added to the dB
 */
-func (p *PostgresDriver) WriteRedirect(ctx context.Context, redirect *types.Redirect) (*types.Redirect, error) {
+func (p *PostgresDriver) WriteRedirect(ctx context.Context, redirect *types.Redirect) (_ *types.Redirect, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	redirectIsInvalid := redirect.Validate()
+	if redirectIsInvalid != nil {
+		return nil, redirectIsInvalid
+	}
+
 	time := time.Now()
 	redirect.CreatedAt = time
 	redirect.UpdatedAt = time
 
-	err := p.InsertRedirect(ctx, extractInsertDBRedirect(redirect))
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = p.WithTx(tx.Tx).InsertRedirect(ctx, extractInsertDBRedirect(redirect))
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
@@ -172,15 +201,31 @@ func extractInsertDBRedirect(redirect *types.Redirect) InsertRedirectParams {
 	}
 }
 
-/* Activate chain toggles chain.active field on or off */
-func (p *PostgresDriver) ActivateChain(ctx context.Context, id string, active bool) error {
+/* Activate chain toggles chain.active field on or off, it returns types.ErrNotFound if the chain does not exist */
+func (p *PostgresDriver) ActivateChain(ctx context.Context, id string, active bool) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	params := ActivateBlockchainParams{
 		BlockchainID: id,
 		Active:       newSQLNullBool(&active),
 		UpdatedAt:    newSQLNullTime(time.Now()),
 	}
 
-	err := p.ActivateBlockchain(ctx, params)
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	updatedRows, err := p.WithTx(tx.Tx).ActivateBlockchain(ctx, params)
+	if err != nil {
+		return err
+	}
+	if updatedRows == 0 {
+		return types.ErrNotFound
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,207 @@
+package postgresdriver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/vishruthsk/portal-db-main/encryption"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+var (
+	ErrNoKeyProvider = errors.New("error: no key provider is set")
+)
+
+/*
+SetKeyProvider enables envelope encryption of GatewayAAT private keys.
+Values are encrypted on write and decrypted on read and in notifications; it must be called before the driver is used.
+Secret keys are hashed instead, those encrypted before hashing was introduced are still decrypted until HashSecretKeys is run.
+*/
+func (d *PostgresDriver) SetKeyProvider(provider encryption.KeyProvider) {
+	d.envelope = encryption.NewEnvelope(provider)
+}
+
+func (p *PostgresDriver) encryptSecret(ctx context.Context, value sql.NullString) (sql.NullString, error) {
+	if p.envelope == nil || !value.Valid {
+		return value, nil
+	}
+
+	encrypted, err := p.envelope.Encrypt(ctx, value.String)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return newSQLNullString(encrypted), nil
+}
+
+func (p *PostgresDriver) decryptSecret(ctx context.Context, value string) (string, error) {
+	if p.envelope == nil {
+		return value, nil
+	}
+
+	return p.envelope.Decrypt(ctx, value)
+}
+
+func (p *PostgresDriver) decryptApplication(ctx context.Context, app *types.Application) error {
+	privateKey, err := p.decryptSecret(ctx, app.GatewayAAT.PrivateKey)
+	if err != nil {
+		return err
+	}
+	app.GatewayAAT.PrivateKey = privateKey
+
+	secretKey, err := p.decryptSecret(ctx, app.GatewaySettings.SecretKey)
+	if err != nil {
+		return err
+	}
+	app.GatewaySettings.SecretKey = secretKey
+
+	for i, key := range app.GatewaySettings.SecretKeys {
+		app.GatewaySettings.SecretKeys[i].Key, err = p.decryptSecret(ctx, key.Key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* decryptNotification decrypts secrets in notification payloads, clearing any that cannot be decrypted */
+func (p *PostgresDriver) decryptNotification(n *types.Notification) {
+	if n == nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	switch data := n.Data.(type) {
+	case *types.GatewayAAT:
+		privateKey, err := p.decryptSecret(ctx, data.PrivateKey)
+		if err != nil {
+			privateKey = ""
+		}
+		data.PrivateKey = privateKey
+	case *types.GatewaySettings:
+		secretKey, err := p.decryptSecret(ctx, data.SecretKey)
+		if err != nil {
+			secretKey = ""
+		}
+		data.SecretKey = secretKey
+	case *types.SecretKey:
+		key, err := p.decryptSecret(ctx, data.Key)
+		if err != nil {
+			key = ""
+		}
+		data.Key = key
+	}
+}
+
+/*
+ReencryptSecrets encrypts every private key, and every secret key that is not hashed yet, that is stored in clear text
+or under an old key with the current key of the KeyProvider, returning how many values were rewritten
+*/
+func (p *PostgresDriver) ReencryptSecrets(ctx context.Context) (_ int64, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if p.envelope == nil {
+		return 0, ErrNoKeyProvider
+	}
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	var rewritten int64
+
+	privateKeys, err := qtx.SelectGatewayAATPrivateKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range privateKeys {
+		if !p.envelope.NeedsRotation(row.PrivateKey.String) {
+			continue
+		}
+
+		privateKey, err := p.reencryptSecret(ctx, row.PrivateKey.String)
+		if err != nil {
+			return 0, err
+		}
+
+		err = qtx.UpdateGatewayAATPrivateKey(ctx, UpdateGatewayAATPrivateKeyParams{
+			ApplicationID: row.ApplicationID,
+			PrivateKey:    privateKey,
+		})
+		if err != nil {
+			return 0, err
+		}
+		rewritten++
+	}
+
+	secretKeys, err := qtx.SelectGatewaySettingsSecretKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range secretKeys {
+		if types.IsHashedSecretKey(row.SecretKey.String) || !p.envelope.NeedsRotation(row.SecretKey.String) {
+			continue
+		}
+
+		secretKey, err := p.reencryptSecret(ctx, row.SecretKey.String)
+		if err != nil {
+			return 0, err
+		}
+
+		err = qtx.UpdateGatewaySettingsSecretKey(ctx, UpdateGatewaySettingsSecretKeyParams{
+			ApplicationID: row.ApplicationID,
+			SecretKey:     secretKey,
+		})
+		if err != nil {
+			return 0, err
+		}
+		rewritten++
+	}
+
+	namedKeys, err := qtx.SelectSecretKeySecrets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range namedKeys {
+		if types.IsHashedSecretKey(row.SecretKey) || !p.envelope.NeedsRotation(row.SecretKey) {
+			continue
+		}
+
+		secretKey, err := p.reencryptSecret(ctx, row.SecretKey)
+		if err != nil {
+			return 0, err
+		}
+
+		err = qtx.UpdateSecretKeySecret(ctx, UpdateSecretKeySecretParams{
+			ID:        row.ID,
+			SecretKey: secretKey.String,
+		})
+		if err != nil {
+			return 0, err
+		}
+		rewritten++
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return rewritten, nil
+}
+
+func (p *PostgresDriver) reencryptSecret(ctx context.Context, value string) (sql.NullString, error) {
+	plaintext, err := p.envelope.Decrypt(ctx, value)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return p.encryptSecret(ctx, newSQLNullString(plaintext))
+}
@@ -0,0 +1,89 @@
+package postgresdriver
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/encryption"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func (ts *PGDriverTestSuite) Test_WriteEncryptedApplication() {
+	keys := map[string][]byte{
+		"key-1": bytes.Repeat([]byte{1}, 32),
+		"key-2": bytes.Repeat([]byte{2}, 32),
+	}
+	oldProvider, err := encryption.NewLocalKeyProvider("key-1", keys)
+	ts.NoError(err)
+	newProvider, err := encryption.NewLocalKeyProvider("key-2", keys)
+	ts.NoError(err)
+
+	encryptingDriver := NewPostgresDriverFromDBInstance(ts.driver.db, NewListenerMock())
+	encryptingDriver.SetKeyProvider(oldProvider)
+
+	createdApp, err := encryptingDriver.WriteApplication(testCtx, &types.Application{
+		Name:   "vipr_app_encrypted",
+		UserID: "test_user_encrypted1234",
+		Status: types.InService,
+		GatewayAAT: types.GatewayAAT{
+			Address:              "test_encrypted_address",
+			ApplicationPublicKey: "test_encrypted_public_key",
+			ApplicationSignature: "test_encrypted_signature",
+			ClientPublicKey:      "test_encrypted_client_public_key",
+			PrivateKey:           "test_encrypted_private_key",
+		},
+		GatewaySettings: types.GatewaySettings{
+			SecretKeyRequired: true,
+		},
+		Limit: types.AppLimit{
+			PayPlan: types.PayPlan{Type: types.FreetierV0},
+		},
+	})
+	ts.NoError(err)
+	ts.Equal("test_encrypted_private_key", createdApp.GatewayAAT.PrivateKey)
+
+	storedApp, err := ts.driver.SelectOneApplication(testCtx, createdApp.ID)
+	ts.NoError(err)
+	ts.True(encryption.IsEncrypted(storedApp.GaPrivateKey.String))
+	// Secret keys are hashed rather than encrypted
+	ts.True(types.IsHashedSecretKey(storedApp.SecretKey.String))
+	hashedSecretKey := storedApp.SecretKey.String
+
+	assertDecrypted := func(driver *PostgresDriver) {
+		apps, err := driver.ReadApplications(testCtx)
+		ts.NoError(err)
+		for _, app := range apps {
+			ts.False(encryption.IsEncrypted(app.GatewayAAT.PrivateKey))
+			ts.False(encryption.IsEncrypted(app.GatewaySettings.SecretKey))
+			if app.ID == createdApp.ID {
+				ts.Equal("test_encrypted_private_key", app.GatewayAAT.PrivateKey)
+				ts.True(app.GatewaySettings.VerifySecretKey(createdApp.GatewaySettings.SecretKey, time.Now()))
+			}
+		}
+	}
+	assertDecrypted(encryptingDriver)
+
+	// Encrypts the seeded clear text secrets under the old key
+	rewritten, err := encryptingDriver.ReencryptSecrets(testCtx)
+	ts.NoError(err)
+	ts.Positive(rewritten)
+
+	rewritten, err = encryptingDriver.ReencryptSecrets(testCtx)
+	ts.NoError(err)
+	ts.Zero(rewritten)
+
+	// Rotates every secret to the new key
+	encryptingDriver.SetKeyProvider(newProvider)
+	rewritten, err = encryptingDriver.ReencryptSecrets(testCtx)
+	ts.NoError(err)
+	ts.Positive(rewritten)
+
+	storedApp, err = ts.driver.SelectOneApplication(testCtx, createdApp.ID)
+	ts.NoError(err)
+	ts.Contains(storedApp.GaPrivateKey.String, "enc:v1:key-2:")
+	ts.Equal(hashedSecretKey, storedApp.SecretKey.String)
+	assertDecrypted(encryptingDriver)
+
+	_, err = ts.driver.ReencryptSecrets(testCtx)
+	ts.Equal(ErrNoKeyProvider, err)
+}
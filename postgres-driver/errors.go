@@ -0,0 +1,53 @@
+package postgresdriver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+	pqQueryCanceled       = "57014"
+	pqLockNotAvailable    = "55P03"
+)
+
+/*
+translateError converts the errors returned by Postgres into the errors exported by the types package:
+timeouts into a *types.TimeoutError, unique and foreign key violations into a *types.ConstraintError
+and missing rows into types.ErrNotFound
+*/
+func translateError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == pqLockNotAvailable:
+			return &types.TimeoutError{Kind: types.TimeoutLock, Err: err}
+		case pqErr.Code == pqQueryCanceled && strings.Contains(pqErr.Message, "statement timeout"):
+			return &types.TimeoutError{Kind: types.TimeoutStatement, Err: err}
+		case pqErr.Code == pqUniqueViolation:
+			return &types.ConstraintError{Kind: types.ErrAlreadyExists, Constraint: pqErr.Constraint, Err: err}
+		case pqErr.Code == pqForeignKeyViolation:
+			return &types.ConstraintError{Kind: types.ErrReferenceViolation, Constraint: pqErr.Constraint, Err: err}
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &types.TimeoutError{Kind: types.TimeoutContext, Err: err}
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.ErrNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,118 @@
+package postgresdriver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func TestTranslateConstraintError(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedErr        error
+		expectedConstraint string
+	}{
+		{
+			name:               "Should translate a unique violation",
+			err:                &pq.Error{Code: pqUniqueViolation, Constraint: "secret_keys_application_id_name_key"},
+			expectedErr:        types.ErrAlreadyExists,
+			expectedConstraint: "secret_keys_application_id_name_key",
+		},
+		{
+			name:               "Should translate a foreign key violation",
+			err:                &pq.Error{Code: pqForeignKeyViolation, Constraint: "fk_lb"},
+			expectedErr:        types.ErrReferenceViolation,
+			expectedConstraint: "fk_lb",
+		},
+		{
+			name:        "Should translate a missing row",
+			err:         sql.ErrNoRows,
+			expectedErr: types.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			err := translateError(context.Background(), test.err)
+			c.ErrorIs(err, test.expectedErr)
+
+			var constraintErr *types.ConstraintError
+			if errors.As(err, &constraintErr) {
+				c.Equal(test.expectedConstraint, constraintErr.Constraint)
+				c.ErrorIs(err, test.err)
+				c.False(errors.Is(err, types.ErrNotFound))
+			} else {
+				c.Empty(test.expectedConstraint)
+			}
+		})
+	}
+}
+
+func (ts *PGDriverTestSuite) Test_ConstraintErrors() {
+	_, err := ts.driver.WriteRedirect(testCtx, &types.Redirect{
+		BlockchainID:   "0001",
+		Alias:          "test-mainnet",
+		Domain:         "test-rpc1.testnet.vipr.network",
+		LoadBalancerID: "test_lb_34gg4g43g34g5hh",
+	})
+	ts.ErrorIs(err, types.ErrAlreadyExists)
+	var constraintErr *types.ConstraintError
+	ts.True(errors.As(err, &constraintErr))
+	ts.Equal("redirects_blockchain_id_domain_key", constraintErr.Constraint)
+
+	_, err = ts.driver.WriteRedirect(testCtx, &types.Redirect{
+		BlockchainID:   "9999",
+		Alias:          "test-mainnet",
+		Domain:         "test-rpc9.testnet.vipr.network",
+		LoadBalancerID: "test_lb_34gg4g43g34g5hh",
+	})
+	ts.ErrorIs(err, types.ErrReferenceViolation)
+	ts.True(errors.As(err, &constraintErr))
+	ts.Equal("fk_blockchain", constraintErr.Constraint)
+}
+
+func (ts *PGDriverTestSuite) Test_NotFoundErrors() {
+	unknownID := "test_unknown_id_4hf83h2"
+
+	err := ts.driver.ActivateChain(testCtx, unknownID, true)
+	ts.Equal(types.ErrNotFound, err)
+
+	err = ts.driver.UpdateApplication(testCtx, unknownID, &types.UpdateApplication{Name: "vipr_app_unknown"})
+	ts.Equal(types.ErrNotFound, err)
+
+	err = ts.driver.RemoveApplication(testCtx, unknownID)
+	ts.Equal(types.ErrNotFound, err)
+
+	err = ts.driver.UpdateAppFirstDateSurpassed(testCtx, &types.UpdateFirstDateSurpassed{
+		ApplicationIDs:     []string{"test_app_47hfnths73j2se", unknownID},
+		FirstDateSurpassed: time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC),
+	})
+	ts.Equal(types.ErrNotFound, err)
+	app, err := ts.driver.SelectOneApplication(testCtx, "test_app_47hfnths73j2se")
+	ts.NoError(err)
+	ts.NotEqual(time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC), app.FirstDateSurpassed.Time.UTC())
+
+	err = ts.driver.UpdateLoadBalancer(testCtx, unknownID, &types.UpdateLoadBalancer{Name: "vipr_lb_unknown"})
+	ts.Equal(types.ErrNotFound, err)
+
+	err = ts.driver.UpdateLoadBalancer(testCtx, unknownID, &types.UpdateLoadBalancer{Name: "vipr_lb_unknown", ExpectedVersion: 1})
+	ts.Equal(types.ErrNotFound, err)
+
+	err = ts.driver.RemoveLoadBalancer(testCtx, unknownID)
+	ts.Equal(types.ErrNotFound, err)
+
+	err = ts.driver.UpdateUserAccessRole(testCtx, unknownID, "test_lb_3890ru23jfi32fj", types.RoleMember)
+	ts.Equal(types.ErrNotFound, err)
+
+	err = ts.driver.RemoveUserAccess(testCtx, unknownID, "test_lb_3890ru23jfi32fj")
+	ts.Equal(types.ErrNotFound, err)
+}
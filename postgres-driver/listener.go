@@ -42,6 +42,18 @@ func (n notification) parseStickinessOptionsNotification() *types.Notification {
 	}
 }
 
+func (n notification) parseLBLimitNotification() *types.Notification {
+	rawData, _ := json.Marshal(n.Data)
+	var dbLBLimit dbLBLimitJSON
+	_ = json.Unmarshal(rawData, &dbLBLimit)
+
+	return &types.Notification{
+		Table:  n.Table,
+		Action: n.Action,
+		Data:   dbLBLimit.toOutput(),
+	}
+}
+
 func (n notification) parseUserAccessNotification() *types.Notification {
 	rawData, _ := json.Marshal(n.Data)
 	var dbUserAccess dbUserAccessJSON
@@ -114,6 +126,18 @@ func (n notification) parseGatewaySettingsNotification() *types.Notification {
 	}
 }
 
+func (n notification) parseSecretKeyNotification() *types.Notification {
+	rawData, _ := json.Marshal(n.Data)
+	var dbSecretKey dbSecretKeyJSON
+	_ = json.Unmarshal(rawData, &dbSecretKey)
+
+	return &types.Notification{
+		Table:  n.Table,
+		Action: n.Action,
+		Data:   dbSecretKey.toOutput(),
+	}
+}
+
 func (n notification) parseWhitelistContractNotification() *types.Notification {
 	rawData, _ := json.Marshal(n.Data)
 	var dbWhitelistContract dbWhitelistContractJSON
@@ -194,6 +218,8 @@ func (n notification) parseNotification() *types.Notification {
 		return n.parseStickinessOptionsNotification()
 	case types.TableUserAccess:
 		return n.parseUserAccessNotification()
+	case types.TableLbLimits:
+		return n.parseLBLimitNotification()
 
 	case types.TableLbApps:
 		return n.parseLbApps()
@@ -206,6 +232,8 @@ func (n notification) parseNotification() *types.Notification {
 		return n.parseGatewayAATNotification()
 	case types.TableGatewaySettings:
 		return n.parseGatewaySettingsNotification()
+	case types.TableSecretKeys:
+		return n.parseSecretKeyNotification()
 	case types.TableWhitelistContracts:
 		return n.parseWhitelistContractNotification()
 	case types.TableWhitelistMethods:
@@ -224,18 +252,27 @@ func (n notification) parseNotification() *types.Notification {
 	return nil
 }
 
-func parsePQNotification(n *pq.Notification, outCh chan *types.Notification) {
+func parsePQNotification(n *pq.Notification, outCh chan *types.Notification, decrypt func(*types.Notification)) {
 	if n != nil {
 		var notification notification
 		_ = json.Unmarshal([]byte(n.Extra), &notification)
-		outCh <- notification.parseNotification()
+		parsed := notification.parseNotification()
+		if decrypt != nil {
+			decrypt(parsed)
+		}
+		outCh <- parsed
 	}
 }
 
 func Listen(inCh <-chan *pq.Notification, outCh chan *types.Notification) {
+	listen(inCh, outCh, nil)
+}
+
+/* listen is Listen with a hook the driver uses to decrypt secrets before notifications are sent on */
+func listen(inCh <-chan *pq.Notification, outCh chan *types.Notification, decrypt func(*types.Notification)) {
 	for {
 		n := <-inCh
-		go parsePQNotification(n, outCh)
+		go parsePQNotification(n, outCh, decrypt)
 	}
 }
 
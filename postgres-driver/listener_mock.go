@@ -27,6 +27,7 @@ func (l *ListenerMock) Listen(channel string) error {
 
 func gatewaySettingsIsNull(settings types.GatewaySettings) bool {
 	return settings.SecretKey == "" &&
+		len(settings.SecretKeys) == 0 &&
 		len(settings.WhitelistOrigins) == 0 &&
 		len(settings.WhitelistUserAgents) == 0 &&
 		len(settings.WhitelistContracts) == 0 &&
@@ -55,6 +56,7 @@ func applicationInputs(mainTableAction, sideTablesAction types.Action, content t
 			UpdatedAt:          app.UpdatedAt.Format(psqlDateLayout),
 			FirstDateSurpassed: app.FirstDateSurpassed.Format(psqlDateLayout),
 			Dummy:              app.Dummy,
+			Version:            app.Version,
 		},
 	})
 
@@ -97,6 +99,20 @@ func applicationInputs(mainTableAction, sideTablesAction types.Action, content t
 				WhitelistBlockchains: app.GatewaySettings.WhitelistBlockchains,
 			},
 		})
+		for _, key := range app.GatewaySettings.SecretKeys {
+			inputs = append(inputs, inputStruct{
+				action: sideTablesAction,
+				table:  types.TableSecretKeys,
+				input: dbSecretKeyJSON{
+					ApplicationID: app.ID,
+					Name:          key.Name,
+					SecretKey:     key.Key,
+					CreatedAt:     key.CreatedAt.Format(psqlDateLayout),
+					ExpiresAt:     key.ExpiresAt.Format(psqlDateLayout),
+					RevokedAt:     key.RevokedAt.Format(psqlDateLayout),
+				},
+			})
+		}
 		for _, contract := range app.GatewaySettings.WhitelistContracts {
 			inputs = append(inputs, inputStruct{
 				action: sideTablesAction,
@@ -217,6 +233,7 @@ func loadBalancerInputs(mainTableAction, sideTablesAction types.Action, content
 			RequestTimeout:    lb.RequestTimeout,
 			Gigastake:         lb.Gigastake,
 			GigastakeRedirect: lb.GigastakeRedirect,
+			Version:           lb.Version,
 			CreatedAt:         lb.CreatedAt.Format(psqlDateLayout),
 			UpdatedAt:         lb.UpdatedAt.Format(psqlDateLayout),
 		},
@@ -236,6 +253,19 @@ func loadBalancerInputs(mainTableAction, sideTablesAction types.Action, content
 		})
 	}
 
+	if lb.Limit != nil {
+		inputs = append(inputs, inputStruct{
+			action: sideTablesAction,
+			table:  types.TableLbLimits,
+			input: dbLBLimitJSON{
+				LbID:        lb.ID,
+				PlanType:    lb.Limit.PayPlan.Type,
+				CustomLimit: lb.Limit.CustomLimit,
+				DailyLimit:  lb.Limit.PayPlan.Limit,
+			},
+		})
+	}
+
 	if len(lb.Users) != 0 {
 		for _, user := range lb.Users {
 			inputs = append(inputs, inputStruct{
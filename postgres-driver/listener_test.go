@@ -23,7 +23,10 @@ func TestListen(t *testing.T) {
 					Address: "123",
 				},
 				GatewaySettings: types.GatewaySettings{
-					SecretKey:            "123",
+					SecretKey: "123",
+					SecretKeys: []types.SecretKey{
+						{Name: "default", Key: "123", CreatedAt: time.Date(2022, 11, 11, 11, 11, 11, 0, time.UTC)},
+					},
 					WhitelistBlockchains: []string{"test-chain-1", "test-chain-2"},
 					WhitelistContracts: []types.WhitelistContract{
 						{BlockchainID: "001", Contracts: []string{"test123", "test456"}},
@@ -74,6 +77,16 @@ func TestListen(t *testing.T) {
 						WhitelistBlockchains: []string{"test-chain-1", "test-chain-2"},
 					},
 				},
+				types.TableSecretKeys: {
+					Table:  types.TableSecretKeys,
+					Action: types.ActionUpdate,
+					Data: &types.SecretKey{
+						ID:        "321",
+						Name:      "default",
+						Key:       "123",
+						CreatedAt: time.Date(2022, 11, 11, 11, 11, 11, 0, time.UTC),
+					},
+				},
 				types.TableWhitelistContracts: {
 					Table:  types.TableWhitelistContracts,
 					Action: types.ActionUpdate,
@@ -133,6 +146,9 @@ func TestListen(t *testing.T) {
 					StickyOrigins: []string{"oahu"},
 					Stickiness:    true,
 				},
+				Limit: &types.LBLimit{
+					PayPlan: types.PayPlan{Type: types.FreetierV0, Limit: 250000},
+				},
 				ApplicationIDs: []string{"a123"},
 				Users: []types.UserAccess{
 					{RoleName: "ADMIN", UserID: "test_user_admin1234", Email: "admin1@test.com", Accepted: true},
@@ -155,6 +171,14 @@ func TestListen(t *testing.T) {
 						Stickiness:    true,
 					},
 				},
+				types.TableLbLimits: {
+					Table:  types.TableLbLimits,
+					Action: types.ActionUpdate,
+					Data: &types.LBLimit{
+						ID:      "123",
+						PayPlan: types.PayPlan{Type: types.FreetierV0, Limit: 250000},
+					},
+				},
 				types.TableUserAccess: {
 					Table:  types.TableUserAccess,
 					Action: types.ActionUpdate,
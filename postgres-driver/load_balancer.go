@@ -2,6 +2,7 @@ package postgresdriver
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,14 +14,24 @@ import (
 
 var (
 	ErrInvalidUsersJSON        = errors.New("error: users JSON is invalid")
-	ErrUserInputIsMissingField = errors.New("error: user access input is missing a required field")
-	ErrLBMustHaveUser          = errors.New("error: a new load balancer must have at least one user")
-	ErrCannotSetToOwner        = errors.New("error: load balancers may only have one owner and the owner role is already set")
+	ErrUserInputIsMissingField = types.ErrUserInputIsMissingField
+	ErrLBMustHaveUser          = types.ErrLBMustHaveUser
+	ErrCannotSetToOwner        = types.ErrCannotSetToOwner
+	ErrUserIsNotOwner          = types.ErrUserIsNotOwner
+	ErrNewOwnerNotAccepted     = types.ErrNewOwnerNotAccepted
+	ErrSameOwner               = types.ErrSameOwner
+	ErrInvitationNotFound      = types.ErrInvitationNotFound
+	ErrInvitationExpired       = types.ErrInvitationExpired
+	ErrInvitationUserMismatch  = types.ErrInvitationUserMismatch
 )
 
 /* ReadLoadBalancers returns all LoadBalancers in the database */
-func (p *PostgresDriver) ReadLoadBalancers(ctx context.Context) ([]*types.LoadBalancer, error) {
-	dbLoadBalancers, err := p.SelectLoadBalancers(ctx)
+func (p *PostgresDriver) ReadLoadBalancers(ctx context.Context) (_ []*types.LoadBalancer, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	dbLoadBalancers, err := p.SelectLoadBalancers(readCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +58,7 @@ func (lb *SelectLoadBalancersRow) toLoadBalancer() (*types.LoadBalancer, error)
 		RequestTimeout:    int(lb.RequestTimeout.Int32),
 		Gigastake:         lb.Gigastake.Bool,
 		GigastakeRedirect: lb.GigastakeRedirect.Bool,
+		Version:           int(lb.Version),
 
 		StickyOptions: types.StickyOptions{
 			Duration:      lb.SDuration.String,
@@ -59,6 +71,16 @@ func (lb *SelectLoadBalancersRow) toLoadBalancer() (*types.LoadBalancer, error)
 		UpdatedAt: lb.UpdatedAt.Time,
 	}
 
+	if lb.LPayPlan.Valid {
+		loadBalancer.Limit = &types.LBLimit{
+			PayPlan: types.PayPlan{
+				Type:  types.PayPlanType(lb.LPayPlan.String),
+				Limit: int(lb.LPlanLimit.Int32),
+			},
+			CustomLimit: int(lb.LCustomLimit.Int32),
+		}
+	}
+
 	// Unmarshal LoadBalancer Users JSON into []types.UserAccess
 	err := json.Unmarshal(lb.Users, &loadBalancer.Users)
 	if err != nil {
@@ -69,8 +91,12 @@ func (lb *SelectLoadBalancersRow) toLoadBalancer() (*types.LoadBalancer, error)
 }
 
 /* ReadUserRoles returns all User Roles in the database as a map that takes the form map[User ID]map[LB ID][]types.PermissionsEnum */
-func (p *PostgresDriver) ReadUserRoles(ctx context.Context) (map[string]map[string][]types.PermissionsEnum, error) {
-	userRoles, err := p.SelectUserRoles(ctx)
+func (p *PostgresDriver) ReadUserRoles(ctx context.Context) (_ map[string]map[string][]types.PermissionsEnum, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	userRoles, err := p.SelectUserRoles(readCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -92,11 +118,18 @@ func (p *PostgresDriver) ReadUserRoles(ctx context.Context) (map[string]map[stri
 }
 
 /* WriteLoadBalancer saves input LoadBalancer to the database */
-func (p *PostgresDriver) WriteLoadBalancer(ctx context.Context, loadBalancer *types.LoadBalancer) (*types.LoadBalancer, error) {
+func (p *PostgresDriver) WriteLoadBalancer(ctx context.Context, loadBalancer *types.LoadBalancer) (_ *types.LoadBalancer, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	if len(loadBalancer.Users) < 1 {
 		return nil, ErrLBMustHaveUser
 	}
 
+	loadBalancerIsInvalid := loadBalancer.Validate()
+	if loadBalancerIsInvalid != nil {
+		return nil, loadBalancerIsInvalid
+	}
+
 	id, err := generateRandomID()
 	if err != nil {
 		return nil, err
@@ -106,13 +139,13 @@ func (p *PostgresDriver) WriteLoadBalancer(ctx context.Context, loadBalancer *ty
 	loadBalancer.CreatedAt = time
 	loadBalancer.UpdatedAt = time
 
-	tx, err := p.db.Begin()
+	tx, err := p.beginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	qtx := p.WithTx(tx)
+	qtx := p.WithTx(tx.Tx)
 
 	err = qtx.InsertLoadBalancer(ctx, extractInsertLoadBalancer(loadBalancer))
 	if err != nil {
@@ -127,6 +160,13 @@ func (p *PostgresDriver) WriteLoadBalancer(ctx context.Context, loadBalancer *ty
 		}
 	}
 
+	if loadBalancer.Limit != nil {
+		err = qtx.UpsertLBLimit(ctx, extractUpsertLBLimit(id, loadBalancer.Limit))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	loadBalancer.Users[0].RoleName = types.RoleOwner // The first User will be the initial creater (owner) of the LoadBalancer
 	accepted := true                                 // New LB owners always start with accepted = true
 	userAccessParams := extractInsertUserAccess(id, loadBalancer.Users[0], &accepted, time)
@@ -179,6 +219,14 @@ func (i *InsertStickinessOptionsParams) isNotNull() bool {
 	return i.Duration.Valid || len(i.Origins) > 0 || i.StickyMax.Valid
 }
 
+func extractUpsertLBLimit(lbID string, limit *types.LBLimit) UpsertLBLimitParams {
+	return UpsertLBLimitParams{
+		LbID:        lbID,
+		PayPlan:     string(limit.PayPlan.Type),
+		CustomLimit: newSQLNullInt32(int32(limit.CustomLimit), false),
+	}
+}
+
 func extractInsertUserAccess(lbID string, userAccess types.UserAccess, accepted *bool, createdAt time.Time) InsertUserAccessParams {
 	return InsertUserAccessParams{
 		LbID:      newSQLNullString(lbID),
@@ -207,7 +255,9 @@ func (i *InsertUserAccessParams) checkForMissingField() string {
 }
 
 /* WriteLoadBalancerUser saves input LoadBalancer to the database */
-func (p *PostgresDriver) WriteLoadBalancerUser(ctx context.Context, lbID string, userAccess types.UserAccess) error {
+func (p *PostgresDriver) WriteLoadBalancerUser(ctx context.Context, lbID string, userAccess types.UserAccess) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	if lbID == "" {
 		return ErrMissingID
 	}
@@ -223,7 +273,18 @@ func (p *PostgresDriver) WriteLoadBalancerUser(ctx context.Context, lbID string,
 		return fmt.Errorf("%w: %s", ErrUserInputIsMissingField, missingField)
 	}
 
-	err := p.InsertUserAccess(ctx, userAccessParams)
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = p.WithTx(tx.Tx).InsertUserAccess(ctx, userAccessParams)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
@@ -231,24 +292,250 @@ func (p *PostgresDriver) WriteLoadBalancerUser(ctx context.Context, lbID string,
 	return nil
 }
 
-/* UpdateLoadBalancer updates LoadBalancer and related table rows */
-func (p *PostgresDriver) UpdateLoadBalancer(ctx context.Context, id string, update *types.UpdateLoadBalancer) error {
+/*
+	WriteLoadBalancerInvitation saves a pending UserAccess row with an invitation token to the database.
+
+The UserID may be left empty for users who have not yet signed up; it is then set when the invitation is accepted
+*/
+func (p *PostgresDriver) WriteLoadBalancerInvitation(ctx context.Context, lbID string, userAccess types.UserAccess) (_ *types.Invitation, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if lbID == "" {
+		return nil, ErrMissingID
+	}
+	if userAccess.RoleName == types.RoleOwner {
+		return nil, ErrCannotSetToOwner
+	}
+	if userAccess.RoleName == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUserInputIsMissingField, "RoleName")
+	}
+	if userAccess.Email == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUserInputIsMissingField, "Email")
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &types.Invitation{
+		LbID:      lbID,
+		UserID:    userAccess.UserID,
+		RoleName:  userAccess.RoleName,
+		Email:     userAccess.Email,
+		Token:     token,
+		ExpiresAt: now.Add(invitationTTL),
+		CreatedAt: now,
+	}
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = p.WithTx(tx.Tx).InsertInvitation(ctx, extractInsertInvitation(invitation))
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func extractInsertInvitation(invitation *types.Invitation) InsertInvitationParams {
+	return InsertInvitationParams{
+		LbID:            newSQLNullString(invitation.LbID),
+		RoleName:        newSQLNullString(string(invitation.RoleName)),
+		UserID:          newSQLNullString(invitation.UserID),
+		Email:           newSQLNullString(invitation.Email),
+		Accepted:        newSQLNullBool(boolPointer(false)),
+		InviteToken:     newSQLNullString(invitation.Token),
+		InviteExpiresAt: newSQLNullTime(invitation.ExpiresAt),
+		CreatedAt:       newSQLNullTime(invitation.CreatedAt),
+		UpdatedAt:       newSQLNullTime(invitation.CreatedAt),
+	}
+}
+
+/* ReadPendingInvitations returns all unexpired invitations that have not been accepted for an email */
+func (p *PostgresDriver) ReadPendingInvitations(ctx context.Context, email string) (_ []*types.Invitation, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	dbInvitations, err := p.SelectPendingInvitations(readCtx, SelectPendingInvitationsParams{
+		Email: newSQLNullString(email),
+		Now:   newSQLNullTime(time.Now()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var invitations []*types.Invitation
+	for _, dbInvitation := range dbInvitations {
+		invitations = append(invitations, dbInvitation.toInvitation())
+	}
+
+	return invitations, nil
+}
+
+func (i *SelectPendingInvitationsRow) toInvitation() *types.Invitation {
+	return &types.Invitation{
+		LbID:      i.LbID.String,
+		UserID:    i.UserID.String,
+		RoleName:  types.RoleName(i.RoleName.String),
+		Email:     i.Email.String,
+		Token:     i.InviteToken.String,
+		ExpiresAt: i.InviteExpiresAt.Time,
+		CreatedAt: i.CreatedAt.Time,
+	}
+}
+
+/* AcceptInvitation marks the invitation as accepted and sets the UserID for invitations sent only to an email */
+func (p *PostgresDriver) AcceptInvitation(ctx context.Context, token, userID string) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if token == "" || userID == "" {
+		return ErrMissingID
+	}
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	invitation, err := qtx.SelectInvitation(ctx, newSQLNullString(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvitationNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if invitation.toInvitation().IsExpired(time.Now()) {
+		return ErrInvitationExpired
+	}
+	if invitation.UserID.Valid && invitation.UserID.String != userID {
+		return ErrInvitationUserMismatch
+	}
+
+	err = qtx.AcceptInvite(ctx, AcceptInviteParams{
+		InviteToken: newSQLNullString(token),
+		UserID:      newSQLNullString(userID),
+		UpdatedAt:   newSQLNullTime(time.Now()),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (i *SelectInvitationRow) toInvitation() *types.Invitation {
+	return &types.Invitation{
+		LbID:      i.LbID.String,
+		UserID:    i.UserID.String,
+		RoleName:  types.RoleName(i.RoleName.String),
+		Email:     i.Email.String,
+		Token:     i.InviteToken.String,
+		ExpiresAt: i.InviteExpiresAt.Time,
+		CreatedAt: i.CreatedAt.Time,
+	}
+}
+
+/* DeclineInvitation deletes a pending invitation */
+func (p *PostgresDriver) DeclineInvitation(ctx context.Context, token string) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if token == "" {
+		return ErrMissingID
+	}
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	deleted, err := p.WithTx(tx.Tx).DeleteInvite(ctx, newSQLNullString(token))
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrInvitationNotFound
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/* RemoveExpiredInvitations deletes all pending invitations past their expiry and returns how many were removed */
+func (p *PostgresDriver) RemoveExpiredInvitations(ctx context.Context) (_ int64, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	removed, err := p.WithTx(tx.Tx).DeleteExpiredInvites(ctx, newSQLNullTime(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+/* UpdateLoadBalancer updates LoadBalancer and related table rows, it returns types.ErrNotFound if the LoadBalancer does not exist */
+func (p *PostgresDriver) UpdateLoadBalancer(ctx context.Context, id string, update *types.UpdateLoadBalancer) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	if id == "" {
 		return ErrMissingID
 	}
 
-	tx, err := p.db.Begin()
+	invalidUpdate := update.Validate()
+	if invalidUpdate != nil {
+		return invalidUpdate
+	}
+
+	tx, err := p.beginTx(ctx)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	qtx := p.WithTx(tx)
+	qtx := p.WithTx(tx.Tx)
 
-	err = qtx.UpdateLB(ctx, extractUpsertLoadBalancer(id, update))
+	updatedRows, err := qtx.UpdateLB(ctx, extractUpsertLoadBalancer(id, update))
 	if err != nil {
 		return err
 	}
+	if updatedRows == 0 {
+		return notFoundOrConflict(ctx, qtx, id)
+	}
 
 	stickinessOptionsParams := extractUpsertStickinessOptions(id, update)
 	if stickinessOptionsParams.isNotNull() {
@@ -258,6 +545,19 @@ func (p *PostgresDriver) UpdateLoadBalancer(ctx context.Context, id string, upda
 		}
 	}
 
+	if update.Limit != nil {
+		err = qtx.UpsertLBLimit(ctx, extractUpsertLBLimit(id, update.Limit))
+		if err != nil {
+			return err
+		}
+	}
+	if update.RemoveLimit {
+		_, err = qtx.DeleteLBLimit(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return err
@@ -268,9 +568,10 @@ func (p *PostgresDriver) UpdateLoadBalancer(ctx context.Context, id string, upda
 
 func extractUpsertLoadBalancer(id string, update *types.UpdateLoadBalancer) UpdateLBParams {
 	return UpdateLBParams{
-		LbID:      id,
-		Name:      newSQLNullString(update.Name),
-		UpdatedAt: newSQLNullTime(time.Now()),
+		LbID:            id,
+		Name:            newSQLNullString(update.Name),
+		UpdatedAt:       newSQLNullTime(time.Now()),
+		ExpectedVersion: int32(update.ExpectedVersion),
 	}
 }
 
@@ -293,21 +594,150 @@ func (u *UpsertStickinessOptionsParams) isNotNull() bool {
 
 /* UpdateUserAccessRole updates the RoleName for a UserAccess row */
 func (p *PostgresDriver) UpdateUserAccessRole(ctx context.Context, userID, lbID string, roleName types.RoleName) error {
-	if userID == "" || lbID == "" {
+	return p.UpdateLoadBalancerUser(ctx, lbID, &types.UpdateUserAccess{UserID: userID, RoleName: roleName})
+}
+
+/*
+	UpdateLoadBalancerUser updates the RoleName for a UserAccess row.
+
+If ExpectedVersion is set the update only succeeds if it matches the version of the load balancer
+*/
+func (p *PostgresDriver) UpdateLoadBalancerUser(ctx context.Context, lbID string, update *types.UpdateUserAccess) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if update.UserID == "" || lbID == "" {
 		return ErrMissingID
 	}
-	if roleName == types.RoleOwner {
+	if update.RoleName == types.RoleOwner {
 		return ErrCannotSetToOwner
 	}
 
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	updatedRows, err := qtx.IncrementLBVersion(ctx, IncrementLBVersionParams{
+		LbID:            lbID,
+		ExpectedVersion: int32(update.ExpectedVersion),
+	})
+	if err != nil {
+		return err
+	}
+	if updatedRows == 0 {
+		return notFoundOrConflict(ctx, qtx, lbID)
+	}
+
 	params := UpdateUserAccessParams{
-		UserID:    newSQLNullString(userID),
+		UserID:    newSQLNullString(update.UserID),
 		LbID:      newSQLNullString(lbID),
-		RoleName:  newSQLNullString(string(roleName)),
+		RoleName:  newSQLNullString(string(update.RoleName)),
 		UpdatedAt: newSQLNullTime(time.Now()),
 	}
 
-	err := p.UpdateUserAccess(ctx, params)
+	updatedRows, err = qtx.UpdateUserAccess(ctx, params)
+	if err != nil {
+		return err
+	}
+	if updatedRows == 0 {
+		return types.ErrNotFound
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+	TransferLoadBalancerOwnership moves the owner role of a LoadBalancer from one user to another.
+
+The previous owner is demoted to admin, the new owner (who must have accepted their invite) is promoted
+and loadbalancers.user_id is updated, all in a single transaction
+*/
+func (p *PostgresDriver) TransferLoadBalancerOwnership(ctx context.Context, lbID, fromUserID, toUserID string) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if lbID == "" || fromUserID == "" || toUserID == "" {
+		return ErrMissingID
+	}
+	if fromUserID == toUserID {
+		return ErrSameOwner
+	}
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	currentOwner, err := qtx.SelectUserAccess(ctx, SelectUserAccessParams{
+		UserID: newSQLNullString(fromUserID),
+		LbID:   newSQLNullString(lbID),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserIsNotOwner
+	}
+	if err != nil {
+		return err
+	}
+	if currentOwner.RoleName.String != string(types.RoleOwner) {
+		return ErrUserIsNotOwner
+	}
+
+	newOwner, err := qtx.SelectUserAccess(ctx, SelectUserAccessParams{
+		UserID: newSQLNullString(toUserID),
+		LbID:   newSQLNullString(lbID),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNewOwnerNotAccepted
+	}
+	if err != nil {
+		return err
+	}
+	if !newOwner.Accepted.Bool {
+		return ErrNewOwnerNotAccepted
+	}
+
+	updatedAt := newSQLNullTime(time.Now())
+
+	_, err = qtx.UpdateUserAccess(ctx, UpdateUserAccessParams{
+		UserID:    newSQLNullString(fromUserID),
+		LbID:      newSQLNullString(lbID),
+		RoleName:  newSQLNullString(string(types.RoleAdmin)),
+		UpdatedAt: updatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = qtx.UpdateUserAccess(ctx, UpdateUserAccessParams{
+		UserID:    newSQLNullString(toUserID),
+		LbID:      newSQLNullString(lbID),
+		RoleName:  newSQLNullString(string(types.RoleOwner)),
+		UpdatedAt: updatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = qtx.UpdateLBUserID(ctx, UpdateLBUserIDParams{
+		LbID:      lbID,
+		UserID:    newSQLNullString(toUserID),
+		UpdatedAt: updatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
@@ -315,13 +745,29 @@ func (p *PostgresDriver) UpdateUserAccessRole(ctx context.Context, userID, lbID
 	return nil
 }
 
-/* RemoveLoadBalancer sets the user ID to an empty string (will not appear in Portal API or UI) */
-func (p *PostgresDriver) RemoveLoadBalancer(ctx context.Context, id string) error {
+/* RemoveLoadBalancer sets the user ID to an empty string (will not appear in Portal API or UI), it returns types.ErrNotFound if the LoadBalancer does not exist */
+func (p *PostgresDriver) RemoveLoadBalancer(ctx context.Context, id string) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	if id == "" {
 		return ErrMissingID
 	}
 
-	err := p.RemoveLB(ctx, RemoveLBParams{LbID: id, UpdatedAt: newSQLNullTime(time.Now())})
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	removedRows, err := p.WithTx(tx.Tx).RemoveLB(ctx, RemoveLBParams{LbID: id, UpdatedAt: newSQLNullTime(time.Now())})
+	if err != nil {
+		return err
+	}
+	if removedRows == 0 {
+		return types.ErrNotFound
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
@@ -329,8 +775,10 @@ func (p *PostgresDriver) RemoveLoadBalancer(ctx context.Context, id string) erro
 	return nil
 }
 
-/* RemoveUserAccess deletes a UserAccess row */
-func (p *PostgresDriver) RemoveUserAccess(ctx context.Context, userID, lbID string) error {
+/* RemoveUserAccess deletes a UserAccess row, it returns types.ErrNotFound if the user has no access to the LoadBalancer */
+func (p *PostgresDriver) RemoveUserAccess(ctx context.Context, userID, lbID string) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
 	if userID == "" || lbID == "" {
 		return ErrMissingID
 	}
@@ -340,7 +788,21 @@ func (p *PostgresDriver) RemoveUserAccess(ctx context.Context, userID, lbID stri
 		LbID:   newSQLNullString(lbID),
 	}
 
-	err := p.DeleteUserAccess(ctx, params)
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	deletedRows, err := p.WithTx(tx.Tx).DeleteUserAccess(ctx, params)
+	if err != nil {
+		return err
+	}
+	if deletedRows == 0 {
+		return types.ErrNotFound
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
@@ -348,6 +810,19 @@ func (p *PostgresDriver) RemoveUserAccess(ctx context.Context, userID, lbID stri
 	return nil
 }
 
+/* notFoundOrConflict tells apart the two reasons a versioned LoadBalancer update can match no rows */
+func notFoundOrConflict(ctx context.Context, q *Queries, id string) error {
+	exists, err := q.LoadBalancerExists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrNotFound
+	}
+
+	return types.ErrConflict
+}
+
 /* Used by Listener */
 type (
 	dbLoadBalancerJSON struct {
@@ -357,6 +832,7 @@ type (
 		RequestTimeout    int    `json:"request_timeout"`
 		Gigastake         bool   `json:"gigastake"`
 		GigastakeRedirect bool   `json:"gigastake_redirect"`
+		Version           int    `json:"version"`
 		CreatedAt         string `json:"created_at"`
 		UpdatedAt         string `json:"updated_at"`
 	}
@@ -367,6 +843,12 @@ type (
 		StickyMax  int      `json:"sticky_max"`
 		Stickiness bool     `json:"stickiness"`
 	}
+	dbLBLimitJSON struct {
+		LbID        string            `json:"lb_id"`
+		PlanType    types.PayPlanType `json:"pay_plan"`
+		CustomLimit int               `json:"custom_limit"`
+		DailyLimit  int               `json:"daily_limit"`
+	}
 	dbUserAccessJSON struct {
 		LbID     string `json:"lb_id"`
 		UserID   string `json:"user_id"`
@@ -384,6 +866,7 @@ func (j dbLoadBalancerJSON) toOutput() *types.LoadBalancer {
 		RequestTimeout:    j.RequestTimeout,
 		Gigastake:         j.Gigastake,
 		GigastakeRedirect: j.GigastakeRedirect,
+		Version:           j.Version,
 		CreatedAt:         psqlDateToTime(j.CreatedAt),
 		UpdatedAt:         psqlDateToTime(j.UpdatedAt),
 	}
@@ -397,6 +880,16 @@ func (j dbStickinessOptionsJSON) toOutput() *types.StickyOptions {
 		Stickiness:    j.Stickiness,
 	}
 }
+func (j dbLBLimitJSON) toOutput() *types.LBLimit {
+	return &types.LBLimit{
+		ID: j.LbID,
+		PayPlan: types.PayPlan{
+			Type:  j.PlanType,
+			Limit: j.DailyLimit,
+		},
+		CustomLimit: j.CustomLimit,
+	}
+}
 func (j dbUserAccessJSON) toOutput() *types.UserAccess {
 	return &types.UserAccess{
 		ID:       j.LbID,
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/vishruthsk/portal-db-main/types"
 )
@@ -75,6 +76,22 @@ func (ts *PGDriverTestSuite) Test_ReadLoadBalancers() {
 						{RoleName: "ADMIN", UserID: "test_user_admin5678", Email: "admin2@test.com", Accepted: true},
 					},
 				},
+				{
+					ID:                "test_lb_9l1m1t3dk2f8s4",
+					Name:              "test_lb_limit",
+					UserID:            "test_user_limit48fj39dk",
+					ApplicationIDs:    []string{""},
+					RequestTimeout:    5_000,
+					Gigastake:         false,
+					GigastakeRedirect: false,
+					StickyOptions: types.StickyOptions{
+						Duration:      "10",
+						StickyOrigins: []string{"test-extension://"},
+						StickyMax:     100,
+						Stickiness:    false,
+					},
+					Users: []types.UserAccess{},
+				},
 			},
 			err: nil,
 		},
@@ -193,7 +210,7 @@ func (ts *PGDriverTestSuite) Test_WriteLoadBalancer() {
 					},
 				},
 			},
-			expectedNumOfLBs: 4,
+			expectedNumOfLBs: 5,
 			expectedLB: SelectOneLoadBalancerRow{
 				Name:              sql.NullString{Valid: true, String: "vipr_app_789"},
 				UserID:            sql.NullString{Valid: true, String: "test_user_47fhsd75jd756sh"},
@@ -348,6 +365,87 @@ func (ts *PGDriverTestSuite) Test_WriteLoadBalancerUser() {
 	}
 }
 
+func (ts *PGDriverTestSuite) Test_LoadBalancerInvitations() {
+	tests := []struct {
+		name      string
+		lbIDInput string
+		userInput types.UserAccess
+		err       error
+	}{
+		{
+			name:      "Should fail if lb ID not provided",
+			lbIDInput: "",
+			userInput: types.UserAccess{RoleName: types.RoleMember, Email: "invitee@test.com"},
+			err:       ErrMissingID,
+		},
+		{
+			name:      "Should fail if attempting to invite a User with owner role",
+			lbIDInput: "test_lb_34gg4g43g34g5hh",
+			userInput: types.UserAccess{RoleName: types.RoleOwner, Email: "invitee@test.com"},
+			err:       ErrCannotSetToOwner,
+		},
+		{
+			name:      "Should fail if email not provided",
+			lbIDInput: "test_lb_34gg4g43g34g5hh",
+			userInput: types.UserAccess{RoleName: types.RoleMember},
+			err:       fmt.Errorf("%w: Email", ErrUserInputIsMissingField),
+		},
+	}
+
+	for _, test := range tests {
+		_, err := ts.driver.WriteLoadBalancerInvitation(testCtx, test.lbIDInput, test.userInput)
+		ts.Equal(test.err, err)
+	}
+
+	email := "invitee@test.com"
+
+	acceptedInvite, err := ts.driver.WriteLoadBalancerInvitation(testCtx, "test_lb_34gg4g43g34g5hh", types.UserAccess{RoleName: types.RoleMember, Email: email})
+	ts.NoError(err)
+	ts.Len(acceptedInvite.Token, 64)
+	ts.WithinDuration(time.Now().Add(invitationTTL), acceptedInvite.ExpiresAt, time.Minute)
+
+	declinedInvite, err := ts.driver.WriteLoadBalancerInvitation(testCtx, "test_lb_3890ru23jfi32fj", types.UserAccess{RoleName: types.RoleAdmin, Email: email})
+	ts.NoError(err)
+
+	pending, err := ts.driver.ReadPendingInvitations(testCtx, email)
+	ts.NoError(err)
+	ts.Len(pending, 2)
+	ts.Equal(acceptedInvite.Token, pending[0].Token)
+	ts.Equal("test_lb_34gg4g43g34g5hh", pending[0].LbID)
+	ts.Equal(types.RoleAdmin, pending[1].RoleName)
+
+	ts.Equal(ErrMissingID, ts.driver.AcceptInvitation(testCtx, acceptedInvite.Token, ""))
+	ts.NoError(ts.driver.AcceptInvitation(testCtx, acceptedInvite.Token, "test_user_invitee1234"))
+	ts.Equal(ErrInvitationNotFound, ts.driver.AcceptInvitation(testCtx, acceptedInvite.Token, "test_user_invitee1234"))
+
+	ts.NoError(ts.driver.DeclineInvitation(testCtx, declinedInvite.Token))
+	ts.Equal(ErrInvitationNotFound, ts.driver.DeclineInvitation(testCtx, declinedInvite.Token))
+
+	pending, err = ts.driver.ReadPendingInvitations(testCtx, email)
+	ts.NoError(err)
+	ts.Empty(pending)
+
+	userRoles, err := ts.driver.ReadUserRoles(testCtx)
+	ts.NoError(err)
+	ts.Equal([]types.PermissionsEnum{types.ReadEndpoint}, userRoles["test_user_invitee1234"]["test_lb_34gg4g43g34g5hh"])
+
+	mismatchedInvite, err := ts.driver.WriteLoadBalancerInvitation(testCtx, "test_lb_3890ru23jfi32fj", types.UserAccess{UserID: "test_user_invitee1234", RoleName: types.RoleMember, Email: email})
+	ts.NoError(err)
+	ts.Equal(ErrInvitationUserMismatch, ts.driver.AcceptInvitation(testCtx, mismatchedInvite.Token, "test_user_someone_else"))
+
+	_, err = ts.driver.db.ExecContext(testCtx, "UPDATE user_access SET invite_expires_at = $1 WHERE invite_token = $2", time.Now().Add(-time.Hour), mismatchedInvite.Token)
+	ts.NoError(err)
+	ts.Equal(ErrInvitationExpired, ts.driver.AcceptInvitation(testCtx, mismatchedInvite.Token, "test_user_invitee1234"))
+
+	removed, err := ts.driver.RemoveExpiredInvitations(testCtx)
+	ts.NoError(err)
+	ts.Equal(int64(1), removed)
+	ts.Equal(ErrInvitationNotFound, ts.driver.DeclineInvitation(testCtx, mismatchedInvite.Token))
+
+	// Clean up so the accepted invitation doesn't affect other tests
+	ts.NoError(ts.driver.RemoveUserAccess(testCtx, "test_user_invitee1234", "test_lb_34gg4g43g34g5hh"))
+}
+
 func (ts *PGDriverTestSuite) Test_UpdateLoadBalancer() {
 	tests := []struct {
 		name                string
@@ -374,6 +472,7 @@ func (ts *PGDriverTestSuite) Test_UpdateLoadBalancer() {
 				StickyMax:  sql.NullInt32{Valid: true, Int32: 500},
 				Stickiness: sql.NullBool{Valid: true, Bool: false},
 				Origins:    []string{"chrome-extension://", "test-ext://"},
+				Version:    2,
 			},
 			err: nil,
 		},
@@ -392,6 +491,7 @@ func (ts *PGDriverTestSuite) Test_UpdateLoadBalancer() {
 				StickyMax:  sql.NullInt32{Valid: true, Int32: 400},
 				Stickiness: sql.NullBool{Valid: true, Bool: true},
 				Origins:    []string{"chrome-extension://"},
+				Version:    4,
 			},
 			err: nil,
 		},
@@ -407,6 +507,7 @@ func (ts *PGDriverTestSuite) Test_UpdateLoadBalancer() {
 				StickyMax:  sql.NullInt32{Valid: true, Int32: 600},
 				Stickiness: sql.NullBool{Valid: true, Bool: false},
 				Origins:    []string{"test-extension://", "test-extension2://"},
+				Version:    2,
 			},
 			err: nil,
 		},
@@ -424,25 +525,134 @@ func (ts *PGDriverTestSuite) Test_UpdateLoadBalancer() {
 				StickyMax:  sql.NullInt32{Valid: true, Int32: 600},
 				Stickiness: sql.NullBool{Valid: true, Bool: false},
 				Origins:    []string{"chrome-extension://", "test-ext://"},
+				Version:    3,
 			},
 			err: nil,
 		},
+		{
+			name:           "Should update a single load balancer successfully if the expected version matches",
+			loadBalancerID: "test_lb_34gg4g43g34g5hh",
+			loadBalancerUpdate: &types.UpdateLoadBalancer{
+				Name:            "vipr_app_updated_4",
+				ExpectedVersion: 3,
+			},
+			expectedAfterUpdate: SelectOneLoadBalancerRow{
+				Name:       sql.NullString{Valid: true, String: "vipr_app_updated_4"},
+				Duration:   sql.NullString{Valid: true, String: "20"},
+				StickyMax:  sql.NullInt32{Valid: true, Int32: 600},
+				Stickiness: sql.NullBool{Valid: true, Bool: false},
+				Origins:    []string{"chrome-extension://", "test-ext://"},
+				Version:    4,
+			},
+			err: nil,
+		},
+		{
+			name:           "Should fail with a conflict and leave the load balancer unchanged if the expected version is stale",
+			loadBalancerID: "test_lb_34gg4g43g34g5hh",
+			loadBalancerUpdate: &types.UpdateLoadBalancer{
+				Name: "vipr_app_updated_stale",
+				StickyOptions: &types.UpdateStickyOptions{
+					Duration: "999",
+				},
+				ExpectedVersion: 3,
+			},
+			expectedAfterUpdate: SelectOneLoadBalancerRow{
+				Name:       sql.NullString{Valid: true, String: "vipr_app_updated_4"},
+				Duration:   sql.NullString{Valid: true, String: "20"},
+				StickyMax:  sql.NullInt32{Valid: true, Int32: 600},
+				Stickiness: sql.NullBool{Valid: true, Bool: false},
+				Origins:    []string{"chrome-extension://", "test-ext://"},
+				Version:    4,
+			},
+			err: types.ErrConflict,
+		},
+		{
+			name:           "Should fail and leave the load balancer unchanged if the sticky options are invalid",
+			loadBalancerID: "test_lb_34gg4g43g34g5hh",
+			loadBalancerUpdate: &types.UpdateLoadBalancer{
+				StickyOptions: &types.UpdateStickyOptions{
+					Duration:  "forever",
+					StickyMax: -1,
+				},
+			},
+			expectedAfterUpdate: SelectOneLoadBalancerRow{
+				Name:       sql.NullString{Valid: true, String: "vipr_app_updated_4"},
+				Duration:   sql.NullString{Valid: true, String: "20"},
+				StickyMax:  sql.NullInt32{Valid: true, Int32: 600},
+				Stickiness: sql.NullBool{Valid: true, Bool: false},
+				Origins:    []string{"chrome-extension://", "test-ext://"},
+				Version:    4,
+			},
+			err: types.ErrInvalidStickyDuration,
+		},
 	}
 
 	for _, test := range tests {
 		_, err := ts.driver.SelectOneLoadBalancer(testCtx, test.loadBalancerID)
-		ts.Equal(test.err, err)
+		ts.NoError(err)
 
 		err = ts.driver.UpdateLoadBalancer(testCtx, test.loadBalancerID, test.loadBalancerUpdate)
-		ts.Equal(test.err, err)
+		ts.ErrorIs(err, test.err)
 
 		lbAfterUpdate, err := ts.driver.SelectOneLoadBalancer(testCtx, test.loadBalancerID)
-		ts.Equal(test.err, err)
+		ts.NoError(err)
 		ts.Equal(test.expectedAfterUpdate.Name, lbAfterUpdate.Name)
 		ts.Equal(test.expectedAfterUpdate.Duration, lbAfterUpdate.Duration)
 		ts.Equal(test.expectedAfterUpdate.Origins, lbAfterUpdate.Origins)
 		ts.Equal(test.expectedAfterUpdate.StickyMax, lbAfterUpdate.StickyMax)
 		ts.Equal(test.expectedAfterUpdate.Stickiness, lbAfterUpdate.Stickiness)
+		ts.Equal(test.expectedAfterUpdate.Version, lbAfterUpdate.Version)
+	}
+}
+
+func (ts *PGDriverTestSuite) Test_UpdateLoadBalancerUser() {
+	tests := []struct {
+		name            string
+		lbIDInput       string
+		update          *types.UpdateUserAccess
+		expectedVersion int32
+		err             error
+	}{
+		{
+			name:      "Should fail with a conflict if the expected load balancer version is stale",
+			lbIDInput: "test_lb_3890ru23jfi32fj",
+			update: &types.UpdateUserAccess{
+				UserID:          "test_user_admin5678",
+				RoleName:        types.RoleMember,
+				ExpectedVersion: 1,
+			},
+			expectedVersion: 4,
+			err:             types.ErrConflict,
+		},
+		{
+			name:      "Should update the RoleName and increment the load balancer version if the expected version matches",
+			lbIDInput: "test_lb_3890ru23jfi32fj",
+			update: &types.UpdateUserAccess{
+				UserID:          "test_user_admin5678",
+				RoleName:        types.RoleAdmin,
+				ExpectedVersion: 4,
+			},
+			expectedVersion: 5,
+			err:             nil,
+		},
+	}
+
+	for _, test := range tests {
+		err := ts.driver.UpdateLoadBalancerUser(testCtx, test.lbIDInput, test.update)
+		ts.Equal(test.err, err)
+
+		loadBalancer, err := ts.driver.SelectOneLoadBalancer(testCtx, test.lbIDInput)
+		ts.NoError(err)
+		ts.Equal(test.expectedVersion, loadBalancer.Version)
+
+		users := []types.UserAccess{}
+		err = json.Unmarshal(loadBalancer.Users, &users)
+		ts.NoError(err)
+		for _, user := range users {
+			if user.UserID == test.update.UserID {
+				ts.Equal(types.RoleAdmin, user.RoleName)
+			}
+		}
 	}
 }
 
@@ -540,6 +750,106 @@ func (ts *PGDriverTestSuite) Test_UpdateUserAccessRole() {
 	}
 }
 
+func (ts *PGDriverTestSuite) Test_TransferLoadBalancerOwnership() {
+	tests := []struct {
+		name                 string
+		lbIDInput            string
+		fromUserID, toUserID string
+		expectedLBUserID     string
+		expectedUsers        []types.UserAccess
+		err                  error
+	}{
+		{
+			name:             "Should transfer ownership of a LoadBalancer to an accepted admin and demote the previous owner",
+			lbIDInput:        "test_lb_3890ru23jfi32fj",
+			fromUserID:       "test_user_04228205bd261a",
+			toUserID:         "test_user_admin5678",
+			expectedLBUserID: "test_user_admin5678",
+			expectedUsers: []types.UserAccess{
+				{
+					UserID:   "test_user_04228205bd261a",
+					RoleName: types.RoleAdmin,
+					Email:    "owner2@test.com",
+					Accepted: true,
+				},
+				{
+					UserID:   "test_user_admin5678",
+					RoleName: types.RoleOwner,
+					Email:    "admin2@test.com",
+					Accepted: true,
+				},
+			},
+			err: nil,
+		},
+		{
+			name:             "Should transfer ownership of a LoadBalancer back to the original owner",
+			lbIDInput:        "test_lb_3890ru23jfi32fj",
+			fromUserID:       "test_user_admin5678",
+			toUserID:         "test_user_04228205bd261a",
+			expectedLBUserID: "test_user_04228205bd261a",
+			expectedUsers: []types.UserAccess{
+				{
+					UserID:   "test_user_admin5678",
+					RoleName: types.RoleAdmin,
+					Email:    "admin2@test.com",
+					Accepted: true,
+				},
+				{
+					UserID:   "test_user_04228205bd261a",
+					RoleName: types.RoleOwner,
+					Email:    "owner2@test.com",
+					Accepted: true,
+				},
+			},
+			err: nil,
+		},
+		{
+			name:       "Should fail if the from user is not the owner of the LoadBalancer",
+			lbIDInput:  "test_lb_3890ru23jfi32fj",
+			fromUserID: "test_user_admin5678",
+			toUserID:   "test_user_04228205bd261a",
+			err:        ErrUserIsNotOwner,
+		},
+		{
+			name:       "Should fail if the new owner is not a member of the LoadBalancer",
+			lbIDInput:  "test_lb_3890ru23jfi32fj",
+			fromUserID: "test_user_04228205bd261a",
+			toUserID:   "test_user_member1234",
+			err:        ErrNewOwnerNotAccepted,
+		},
+		{
+			name:       "Should fail if the new owner is the current owner",
+			lbIDInput:  "test_lb_3890ru23jfi32fj",
+			fromUserID: "test_user_04228205bd261a",
+			toUserID:   "test_user_04228205bd261a",
+			err:        ErrSameOwner,
+		},
+		{
+			name:       "Should fail if lb ID not provided",
+			lbIDInput:  "",
+			fromUserID: "test_user_04228205bd261a",
+			toUserID:   "test_user_admin5678",
+			err:        ErrMissingID,
+		},
+	}
+
+	for _, test := range tests {
+		err := ts.driver.TransferLoadBalancerOwnership(testCtx, test.lbIDInput, test.fromUserID, test.toUserID)
+		ts.Equal(test.err, err)
+
+		if err == nil {
+			loadBalancer, err := ts.driver.SelectOneLoadBalancer(testCtx, test.lbIDInput)
+			ts.NoError(err)
+			ts.Equal(test.expectedLBUserID, loadBalancer.UserID.String)
+
+			users := []types.UserAccess{}
+			err = json.Unmarshal(loadBalancer.Users, &users)
+			ts.NoError(err)
+			ts.ElementsMatch(test.expectedUsers, users)
+		}
+	}
+}
+
 func (ts *PGDriverTestSuite) Test_RemoveLoadBalancer() {
 	tests := []struct {
 		name           string
@@ -551,15 +861,22 @@ func (ts *PGDriverTestSuite) Test_RemoveLoadBalancer() {
 			loadBalancerID: "test_lb_34gg4g43g34g5hh",
 			err:            nil,
 		},
+		{
+			name:           "Should fail if the load balancer does not exist",
+			loadBalancerID: "test_lb_unknown_4hf83h2",
+			err:            types.ErrNotFound,
+		},
 	}
 
 	for _, test := range tests {
 		err := ts.driver.RemoveLoadBalancer(testCtx, test.loadBalancerID)
 		ts.Equal(test.err, err)
 
-		lbAfterRemove, err := ts.driver.SelectOneLoadBalancer(testCtx, test.loadBalancerID)
-		ts.Equal(test.err, err)
-		ts.Empty(lbAfterRemove.UserID.String)
+		if test.err == nil {
+			lbAfterRemove, err := ts.driver.SelectOneLoadBalancer(testCtx, test.loadBalancerID)
+			ts.NoError(err)
+			ts.Empty(lbAfterRemove.UserID.String)
+		}
 	}
 }
 
@@ -587,6 +904,12 @@ func (ts *PGDriverTestSuite) Test_RemoveUserAccess() {
 			},
 			err: nil,
 		},
+		{
+			name:        "Should fail if the user no longer has access to the load balancer",
+			lbIDInput:   "test_lb_34gg4g43g34g5hh",
+			userIDInput: "test_user_member5678",
+			err:         types.ErrNotFound,
+		},
 		{
 			name:        "Should fail if user ID not provided",
 			lbIDInput:   "test_lb_34gg4g43g34g5hh",
@@ -626,3 +949,44 @@ func (ts *PGDriverTestSuite) Test_RemoveUserAccess() {
 		}
 	}
 }
+
+func (ts *PGDriverTestSuite) Test_LoadBalancerLimit() {
+	lbID := "test_lb_9l1m1t3dk2f8s4"
+
+	readLimit := func() *types.LBLimit {
+		loadBalancers, err := ts.driver.ReadLoadBalancers(testCtx)
+		ts.NoError(err)
+		for _, lb := range loadBalancers {
+			if lb.ID == lbID {
+				return lb.Limit
+			}
+		}
+		ts.FailNow("load balancer not found")
+		return nil
+	}
+
+	err := ts.driver.UpdateLoadBalancer(testCtx, lbID, &types.UpdateLoadBalancer{
+		Limit:       &types.LBLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}},
+		RemoveLimit: true,
+	})
+	ts.ErrorIs(err, types.ErrLimitSetAndRemoved)
+	ts.Nil(readLimit())
+
+	err = ts.driver.UpdateLoadBalancer(testCtx, lbID, &types.UpdateLoadBalancer{
+		Limit: &types.LBLimit{PayPlan: types.PayPlan{Type: types.Enterprise}, CustomLimit: 5_000_000},
+	})
+	ts.NoError(err)
+	ts.Equal(&types.LBLimit{PayPlan: types.PayPlan{Type: types.Enterprise}, CustomLimit: 5_000_000}, readLimit())
+
+	err = ts.driver.UpdateLoadBalancer(testCtx, lbID, &types.UpdateLoadBalancer{
+		Limit: &types.LBLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}},
+	})
+	ts.NoError(err)
+	limit := readLimit()
+	ts.Equal(types.FreetierV0, limit.PayPlan.Type)
+	ts.Equal(250_000, limit.DailyLimit())
+
+	err = ts.driver.UpdateLoadBalancer(testCtx, lbID, &types.UpdateLoadBalancer{RemoveLimit: true})
+	ts.NoError(err)
+	ts.Nil(readLimit())
+}
@@ -7,7 +7,9 @@ package postgresdriver
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/vishruthsk/portal-db-main/types"
 )
@@ -61,6 +63,15 @@ type AppLimit struct {
 	CustomLimit   sql.NullInt32 `json:"customLimit"`
 }
 
+type AppPlanHistory struct {
+	ID            int32         `json:"id"`
+	ApplicationID string        `json:"applicationID"`
+	PayPlan       string        `json:"payPlan"`
+	CustomLimit   sql.NullInt32 `json:"customLimit"`
+	EffectiveFrom time.Time     `json:"effectiveFrom"`
+	EffectiveTo   sql.NullTime  `json:"effectiveTo"`
+}
+
 type Application struct {
 	ID                 sql.NullInt32  `json:"id"`
 	ApplicationID      string         `json:"applicationID"`
@@ -73,10 +84,21 @@ type Application struct {
 	UserID             sql.NullString `json:"userID"`
 	Dummy              sql.NullBool   `json:"dummy"`
 	FirstDateSurpassed sql.NullTime   `json:"firstDateSurpassed"`
+	Version            int32          `json:"version"`
 	CreatedAt          sql.NullTime   `json:"createdAt"`
 	UpdatedAt          sql.NullTime   `json:"updatedAt"`
 }
 
+type AuditLog struct {
+	ID         int32           `json:"id"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityID"`
+	Action     string          `json:"action"`
+	Actor      sql.NullString  `json:"actor"`
+	Diff       json.RawMessage `json:"diff"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
 type Blockchain struct {
 	ID                sql.NullInt32  `json:"id"`
 	BlockchainID      string         `json:"blockchainID"`
@@ -124,6 +146,13 @@ type LbApp struct {
 	AppID string `json:"appID"`
 }
 
+type LbLimit struct {
+	ID          int32         `json:"id"`
+	LbID        string        `json:"lbID"`
+	PayPlan     string        `json:"payPlan"`
+	CustomLimit sql.NullInt32 `json:"customLimit"`
+}
+
 type Loadbalancer struct {
 	ID                int32          `json:"id"`
 	LbID              string         `json:"lbID"`
@@ -132,6 +161,7 @@ type Loadbalancer struct {
 	RequestTimeout    sql.NullInt32  `json:"requestTimeout"`
 	Gigastake         sql.NullBool   `json:"gigastake"`
 	GigastakeRedirect sql.NullBool   `json:"gigastakeRedirect"`
+	Version           int32          `json:"version"`
 	CreatedAt         sql.NullTime   `json:"createdAt"`
 	UpdatedAt         sql.NullTime   `json:"updatedAt"`
 }
@@ -164,6 +194,23 @@ type Redirect struct {
 	UpdatedAt    sql.NullTime `json:"updatedAt"`
 }
 
+type RelayUsage struct {
+	ApplicationID string    `json:"applicationID"`
+	UsageDate     time.Time `json:"usageDate"`
+	Relays        int64     `json:"relays"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type SecretKey struct {
+	ID            int32        `json:"id"`
+	ApplicationID string       `json:"applicationID"`
+	Name          string       `json:"name"`
+	SecretKey     string       `json:"secretKey"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	ExpiresAt     sql.NullTime `json:"expiresAt"`
+	RevokedAt     sql.NullTime `json:"revokedAt"`
+}
+
 type StickinessOption struct {
 	ID         int32          `json:"id"`
 	LbID       string         `json:"lbID"`
@@ -183,15 +230,24 @@ type SyncCheckOption struct {
 	ResultKey    sql.NullString `json:"resultKey"`
 }
 
+type UsageThresholdNotification struct {
+	ApplicationID string    `json:"applicationID"`
+	UsageDate     time.Time `json:"usageDate"`
+	Threshold     int32     `json:"threshold"`
+	SentAt        time.Time `json:"sentAt"`
+}
+
 type UserAccess struct {
-	ID        int32          `json:"id"`
-	LbID      sql.NullString `json:"lbID"`
-	UserID    sql.NullString `json:"userID"`
-	RoleName  sql.NullString `json:"roleName"`
-	Email     sql.NullString `json:"email"`
-	Accepted  sql.NullBool   `json:"accepted"`
-	CreatedAt sql.NullTime   `json:"createdAt"`
-	UpdatedAt sql.NullTime   `json:"updatedAt"`
+	ID              int32          `json:"id"`
+	LbID            sql.NullString `json:"lbID"`
+	UserID          sql.NullString `json:"userID"`
+	RoleName        sql.NullString `json:"roleName"`
+	Email           sql.NullString `json:"email"`
+	Accepted        sql.NullBool   `json:"accepted"`
+	InviteToken     sql.NullString `json:"inviteToken"`
+	InviteExpiresAt sql.NullTime   `json:"inviteExpiresAt"`
+	CreatedAt       sql.NullTime   `json:"createdAt"`
+	UpdatedAt       sql.NullTime   `json:"updatedAt"`
 }
 
 type UserRole struct {
@@ -0,0 +1,87 @@
+package postgresdriver
+
+import (
+	"context"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+var (
+	ErrInvalidTimeRange = types.ErrInvalidTimeRange
+)
+
+/*
+ReadAppPlanHistory returns every pay plan period of an Application that overlaps the time range, oldest first.
+A zero to reads until now. Applications created before the table existed start their history with the limit they had then.
+*/
+func (p *PostgresDriver) ReadAppPlanHistory(ctx context.Context, appID string, from, to time.Time) (_ []*types.AppPlanPeriod, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	if appID == "" {
+		return nil, ErrMissingID
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.After(to) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	dbPeriods, err := p.SelectAppPlanHistory(readCtx, SelectAppPlanHistoryParams{
+		ApplicationID: appID,
+		ToTime:        to,
+		FromTime:      from,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var periods []*types.AppPlanPeriod
+	for _, dbPeriod := range dbPeriods {
+		periods = append(periods, dbPeriod.toAppPlanPeriod())
+	}
+
+	return periods, nil
+}
+
+/* ReadAppPlanAt returns the pay plan period of an Application in effect at a time, or types.ErrNotFound if there is none */
+func (p *PostgresDriver) ReadAppPlanAt(ctx context.Context, appID string, at time.Time) (*types.AppPlanPeriod, error) {
+	periods, err := p.ReadAppPlanHistory(ctx, appID, at, at)
+	if err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return nil, types.ErrNotFound
+	}
+
+	return periods[0], nil
+}
+
+func (h *SelectAppPlanHistoryRow) toAppPlanPeriod() *types.AppPlanPeriod {
+	return &types.AppPlanPeriod{
+		ApplicationID: h.ApplicationID,
+		Limit: types.AppLimit{
+			PayPlan: types.PayPlan{
+				Type:  types.PayPlanType(h.PayPlan),
+				Limit: int(h.PlanLimit),
+			},
+			CustomLimit: int(h.CustomLimit.Int32),
+		},
+		EffectiveFrom: h.EffectiveFrom,
+		EffectiveTo:   h.EffectiveTo.Time,
+	}
+}
+
+/*
+recordAppPlanChange ends the Application's current pay plan period and starts one with its stored limit at changedAt.
+It must run in the transaction that changes the limit, nothing is recorded if the plan and custom limit did not change.
+*/
+func recordAppPlanChange(ctx context.Context, q *Queries, appID string, changedAt time.Time) error {
+	return q.InsertAppPlanHistory(ctx, InsertAppPlanHistoryParams{
+		ApplicationID: appID,
+		ChangedAt:     changedAt,
+	})
+}
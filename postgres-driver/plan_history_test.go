@@ -0,0 +1,68 @@
+package postgresdriver
+
+import (
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func (ts *PGDriverTestSuite) Test_AppPlanHistory() {
+	appID := "test_app_47hfnths73j2se"
+	beforeChange := time.Now()
+
+	// Seeded applications have their limit since they were created
+	periods, err := ts.driver.ReadAppPlanHistory(testCtx, appID, time.Time{}, time.Time{})
+	ts.NoError(err)
+	ts.Len(periods, 1)
+	ts.Equal(types.FreetierV0, periods[0].Limit.PayPlan.Type)
+	ts.Equal(time.Date(2022, 11, 11, 11, 11, 11, 0, time.UTC), periods[0].EffectiveFrom.UTC())
+	ts.True(periods[0].IsCurrent())
+
+	err = ts.driver.UpdateApplication(testCtx, appID, &types.UpdateApplication{
+		Limit: &types.AppLimit{PayPlan: types.PayPlan{Type: types.PayAsYouGoV0}},
+	})
+	ts.NoError(err)
+
+	duringPayAsYouGo := time.Now()
+
+	// The application goes back to its seeded plan so later tests are not affected
+	for i := 0; i < 2; i++ {
+		err = ts.driver.UpdateApplication(testCtx, appID, &types.UpdateApplication{
+			Limit: &types.AppLimit{PayPlan: types.PayPlan{Type: types.FreetierV0}},
+		})
+		ts.NoError(err)
+	}
+
+	periods, err = ts.driver.ReadAppPlanHistory(testCtx, appID, beforeChange, time.Time{})
+	ts.NoError(err)
+	ts.Len(periods, 3)
+	ts.Equal(types.FreetierV0, periods[0].Limit.PayPlan.Type)
+	ts.Equal(periods[0].EffectiveTo, periods[1].EffectiveFrom)
+	ts.Equal(types.PayAsYouGoV0, periods[1].Limit.PayPlan.Type)
+	ts.False(periods[1].IsCurrent())
+	ts.Equal(periods[1].EffectiveTo, periods[2].EffectiveFrom)
+	ts.Equal(types.FreetierV0, periods[2].Limit.PayPlan.Type)
+	ts.Equal(250_000, periods[2].Limit.DailyLimit())
+	ts.True(periods[2].IsCurrent())
+
+	period, err := ts.driver.ReadAppPlanAt(testCtx, appID, duringPayAsYouGo)
+	ts.NoError(err)
+	ts.Equal(types.PayAsYouGoV0, period.Limit.PayPlan.Type)
+
+	period, err = ts.driver.ReadAppPlanAt(testCtx, appID, time.Now())
+	ts.NoError(err)
+	ts.Equal(types.FreetierV0, period.Limit.PayPlan.Type)
+
+	period, err = ts.driver.ReadAppPlanAt(testCtx, appID, beforeChange)
+	ts.NoError(err)
+	ts.Equal(types.FreetierV0, period.Limit.PayPlan.Type)
+	ts.False(period.IsCurrent())
+
+	_, err = ts.driver.ReadAppPlanAt(testCtx, appID, time.Date(2022, 11, 11, 0, 0, 0, 0, time.UTC))
+	ts.Equal(types.ErrNotFound, err)
+
+	_, err = ts.driver.ReadAppPlanHistory(testCtx, appID, time.Now(), beforeChange)
+	ts.Equal(ErrInvalidTimeRange, err)
+	_, err = ts.driver.ReadAppPlanHistory(testCtx, "", beforeChange, time.Time{})
+	ts.Equal(ErrMissingID, err)
+}
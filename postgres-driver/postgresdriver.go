@@ -4,21 +4,25 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
-	"errors"
+	"sync"
 	"time"
 
 	// PQ import is required
 	_ "github.com/lib/pq"
+	"github.com/vishruthsk/portal-db-main/encryption"
 	"github.com/vishruthsk/portal-db-main/types"
 )
 
 const (
 	psqlDateLayout = "2006-01-02T15:04:05.999999"
 	idLength       = 24
+	tokenLength    = 64
+	invitationTTL  = 7 * 24 * time.Hour
 )
 
+// The input errors are defined in types so every driver returns the same ones, they are kept here for existing callers
 var (
-	ErrMissingID = errors.New("missing id")
+	ErrMissingID = types.ErrMissingID
 )
 
 // The PostgresDriver struct satisfies the Driver interface which defines all database driver methods
@@ -27,6 +31,12 @@ type PostgresDriver struct {
 	db           *sql.DB
 	notification chan *types.Notification
 	listener     Listener
+	timeoutsMu   sync.RWMutex
+	timeouts     Timeouts
+	envelope     *encryption.Envelope
+	tx           *sql.Tx
+	txActor      string
+	savepoints   int
 }
 
 /* NewPostgresDriver returns PostgresDriver instance from Postgres connection string */
@@ -41,6 +51,7 @@ func NewPostgresDriver(connectionString string, listener Listener) (*PostgresDri
 		db:           db,
 		notification: make(chan *types.Notification, 32),
 		listener:     listener,
+		timeouts:     DefaultTimeouts,
 	}
 
 	err = driver.listener.Listen("events")
@@ -48,7 +59,7 @@ func NewPostgresDriver(connectionString string, listener Listener) (*PostgresDri
 		return nil, err
 	}
 
-	go Listen(driver.listener.NotificationChannel(), driver.notification)
+	go listen(driver.listener.NotificationChannel(), driver.notification, driver.decryptNotification)
 
 	return driver, nil
 }
@@ -58,8 +69,10 @@ func NewPostgresDriver(connectionString string, listener Listener) (*PostgresDri
 func NewPostgresDriverFromDBInstance(db *sql.DB, listener Listener) *PostgresDriver {
 	driver := &PostgresDriver{
 		Queries:      New(db),
+		db:           db,
 		notification: make(chan *types.Notification, 32),
 		listener:     listener,
+		timeouts:     DefaultTimeouts,
 	}
 
 	err := driver.listener.Listen("events")
@@ -67,7 +80,7 @@ func NewPostgresDriverFromDBInstance(db *sql.DB, listener Listener) *PostgresDri
 		panic(err)
 	}
 
-	go Listen(driver.listener.NotificationChannel(), driver.notification)
+	go listen(driver.listener.NotificationChannel(), driver.notification, driver.decryptNotification)
 
 	return driver
 }
@@ -86,6 +99,15 @@ func generateRandomID() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
+func generateRandomToken() (string, error) {
+	bytes := make([]byte, tokenLength/2)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
+
 func newSQLNullString(value string) sql.NullString {
 	if value == "" {
 		return sql.NullString{}
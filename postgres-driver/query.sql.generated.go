@@ -9,12 +9,34 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/vishruthsk/portal-db-main/types"
 )
 
-const activateBlockchain = `-- name: ActivateBlockchain :exec
+const acceptInvite = `-- name: AcceptInvite :exec
+UPDATE user_access
+SET user_id = $2,
+    accepted = true,
+    invite_token = NULL,
+    invite_expires_at = NULL,
+    updated_at = $3
+WHERE invite_token = $1
+`
+
+type AcceptInviteParams struct {
+	InviteToken sql.NullString `json:"inviteToken"`
+	UserID      sql.NullString `json:"userID"`
+	UpdatedAt   sql.NullTime   `json:"updatedAt"`
+}
+
+func (q *Queries) AcceptInvite(ctx context.Context, arg AcceptInviteParams) error {
+	_, err := q.db.ExecContext(ctx, acceptInvite, arg.InviteToken, arg.UserID, arg.UpdatedAt)
+	return err
+}
+
+const activateBlockchain = `-- name: ActivateBlockchain :execrows
 UPDATE blockchains
 SET active = $2,
     updated_at = $3
@@ -27,12 +49,69 @@ type ActivateBlockchainParams struct {
 	UpdatedAt    sql.NullTime `json:"updatedAt"`
 }
 
-func (q *Queries) ActivateBlockchain(ctx context.Context, arg ActivateBlockchainParams) error {
-	_, err := q.db.ExecContext(ctx, activateBlockchain, arg.BlockchainID, arg.Active, arg.UpdatedAt)
-	return err
+func (q *Queries) ActivateBlockchain(ctx context.Context, arg ActivateBlockchainParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, activateBlockchain, arg.BlockchainID, arg.Active, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countSecretKeys = `-- name: CountSecretKeys :one
+SELECT COUNT(*)
+FROM secret_keys
+WHERE application_id = $1
+`
+
+func (q *Queries) CountSecretKeys(ctx context.Context, applicationID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSecretKeys, applicationID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteExpiredInvites = `-- name: DeleteExpiredInvites :execrows
+DELETE FROM user_access
+WHERE accepted = false
+    AND invite_expires_at < $1
+`
+
+func (q *Queries) DeleteExpiredInvites(ctx context.Context, inviteExpiresAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredInvites, inviteExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const deleteUserAccess = `-- name: DeleteUserAccess :exec
+const deleteInvite = `-- name: DeleteInvite :execrows
+DELETE FROM user_access
+WHERE invite_token = $1
+    AND accepted = false
+`
+
+func (q *Queries) DeleteInvite(ctx context.Context, inviteToken sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteInvite, inviteToken)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteLBLimit = `-- name: DeleteLBLimit :execrows
+DELETE FROM lb_limits
+WHERE lb_id = $1
+`
+
+func (q *Queries) DeleteLBLimit(ctx context.Context, lbID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteLBLimit, lbID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUserAccess = `-- name: DeleteUserAccess :execrows
 DELETE FROM user_access
 WHERE user_id = $1
     AND lb_id = $2
@@ -43,8 +122,121 @@ type DeleteUserAccessParams struct {
 	LbID   sql.NullString `json:"lbID"`
 }
 
-func (q *Queries) DeleteUserAccess(ctx context.Context, arg DeleteUserAccessParams) error {
-	_, err := q.db.ExecContext(ctx, deleteUserAccess, arg.UserID, arg.LbID)
+func (q *Queries) DeleteUserAccess(ctx context.Context, arg DeleteUserAccessParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserAccess, arg.UserID, arg.LbID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const expireSecretKeys = `-- name: ExpireSecretKeys :exec
+UPDATE secret_keys
+SET expires_at = $1
+WHERE application_id = $2
+    AND revoked_at IS NULL
+    AND (
+        expires_at IS NULL
+        OR expires_at > $1
+    )
+`
+
+type ExpireSecretKeysParams struct {
+	ExpiresAt     sql.NullTime `json:"expiresAt"`
+	ApplicationID string       `json:"applicationID"`
+}
+
+func (q *Queries) ExpireSecretKeys(ctx context.Context, arg ExpireSecretKeysParams) error {
+	_, err := q.db.ExecContext(ctx, expireSecretKeys, arg.ExpiresAt, arg.ApplicationID)
+	return err
+}
+
+const incrementLBVersion = `-- name: IncrementLBVersion :execrows
+UPDATE loadbalancers AS l
+SET version = l.version + 1
+WHERE l.lb_id = $1
+    AND (
+        $2::INT = 0
+        OR l.version = $2::INT
+    )
+`
+
+type IncrementLBVersionParams struct {
+	LbID            string `json:"lbID"`
+	ExpectedVersion int32  `json:"expectedVersion"`
+}
+
+func (q *Queries) IncrementLBVersion(ctx context.Context, arg IncrementLBVersionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, incrementLBVersion, arg.LbID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const incrementRelayUsage = `-- name: IncrementRelayUsage :one
+INSERT INTO relay_usage AS ru (
+        application_id,
+        usage_date,
+        relays,
+        updated_at
+    )
+VALUES ($1, $2, $3, $4) ON CONFLICT (application_id, usage_date) DO
+UPDATE
+SET relays = ru.relays + EXCLUDED.relays,
+    updated_at = EXCLUDED.updated_at
+RETURNING relays
+`
+
+type IncrementRelayUsageParams struct {
+	ApplicationID string    `json:"applicationID"`
+	UsageDate     time.Time `json:"usageDate"`
+	Relays        int64     `json:"relays"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+func (q *Queries) IncrementRelayUsage(ctx context.Context, arg IncrementRelayUsageParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, incrementRelayUsage,
+		arg.ApplicationID,
+		arg.UsageDate,
+		arg.Relays,
+		arg.UpdatedAt,
+	)
+	var relays int64
+	err := row.Scan(&relays)
+	return relays, err
+}
+
+const incrementRelayUsages = `-- name: IncrementRelayUsages :exec
+INSERT INTO relay_usage AS ru (
+        application_id,
+        usage_date,
+        relays,
+        updated_at
+    )
+SELECT unnest($1::VARCHAR []),
+    unnest($2::VARCHAR [])::DATE,
+    unnest($3::BIGINT []),
+    $4 ON CONFLICT (application_id, usage_date) DO
+UPDATE
+SET relays = ru.relays + EXCLUDED.relays,
+    updated_at = EXCLUDED.updated_at
+`
+
+type IncrementRelayUsagesParams struct {
+	ApplicationIds []string  `json:"applicationIds"`
+	UsageDates     []string  `json:"usageDates"`
+	Relays         []int64   `json:"relays"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+func (q *Queries) IncrementRelayUsages(ctx context.Context, arg IncrementRelayUsagesParams) error {
+	_, err := q.db.ExecContext(ctx, incrementRelayUsages,
+		pq.Array(arg.ApplicationIds),
+		pq.Array(arg.UsageDates),
+		pq.Array(arg.Relays),
+		arg.UpdatedAt,
+	)
 	return err
 }
 
@@ -64,6 +256,53 @@ func (q *Queries) InsertAppLimit(ctx context.Context, arg InsertAppLimitParams)
 	return err
 }
 
+const insertAppPlanHistory = `-- name: InsertAppPlanHistory :exec
+WITH current_limit AS (
+    SELECT application_id,
+        pay_plan,
+        custom_limit
+    FROM app_limits
+    WHERE application_id = $1
+),
+closed_period AS (
+    UPDATE app_plan_history AS h
+    SET effective_to = $2::TIMESTAMP
+    FROM current_limit AS cl
+    WHERE h.application_id = cl.application_id
+        AND h.effective_to IS NULL
+        AND (h.pay_plan, h.custom_limit) IS DISTINCT FROM (cl.pay_plan, cl.custom_limit)
+    RETURNING h.id
+)
+INSERT INTO app_plan_history (
+        application_id,
+        pay_plan,
+        custom_limit,
+        effective_from
+    )
+SELECT cl.application_id,
+    cl.pay_plan,
+    cl.custom_limit,
+    $2::TIMESTAMP
+FROM current_limit AS cl
+WHERE NOT EXISTS (
+        SELECT 1
+        FROM app_plan_history AS h
+        WHERE h.application_id = cl.application_id
+            AND h.effective_to IS NULL
+            AND (h.pay_plan, h.custom_limit) IS NOT DISTINCT FROM (cl.pay_plan, cl.custom_limit)
+    )
+`
+
+type InsertAppPlanHistoryParams struct {
+	ApplicationID string    `json:"applicationID"`
+	ChangedAt     time.Time `json:"changedAt"`
+}
+
+func (q *Queries) InsertAppPlanHistory(ctx context.Context, arg InsertAppPlanHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, insertAppPlanHistory, arg.ApplicationID, arg.ChangedAt)
+	return err
+}
+
 const insertApplication = `-- name: InsertApplication :exec
 INSERT into applications (
         application_id,
@@ -272,6 +511,48 @@ func (q *Queries) InsertGatewaySettings(ctx context.Context, arg InsertGatewaySe
 	return err
 }
 
+const insertInvitation = `-- name: InsertInvitation :exec
+INSERT INTO user_access (
+        lb_id,
+        role_name,
+        user_id,
+        email,
+        accepted,
+        invite_token,
+        invite_expires_at,
+        created_at,
+        updated_at
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type InsertInvitationParams struct {
+	LbID            sql.NullString `json:"lbID"`
+	RoleName        sql.NullString `json:"roleName"`
+	UserID          sql.NullString `json:"userID"`
+	Email           sql.NullString `json:"email"`
+	Accepted        sql.NullBool   `json:"accepted"`
+	InviteToken     sql.NullString `json:"inviteToken"`
+	InviteExpiresAt sql.NullTime   `json:"inviteExpiresAt"`
+	CreatedAt       sql.NullTime   `json:"createdAt"`
+	UpdatedAt       sql.NullTime   `json:"updatedAt"`
+}
+
+func (q *Queries) InsertInvitation(ctx context.Context, arg InsertInvitationParams) error {
+	_, err := q.db.ExecContext(ctx, insertInvitation,
+		arg.LbID,
+		arg.RoleName,
+		arg.UserID,
+		arg.Email,
+		arg.Accepted,
+		arg.InviteToken,
+		arg.InviteExpiresAt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const insertLbApps = `-- name: InsertLbApps :exec
 INSERT into lb_apps (lb_id, app_id)
 SELECT $1,
@@ -416,6 +697,36 @@ func (q *Queries) InsertRedirect(ctx context.Context, arg InsertRedirectParams)
 	return err
 }
 
+const insertSecretKey = `-- name: InsertSecretKey :exec
+INSERT INTO secret_keys (
+        application_id,
+        name,
+        secret_key,
+        created_at,
+        expires_at
+    )
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type InsertSecretKeyParams struct {
+	ApplicationID string       `json:"applicationID"`
+	Name          string       `json:"name"`
+	SecretKey     string       `json:"secretKey"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	ExpiresAt     sql.NullTime `json:"expiresAt"`
+}
+
+func (q *Queries) InsertSecretKey(ctx context.Context, arg InsertSecretKeyParams) error {
+	_, err := q.db.ExecContext(ctx, insertSecretKey,
+		arg.ApplicationID,
+		arg.Name,
+		arg.SecretKey,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
 const insertStickinessOptions = `-- name: InsertStickinessOptions :exec
 INSERT INTO stickiness_options (
         lb_id,
@@ -486,6 +797,55 @@ func (q *Queries) InsertSyncCheckOptions(ctx context.Context, arg InsertSyncChec
 	return err
 }
 
+const insertUsageThresholdNotifications = `-- name: InsertUsageThresholdNotifications :many
+INSERT INTO usage_threshold_notifications (
+        application_id,
+        usage_date,
+        threshold,
+        sent_at
+    )
+SELECT $1,
+    $2,
+    unnest($3::INT []),
+    $4 ON CONFLICT DO NOTHING
+RETURNING threshold
+`
+
+type InsertUsageThresholdNotificationsParams struct {
+	ApplicationID string    `json:"applicationID"`
+	UsageDate     time.Time `json:"usageDate"`
+	Thresholds    []int32   `json:"thresholds"`
+	SentAt        time.Time `json:"sentAt"`
+}
+
+func (q *Queries) InsertUsageThresholdNotifications(ctx context.Context, arg InsertUsageThresholdNotificationsParams) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, insertUsageThresholdNotifications,
+		arg.ApplicationID,
+		arg.UsageDate,
+		pq.Array(arg.Thresholds),
+		arg.SentAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var threshold int32
+		if err := rows.Scan(&threshold); err != nil {
+			return nil, err
+		}
+		items = append(items, threshold)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertUserAccess = `-- name: InsertUserAccess :exec
 INSERT INTO user_access (
         lb_id,
@@ -522,7 +882,71 @@ func (q *Queries) InsertUserAccess(ctx context.Context, arg InsertUserAccessPara
 	return err
 }
 
-const removeApp = `-- name: RemoveApp :exec
+const loadBalancerExists = `-- name: LoadBalancerExists :one
+SELECT EXISTS (
+        SELECT 1
+        FROM loadbalancers
+        WHERE lb_id = $1
+    )
+`
+
+func (q *Queries) LoadBalancerExists(ctx context.Context, lbID string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, loadBalancerExists, lbID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const markFirstDateSurpassed = `-- name: MarkFirstDateSurpassed :many
+UPDATE applications AS a
+SET first_date_surpassed = $1
+FROM relay_usage AS ru
+    INNER JOIN app_limits AS al ON ru.application_id = al.application_id
+    INNER JOIN pay_plans AS pp ON al.pay_plan = pp.plan_type
+WHERE a.application_id = ru.application_id
+    AND a.first_date_surpassed IS NULL
+    AND ru.application_id = ANY ($2::VARCHAR [])
+    AND ru.usage_date = ANY ($3::VARCHAR []::DATE [])
+    AND ru.relays > NULLIF(
+        CASE
+            WHEN al.pay_plan = 'ENTERPRISE' THEN COALESCE(al.custom_limit, 0)
+            ELSE pp.daily_limit
+        END,
+        0
+    )
+RETURNING a.application_id
+`
+
+type MarkFirstDateSurpassedParams struct {
+	FirstDateSurpassed sql.NullTime `json:"firstDateSurpassed"`
+	ApplicationIds     []string     `json:"applicationIds"`
+	UsageDates         []string     `json:"usageDates"`
+}
+
+func (q *Queries) MarkFirstDateSurpassed(ctx context.Context, arg MarkFirstDateSurpassedParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, markFirstDateSurpassed, arg.FirstDateSurpassed, pq.Array(arg.ApplicationIds), pq.Array(arg.UsageDates))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var application_id string
+		if err := rows.Scan(&application_id); err != nil {
+			return nil, err
+		}
+		items = append(items, application_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeApp = `-- name: RemoveApp :execrows
 UPDATE applications
 SET status = COALESCE($2, status)
 WHERE application_id = $1
@@ -533,12 +957,15 @@ type RemoveAppParams struct {
 	Status        sql.NullString `json:"status"`
 }
 
-func (q *Queries) RemoveApp(ctx context.Context, arg RemoveAppParams) error {
-	_, err := q.db.ExecContext(ctx, removeApp, arg.ApplicationID, arg.Status)
-	return err
+func (q *Queries) RemoveApp(ctx context.Context, arg RemoveAppParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeApp, arg.ApplicationID, arg.Status)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const removeLB = `-- name: RemoveLB :exec
+const removeLB = `-- name: RemoveLB :execrows
 UPDATE loadbalancers
 SET user_id = '',
     updated_at = $2
@@ -550,9 +977,50 @@ type RemoveLBParams struct {
 	UpdatedAt sql.NullTime `json:"updatedAt"`
 }
 
-func (q *Queries) RemoveLB(ctx context.Context, arg RemoveLBParams) error {
-	_, err := q.db.ExecContext(ctx, removeLB, arg.LbID, arg.UpdatedAt)
-	return err
+func (q *Queries) RemoveLB(ctx context.Context, arg RemoveLBParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeLB, arg.LbID, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeSecretKey = `-- name: RevokeSecretKey :many
+UPDATE secret_keys
+SET revoked_at = $1
+WHERE application_id = $2
+    AND name = $3
+    AND revoked_at IS NULL
+RETURNING secret_key
+`
+
+type RevokeSecretKeyParams struct {
+	RevokedAt     sql.NullTime `json:"revokedAt"`
+	ApplicationID string       `json:"applicationID"`
+	Name          string       `json:"name"`
+}
+
+func (q *Queries) RevokeSecretKey(ctx context.Context, arg RevokeSecretKeyParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, revokeSecretKey, arg.RevokedAt, arg.ApplicationID, arg.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var secret_key string
+		if err := rows.Scan(&secret_key); err != nil {
+			return nil, err
+		}
+		items = append(items, secret_key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const selectAppLimit = `-- name: SelectAppLimit :one
@@ -576,6 +1044,145 @@ func (q *Queries) SelectAppLimit(ctx context.Context, applicationID string) (Sel
 	return i, err
 }
 
+const selectAppPlanHistory = `-- name: SelectAppPlanHistory :many
+SELECT h.application_id,
+    h.pay_plan,
+    h.custom_limit,
+    pp.daily_limit AS plan_limit,
+    h.effective_from,
+    h.effective_to
+FROM app_plan_history AS h
+    INNER JOIN pay_plans AS pp ON h.pay_plan = pp.plan_type
+WHERE h.application_id = $1
+    AND h.effective_from <= $2::TIMESTAMP
+    AND (
+        h.effective_to IS NULL
+        OR h.effective_to > $3::TIMESTAMP
+    )
+ORDER BY h.effective_from
+`
+
+type SelectAppPlanHistoryParams struct {
+	ApplicationID string    `json:"applicationID"`
+	ToTime        time.Time `json:"toTime"`
+	FromTime      time.Time `json:"fromTime"`
+}
+
+type SelectAppPlanHistoryRow struct {
+	ApplicationID string        `json:"applicationID"`
+	PayPlan       string        `json:"payPlan"`
+	CustomLimit   sql.NullInt32 `json:"customLimit"`
+	PlanLimit     int32         `json:"planLimit"`
+	EffectiveFrom time.Time     `json:"effectiveFrom"`
+	EffectiveTo   sql.NullTime  `json:"effectiveTo"`
+}
+
+func (q *Queries) SelectAppPlanHistory(ctx context.Context, arg SelectAppPlanHistoryParams) ([]SelectAppPlanHistoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, selectAppPlanHistory, arg.ApplicationID, arg.ToTime, arg.FromTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectAppPlanHistoryRow
+	for rows.Next() {
+		var i SelectAppPlanHistoryRow
+		if err := rows.Scan(
+			&i.ApplicationID,
+			&i.PayPlan,
+			&i.CustomLimit,
+			&i.PlanLimit,
+			&i.EffectiveFrom,
+			&i.EffectiveTo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectApplicationStatus = `-- name: SelectApplicationStatus :one
+SELECT status
+FROM applications
+WHERE application_id = $1 FOR
+UPDATE
+`
+
+func (q *Queries) SelectApplicationStatus(ctx context.Context, applicationID string) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, selectApplicationStatus, applicationID)
+	var status sql.NullString
+	err := row.Scan(&status)
+	return status, err
+}
+
+const selectApplicationUsage = `-- name: SelectApplicationUsage :one
+SELECT a.application_id,
+    a.name,
+    a.user_id,
+    a.contact_email,
+    al.pay_plan,
+    al.custom_limit,
+    pp.daily_limit AS plan_limit,
+    ns.on_quarter,
+    ns.on_half,
+    ns.on_three_quarters,
+    ns.on_full,
+    COALESCE(ru.relays, 0)::BIGINT AS relays
+FROM applications AS a
+    LEFT JOIN app_limits AS al ON a.application_id = al.application_id
+    LEFT JOIN pay_plans AS pp ON al.pay_plan = pp.plan_type
+    LEFT JOIN notification_settings AS ns ON a.application_id = ns.application_id
+    LEFT JOIN relay_usage AS ru ON a.application_id = ru.application_id
+    AND ru.usage_date = $1
+WHERE a.application_id = $2
+`
+
+type SelectApplicationUsageParams struct {
+	UsageDate     time.Time `json:"usageDate"`
+	ApplicationID string    `json:"applicationID"`
+}
+
+type SelectApplicationUsageRow struct {
+	ApplicationID   string         `json:"applicationID"`
+	Name            sql.NullString `json:"name"`
+	UserID          sql.NullString `json:"userID"`
+	ContactEmail    sql.NullString `json:"contactEmail"`
+	PayPlan         sql.NullString `json:"payPlan"`
+	CustomLimit     sql.NullInt32  `json:"customLimit"`
+	PlanLimit       sql.NullInt32  `json:"planLimit"`
+	OnQuarter       sql.NullBool   `json:"onQuarter"`
+	OnHalf          sql.NullBool   `json:"onHalf"`
+	OnThreeQuarters sql.NullBool   `json:"onThreeQuarters"`
+	OnFull          sql.NullBool   `json:"onFull"`
+	Relays          int64          `json:"relays"`
+}
+
+func (q *Queries) SelectApplicationUsage(ctx context.Context, arg SelectApplicationUsageParams) (SelectApplicationUsageRow, error) {
+	row := q.db.QueryRowContext(ctx, selectApplicationUsage, arg.UsageDate, arg.ApplicationID)
+	var i SelectApplicationUsageRow
+	err := row.Scan(
+		&i.ApplicationID,
+		&i.Name,
+		&i.UserID,
+		&i.ContactEmail,
+		&i.PayPlan,
+		&i.CustomLimit,
+		&i.PlanLimit,
+		&i.OnQuarter,
+		&i.OnHalf,
+		&i.OnThreeQuarters,
+		&i.OnFull,
+		&i.Relays,
+	)
+	return i, err
+}
+
 const selectApplications = `-- name: SelectApplications :many
 WITH app_whitelists AS (
     SELECT application_id
@@ -596,6 +1203,7 @@ SELECT a.application_id,
     a.url,
     a.user_id,
     a.first_date_surpassed,
+    a.version,
     ga.address AS ga_address,
     ga.client_public_key AS ga_client_public_key,
     ga.private_key AS ga_private_key,
@@ -636,7 +1244,29 @@ SELECT a.application_id,
             )
         )::VARCHAR
         ELSE null
-    END as whitelist_methods
+    END as whitelist_methods,
+    (
+        SELECT json_agg(
+                json_build_object(
+                    'application_id',
+                    sk.application_id,
+                    'name',
+                    sk.name,
+                    'secret_key',
+                    sk.secret_key,
+                    'created_at',
+                    sk.created_at,
+                    'expires_at',
+                    sk.expires_at,
+                    'revoked_at',
+                    sk.revoked_at
+                )
+                ORDER BY sk.created_at,
+                    sk.id
+            )::VARCHAR
+        FROM secret_keys AS sk
+        WHERE sk.application_id = a.application_id
+    ) AS secret_keys
 FROM applications AS a
     LEFT JOIN gateway_aat AS ga ON a.application_id = ga.application_id
     LEFT JOIN gateway_settings AS gs ON a.application_id = gs.application_id
@@ -657,6 +1287,7 @@ GROUP BY a.application_id,
     a.url,
     a.user_id,
     a.first_date_surpassed,
+    a.version,
     ga.address,
     ga.client_public_key,
     ga.private_key,
@@ -693,6 +1324,7 @@ type SelectApplicationsRow struct {
 	Url                  sql.NullString `json:"url"`
 	UserID               sql.NullString `json:"userID"`
 	FirstDateSurpassed   sql.NullTime   `json:"firstDateSurpassed"`
+	Version              int32          `json:"version"`
 	GaAddress            sql.NullString `json:"gaAddress"`
 	GaClientPublicKey    sql.NullString `json:"gaClientPublicKey"`
 	GaPrivateKey         sql.NullString `json:"gaPrivateKey"`
@@ -714,6 +1346,7 @@ type SelectApplicationsRow struct {
 	PlanLimit            sql.NullInt32  `json:"planLimit"`
 	WhitelistContracts   interface{}    `json:"whitelistContracts"`
 	WhitelistMethods     interface{}    `json:"whitelistMethods"`
+	SecretKeys           interface{}    `json:"secretKeys"`
 }
 
 func (q *Queries) SelectApplications(ctx context.Context) ([]SelectApplicationsRow, error) {
@@ -738,6 +1371,7 @@ func (q *Queries) SelectApplications(ctx context.Context) ([]SelectApplicationsR
 			&i.Url,
 			&i.UserID,
 			&i.FirstDateSurpassed,
+			&i.Version,
 			&i.GaAddress,
 			&i.GaClientPublicKey,
 			&i.GaPrivateKey,
@@ -759,6 +1393,136 @@ func (q *Queries) SelectApplications(ctx context.Context) ([]SelectApplicationsR
 			&i.PlanLimit,
 			&i.WhitelistContracts,
 			&i.WhitelistMethods,
+			&i.SecretKeys,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectApplicationsOverLimit = `-- name: SelectApplicationsOverLimit :many
+SELECT ru.application_id,
+    ru.relays,
+    a.first_date_surpassed,
+    al.pay_plan,
+    al.custom_limit,
+    pp.daily_limit AS plan_limit
+FROM relay_usage AS ru
+    INNER JOIN applications AS a ON ru.application_id = a.application_id
+    INNER JOIN app_limits AS al ON ru.application_id = al.application_id
+    INNER JOIN pay_plans AS pp ON al.pay_plan = pp.plan_type
+WHERE ru.usage_date = $1
+    AND ru.relays > NULLIF(
+        CASE
+            WHEN al.pay_plan = 'ENTERPRISE' THEN COALESCE(al.custom_limit, 0)
+            ELSE pp.daily_limit
+        END,
+        0
+    )
+ORDER BY ru.application_id
+`
+
+type SelectApplicationsOverLimitRow struct {
+	ApplicationID      string        `json:"applicationID"`
+	Relays             int64         `json:"relays"`
+	FirstDateSurpassed sql.NullTime  `json:"firstDateSurpassed"`
+	PayPlan            string        `json:"payPlan"`
+	CustomLimit        sql.NullInt32 `json:"customLimit"`
+	PlanLimit          int32         `json:"planLimit"`
+}
+
+func (q *Queries) SelectApplicationsOverLimit(ctx context.Context, usageDate time.Time) ([]SelectApplicationsOverLimitRow, error) {
+	rows, err := q.db.QueryContext(ctx, selectApplicationsOverLimit, usageDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectApplicationsOverLimitRow
+	for rows.Next() {
+		var i SelectApplicationsOverLimitRow
+		if err := rows.Scan(
+			&i.ApplicationID,
+			&i.Relays,
+			&i.FirstDateSurpassed,
+			&i.PayPlan,
+			&i.CustomLimit,
+			&i.PlanLimit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectAuditLog = `-- name: SelectAuditLog :many
+SELECT id,
+    entity_type,
+    entity_id,
+    action,
+    actor,
+    diff,
+    created_at
+FROM audit_log
+WHERE (
+        $1::VARCHAR = ''
+        OR entity_type = $1::VARCHAR
+    )
+    AND (
+        $2::VARCHAR = ''
+        OR entity_id = $2::VARCHAR
+    )
+    AND (
+        $3::VARCHAR = ''
+        OR actor = $3::VARCHAR
+    )
+ORDER BY id DESC
+LIMIT NULLIF($4::INT, 0)
+`
+
+type SelectAuditLogParams struct {
+	EntityType string `json:"entityType"`
+	EntityID   string `json:"entityID"`
+	Actor      string `json:"actor"`
+	MaxRows    int32  `json:"maxRows"`
+}
+
+func (q *Queries) SelectAuditLog(ctx context.Context, arg SelectAuditLogParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, selectAuditLog,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Actor,
+		arg.MaxRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Action,
+			&i.Actor,
+			&i.Diff,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -773,6 +1537,40 @@ func (q *Queries) SelectApplications(ctx context.Context) ([]SelectApplicationsR
 	return items, nil
 }
 
+const selectBlockchainChainIDChecks = `-- name: SelectBlockchainChainIDChecks :many
+SELECT blockchain_id,
+    chain_id_check
+FROM blockchains
+`
+
+type SelectBlockchainChainIDChecksRow struct {
+	BlockchainID string         `json:"blockchainID"`
+	ChainIDCheck sql.NullString `json:"chainIDCheck"`
+}
+
+func (q *Queries) SelectBlockchainChainIDChecks(ctx context.Context) ([]SelectBlockchainChainIDChecksRow, error) {
+	rows, err := q.db.QueryContext(ctx, selectBlockchainChainIDChecks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectBlockchainChainIDChecksRow
+	for rows.Next() {
+		var i SelectBlockchainChainIDChecksRow
+		if err := rows.Scan(&i.BlockchainID, &i.ChainIDCheck); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectBlockchains = `-- name: SelectBlockchains :many
 SELECT b.blockchain_id,
     b.altruist,
@@ -886,6 +1684,43 @@ func (q *Queries) SelectBlockchains(ctx context.Context) ([]SelectBlockchainsRow
 	return items, nil
 }
 
+const selectGatewayAATPrivateKeys = `-- name: SelectGatewayAATPrivateKeys :many
+SELECT application_id,
+    private_key
+FROM gateway_aat
+WHERE private_key IS NOT NULL
+    AND private_key <> '' FOR
+UPDATE
+`
+
+type SelectGatewayAATPrivateKeysRow struct {
+	ApplicationID string         `json:"applicationID"`
+	PrivateKey    sql.NullString `json:"privateKey"`
+}
+
+func (q *Queries) SelectGatewayAATPrivateKeys(ctx context.Context) ([]SelectGatewayAATPrivateKeysRow, error) {
+	rows, err := q.db.QueryContext(ctx, selectGatewayAATPrivateKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectGatewayAATPrivateKeysRow
+	for rows.Next() {
+		var i SelectGatewayAATPrivateKeysRow
+		if err := rows.Scan(&i.ApplicationID, &i.PrivateKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectGatewaySettings = `-- name: SelectGatewaySettings :one
 SELECT gs.application_id AS application_id,
     gs.secret_key AS secret_key,
@@ -942,6 +1777,98 @@ func (q *Queries) SelectGatewaySettings(ctx context.Context, applicationID strin
 	return i, err
 }
 
+const selectGatewaySettingsSecretKey = `-- name: SelectGatewaySettingsSecretKey :one
+SELECT secret_key
+FROM gateway_settings
+WHERE application_id = $1 FOR
+UPDATE
+`
+
+func (q *Queries) SelectGatewaySettingsSecretKey(ctx context.Context, applicationID string) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, selectGatewaySettingsSecretKey, applicationID)
+	var secret_key sql.NullString
+	err := row.Scan(&secret_key)
+	return secret_key, err
+}
+
+const selectGatewaySettingsSecretKeys = `-- name: SelectGatewaySettingsSecretKeys :many
+SELECT application_id,
+    secret_key
+FROM gateway_settings
+WHERE secret_key IS NOT NULL
+    AND secret_key <> '' FOR
+UPDATE
+`
+
+type SelectGatewaySettingsSecretKeysRow struct {
+	ApplicationID string         `json:"applicationID"`
+	SecretKey     sql.NullString `json:"secretKey"`
+}
+
+func (q *Queries) SelectGatewaySettingsSecretKeys(ctx context.Context) ([]SelectGatewaySettingsSecretKeysRow, error) {
+	rows, err := q.db.QueryContext(ctx, selectGatewaySettingsSecretKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectGatewaySettingsSecretKeysRow
+	for rows.Next() {
+		var i SelectGatewaySettingsSecretKeysRow
+		if err := rows.Scan(&i.ApplicationID, &i.SecretKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectInvitation = `-- name: SelectInvitation :one
+SELECT lb_id,
+    user_id,
+    role_name,
+    email,
+    accepted,
+    invite_token,
+    invite_expires_at,
+    created_at
+FROM user_access
+WHERE invite_token = $1 FOR
+UPDATE
+`
+
+type SelectInvitationRow struct {
+	LbID            sql.NullString `json:"lbID"`
+	UserID          sql.NullString `json:"userID"`
+	RoleName        sql.NullString `json:"roleName"`
+	Email           sql.NullString `json:"email"`
+	Accepted        sql.NullBool   `json:"accepted"`
+	InviteToken     sql.NullString `json:"inviteToken"`
+	InviteExpiresAt sql.NullTime   `json:"inviteExpiresAt"`
+	CreatedAt       sql.NullTime   `json:"createdAt"`
+}
+
+func (q *Queries) SelectInvitation(ctx context.Context, inviteToken sql.NullString) (SelectInvitationRow, error) {
+	row := q.db.QueryRowContext(ctx, selectInvitation, inviteToken)
+	var i SelectInvitationRow
+	err := row.Scan(
+		&i.LbID,
+		&i.UserID,
+		&i.RoleName,
+		&i.Email,
+		&i.Accepted,
+		&i.InviteToken,
+		&i.InviteExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const selectLoadBalancers = `-- name: SelectLoadBalancers :many
 SELECT lb.lb_id,
     lb.name,
@@ -949,16 +1876,22 @@ SELECT lb.lb_id,
     lb.gigastake,
     lb.gigastake_redirect,
     lb.user_id,
+    lb.version,
     so.duration AS s_duration,
     so.sticky_max AS s_sticky_max,
     so.stickiness AS s_stickiness,
     so.origins AS s_origins,
+    ll.pay_plan AS l_pay_plan,
+    ll.custom_limit AS l_custom_limit,
+    lpp.daily_limit AS l_plan_limit,
     STRING_AGG(la.app_id, ',') AS app_ids,
     COALESCE(user_access.ua, '[]') AS users,
     lb.created_at,
     lb.updated_at
 FROM loadbalancers AS lb
     LEFT JOIN stickiness_options AS so ON lb.lb_id = so.lb_id
+    LEFT JOIN lb_limits AS ll ON lb.lb_id = ll.lb_id
+    LEFT JOIN pay_plans AS lpp ON ll.pay_plan = lpp.plan_type
     LEFT JOIN lb_apps AS la ON lb.lb_id = la.lb_id
     LEFT JOIN LATERAL (
         SELECT jsonb_agg(
@@ -985,10 +1918,14 @@ GROUP BY lb.lb_id,
     lb.gigastake,
     lb.gigastake_redirect,
     lb.user_id,
+    lb.version,
     so.duration,
     so.sticky_max,
     so.stickiness,
     so.origins,
+    ll.pay_plan,
+    ll.custom_limit,
+    lpp.daily_limit,
     user_access.ua
 ORDER BY lb.lb_id ASC
 `
@@ -1000,10 +1937,14 @@ type SelectLoadBalancersRow struct {
 	Gigastake         sql.NullBool    `json:"gigastake"`
 	GigastakeRedirect sql.NullBool    `json:"gigastakeRedirect"`
 	UserID            sql.NullString  `json:"userID"`
+	Version           int32           `json:"version"`
 	SDuration         sql.NullString  `json:"sDuration"`
 	SStickyMax        sql.NullInt32   `json:"sStickyMax"`
 	SStickiness       sql.NullBool    `json:"sStickiness"`
 	SOrigins          []string        `json:"sOrigins"`
+	LPayPlan          sql.NullString  `json:"lPayPlan"`
+	LCustomLimit      sql.NullInt32   `json:"lCustomLimit"`
+	LPlanLimit        sql.NullInt32   `json:"lPlanLimit"`
 	AppIds            []byte          `json:"appIds"`
 	Users             json.RawMessage `json:"users"`
 	CreatedAt         sql.NullTime    `json:"createdAt"`
@@ -1026,10 +1967,14 @@ func (q *Queries) SelectLoadBalancers(ctx context.Context) ([]SelectLoadBalancer
 			&i.Gigastake,
 			&i.GigastakeRedirect,
 			&i.UserID,
+			&i.Version,
 			&i.SDuration,
 			&i.SStickyMax,
 			&i.SStickiness,
 			pq.Array(&i.SOrigins),
+			&i.LPayPlan,
+			&i.LCustomLimit,
+			&i.LPlanLimit,
 			&i.AppIds,
 			&i.Users,
 			&i.CreatedAt,
@@ -1102,6 +2047,7 @@ SELECT a.application_id,
     a.url,
     a.user_id,
     a.first_date_surpassed,
+    a.version,
     ga.address AS ga_address,
     ga.client_public_key AS ga_client_public_key,
     ga.private_key AS ga_private_key,
@@ -1164,6 +2110,7 @@ GROUP BY a.application_id,
     a.url,
     a.user_id,
     a.first_date_surpassed,
+    a.version,
     ga.address,
     ga.client_public_key,
     ga.private_key,
@@ -1200,6 +2147,7 @@ type SelectOneApplicationRow struct {
 	Url                  sql.NullString `json:"url"`
 	UserID               sql.NullString `json:"userID"`
 	FirstDateSurpassed   sql.NullTime   `json:"firstDateSurpassed"`
+	Version              int32          `json:"version"`
 	GaAddress            sql.NullString `json:"gaAddress"`
 	GaClientPublicKey    sql.NullString `json:"gaClientPublicKey"`
 	GaPrivateKey         sql.NullString `json:"gaPrivateKey"`
@@ -1239,6 +2187,7 @@ func (q *Queries) SelectOneApplication(ctx context.Context, applicationID string
 		&i.Url,
 		&i.UserID,
 		&i.FirstDateSurpassed,
+		&i.Version,
 		&i.GaAddress,
 		&i.GaClientPublicKey,
 		&i.GaPrivateKey,
@@ -1271,16 +2220,22 @@ SELECT lb.lb_id,
     lb.gigastake,
     lb.gigastake_redirect,
     lb.user_id,
+    lb.version,
     so.duration,
     so.sticky_max,
     so.stickiness,
     so.origins,
+    ll.pay_plan,
+    ll.custom_limit,
+    lpp.daily_limit AS plan_limit,
     STRING_AGG(la.app_id, ',') AS app_ids,
     COALESCE(user_access.ua, '[]') AS users,
     lb.created_at,
     lb.updated_at
 FROM loadbalancers AS lb
     LEFT JOIN stickiness_options AS so ON lb.lb_id = so.lb_id
+    LEFT JOIN lb_limits AS ll ON lb.lb_id = ll.lb_id
+    LEFT JOIN pay_plans AS lpp ON ll.pay_plan = lpp.plan_type
     LEFT JOIN lb_apps AS la ON lb.lb_id = la.lb_id
     LEFT JOIN LATERAL (
         SELECT jsonb_agg(
@@ -1308,10 +2263,14 @@ GROUP BY lb.lb_id,
     lb.gigastake,
     lb.gigastake_redirect,
     lb.user_id,
+    lb.version,
     so.duration,
     so.sticky_max,
     so.stickiness,
     so.origins,
+    ll.pay_plan,
+    ll.custom_limit,
+    lpp.daily_limit,
     user_access.ua
 `
 
@@ -1322,10 +2281,14 @@ type SelectOneLoadBalancerRow struct {
 	Gigastake         sql.NullBool    `json:"gigastake"`
 	GigastakeRedirect sql.NullBool    `json:"gigastakeRedirect"`
 	UserID            sql.NullString  `json:"userID"`
+	Version           int32           `json:"version"`
 	Duration          sql.NullString  `json:"duration"`
 	StickyMax         sql.NullInt32   `json:"stickyMax"`
 	Stickiness        sql.NullBool    `json:"stickiness"`
 	Origins           []string        `json:"origins"`
+	PayPlan           sql.NullString  `json:"payPlan"`
+	CustomLimit       sql.NullInt32   `json:"customLimit"`
+	PlanLimit         sql.NullInt32   `json:"planLimit"`
 	AppIds            []byte          `json:"appIds"`
 	Users             json.RawMessage `json:"users"`
 	CreatedAt         sql.NullTime    `json:"createdAt"`
@@ -1342,10 +2305,14 @@ func (q *Queries) SelectOneLoadBalancer(ctx context.Context, lbID string) (Selec
 		&i.Gigastake,
 		&i.GigastakeRedirect,
 		&i.UserID,
+		&i.Version,
 		&i.Duration,
 		&i.StickyMax,
 		&i.Stickiness,
 		pq.Array(&i.Origins),
+		&i.PayPlan,
+		&i.CustomLimit,
+		&i.PlanLimit,
 		&i.AppIds,
 		&i.Users,
 		&i.CreatedAt,
@@ -1389,6 +2356,181 @@ func (q *Queries) SelectPayPlans(ctx context.Context) ([]SelectPayPlansRow, erro
 	return items, nil
 }
 
+const selectPendingInvitations = `-- name: SelectPendingInvitations :many
+SELECT lb_id,
+    user_id,
+    role_name,
+    email,
+    accepted,
+    invite_token,
+    invite_expires_at,
+    created_at
+FROM user_access
+WHERE email = $1
+    AND accepted = false
+    AND invite_token IS NOT NULL
+    AND invite_expires_at > $2
+ORDER BY created_at ASC
+`
+
+type SelectPendingInvitationsParams struct {
+	Email sql.NullString `json:"email"`
+	Now   sql.NullTime   `json:"now"`
+}
+
+type SelectPendingInvitationsRow struct {
+	LbID            sql.NullString `json:"lbID"`
+	UserID          sql.NullString `json:"userID"`
+	RoleName        sql.NullString `json:"roleName"`
+	Email           sql.NullString `json:"email"`
+	Accepted        sql.NullBool   `json:"accepted"`
+	InviteToken     sql.NullString `json:"inviteToken"`
+	InviteExpiresAt sql.NullTime   `json:"inviteExpiresAt"`
+	CreatedAt       sql.NullTime   `json:"createdAt"`
+}
+
+func (q *Queries) SelectPendingInvitations(ctx context.Context, arg SelectPendingInvitationsParams) ([]SelectPendingInvitationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, selectPendingInvitations, arg.Email, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectPendingInvitationsRow
+	for rows.Next() {
+		var i SelectPendingInvitationsRow
+		if err := rows.Scan(
+			&i.LbID,
+			&i.UserID,
+			&i.RoleName,
+			&i.Email,
+			&i.Accepted,
+			&i.InviteToken,
+			&i.InviteExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectRelayUsage = `-- name: SelectRelayUsage :many
+SELECT application_id,
+    usage_date,
+    relays,
+    updated_at
+FROM relay_usage
+WHERE (
+        $1::VARCHAR = ''
+        OR application_id = $1::VARCHAR
+    )
+    AND usage_date BETWEEN $2::DATE AND $3::DATE
+ORDER BY usage_date,
+    application_id
+`
+
+type SelectRelayUsageParams struct {
+	ApplicationID string    `json:"applicationID"`
+	FromDate      time.Time `json:"fromDate"`
+	ToDate        time.Time `json:"toDate"`
+}
+
+func (q *Queries) SelectRelayUsage(ctx context.Context, arg SelectRelayUsageParams) ([]RelayUsage, error) {
+	rows, err := q.db.QueryContext(ctx, selectRelayUsage, arg.ApplicationID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RelayUsage
+	for rows.Next() {
+		var i RelayUsage
+		if err := rows.Scan(
+			&i.ApplicationID,
+			&i.UsageDate,
+			&i.Relays,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectSecretKeySecrets = `-- name: SelectSecretKeySecrets :many
+SELECT id,
+    secret_key
+FROM secret_keys FOR
+UPDATE
+`
+
+type SelectSecretKeySecretsRow struct {
+	ID        int32  `json:"id"`
+	SecretKey string `json:"secretKey"`
+}
+
+func (q *Queries) SelectSecretKeySecrets(ctx context.Context) ([]SelectSecretKeySecretsRow, error) {
+	rows, err := q.db.QueryContext(ctx, selectSecretKeySecrets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectSecretKeySecretsRow
+	for rows.Next() {
+		var i SelectSecretKeySecretsRow
+		if err := rows.Scan(&i.ID, &i.SecretKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectUserAccess = `-- name: SelectUserAccess :one
+SELECT role_name,
+    accepted
+FROM user_access
+WHERE user_id = $1
+    AND lb_id = $2 FOR
+UPDATE
+`
+
+type SelectUserAccessParams struct {
+	UserID sql.NullString `json:"userID"`
+	LbID   sql.NullString `json:"lbID"`
+}
+
+type SelectUserAccessRow struct {
+	RoleName sql.NullString `json:"roleName"`
+	Accepted sql.NullBool   `json:"accepted"`
+}
+
+func (q *Queries) SelectUserAccess(ctx context.Context, arg SelectUserAccessParams) (SelectUserAccessRow, error) {
+	row := q.db.QueryRowContext(ctx, selectUserAccess, arg.UserID, arg.LbID)
+	var i SelectUserAccessRow
+	err := row.Scan(&i.RoleName, &i.Accepted)
+	return i, err
+}
+
 const selectUserRoles = `-- name: SelectUserRoles :many
 SELECT ua.lb_id,
     ua.user_id,
@@ -1426,7 +2568,7 @@ func (q *Queries) SelectUserRoles(ctx context.Context) ([]SelectUserRolesRow, er
 	return items, nil
 }
 
-const updateFirstDateSurpassed = `-- name: UpdateFirstDateSurpassed :exec
+const updateFirstDateSurpassed = `-- name: UpdateFirstDateSurpassed :execrows
 UPDATE applications
 SET first_date_surpassed = $1
 WHERE application_id = ANY ($2::VARCHAR [])
@@ -1437,30 +2579,114 @@ type UpdateFirstDateSurpassedParams struct {
 	ApplicationIds     []string     `json:"applicationIds"`
 }
 
-func (q *Queries) UpdateFirstDateSurpassed(ctx context.Context, arg UpdateFirstDateSurpassedParams) error {
-	_, err := q.db.ExecContext(ctx, updateFirstDateSurpassed, arg.FirstDateSurpassed, pq.Array(arg.ApplicationIds))
+func (q *Queries) UpdateFirstDateSurpassed(ctx context.Context, arg UpdateFirstDateSurpassedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateFirstDateSurpassed, arg.FirstDateSurpassed, pq.Array(arg.ApplicationIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateGatewayAATPrivateKey = `-- name: UpdateGatewayAATPrivateKey :exec
+UPDATE gateway_aat
+SET private_key = $2
+WHERE application_id = $1
+`
+
+type UpdateGatewayAATPrivateKeyParams struct {
+	ApplicationID string         `json:"applicationID"`
+	PrivateKey    sql.NullString `json:"privateKey"`
+}
+
+func (q *Queries) UpdateGatewayAATPrivateKey(ctx context.Context, arg UpdateGatewayAATPrivateKeyParams) error {
+	_, err := q.db.ExecContext(ctx, updateGatewayAATPrivateKey, arg.ApplicationID, arg.PrivateKey)
+	return err
+}
+
+const updateGatewaySettingsSecretKey = `-- name: UpdateGatewaySettingsSecretKey :exec
+UPDATE gateway_settings
+SET secret_key = $2
+WHERE application_id = $1
+`
+
+type UpdateGatewaySettingsSecretKeyParams struct {
+	ApplicationID string         `json:"applicationID"`
+	SecretKey     sql.NullString `json:"secretKey"`
+}
+
+func (q *Queries) UpdateGatewaySettingsSecretKey(ctx context.Context, arg UpdateGatewaySettingsSecretKeyParams) error {
+	_, err := q.db.ExecContext(ctx, updateGatewaySettingsSecretKey, arg.ApplicationID, arg.SecretKey)
 	return err
 }
 
-const updateLB = `-- name: UpdateLB :exec
+const updateLB = `-- name: UpdateLB :execrows
 UPDATE loadbalancers AS l
-SET name = COALESCE($2, l.name),
-    updated_at = $3
-WHERE l.lb_id = $1
+SET name = COALESCE($1, l.name),
+    updated_at = $2,
+    version = l.version + 1
+WHERE l.lb_id = $3
+    AND (
+        $4::INT = 0
+        OR l.version = $4::INT
+    )
 `
 
 type UpdateLBParams struct {
+	Name            sql.NullString `json:"name"`
+	UpdatedAt       sql.NullTime   `json:"updatedAt"`
+	LbID            string         `json:"lbID"`
+	ExpectedVersion int32          `json:"expectedVersion"`
+}
+
+func (q *Queries) UpdateLB(ctx context.Context, arg UpdateLBParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateLB,
+		arg.Name,
+		arg.UpdatedAt,
+		arg.LbID,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateLBUserID = `-- name: UpdateLBUserID :exec
+UPDATE loadbalancers
+SET user_id = $2,
+    updated_at = $3,
+    version = version + 1
+WHERE lb_id = $1
+`
+
+type UpdateLBUserIDParams struct {
 	LbID      string         `json:"lbID"`
-	Name      sql.NullString `json:"name"`
+	UserID    sql.NullString `json:"userID"`
 	UpdatedAt sql.NullTime   `json:"updatedAt"`
 }
 
-func (q *Queries) UpdateLB(ctx context.Context, arg UpdateLBParams) error {
-	_, err := q.db.ExecContext(ctx, updateLB, arg.LbID, arg.Name, arg.UpdatedAt)
+func (q *Queries) UpdateLBUserID(ctx context.Context, arg UpdateLBUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateLBUserID, arg.LbID, arg.UserID, arg.UpdatedAt)
+	return err
+}
+
+const updateSecretKeySecret = `-- name: UpdateSecretKeySecret :exec
+UPDATE secret_keys
+SET secret_key = $2
+WHERE id = $1
+`
+
+type UpdateSecretKeySecretParams struct {
+	ID        int32  `json:"id"`
+	SecretKey string `json:"secretKey"`
+}
+
+func (q *Queries) UpdateSecretKeySecret(ctx context.Context, arg UpdateSecretKeySecretParams) error {
+	_, err := q.db.ExecContext(ctx, updateSecretKeySecret, arg.ID, arg.SecretKey)
 	return err
 }
 
-const updateUserAccess = `-- name: UpdateUserAccess :exec
+const updateUserAccess = `-- name: UpdateUserAccess :execrows
 UPDATE user_access as ua
 SET role_name = COALESCE($3, ua.role_name),
     updated_at = $4
@@ -1475,14 +2701,17 @@ type UpdateUserAccessParams struct {
 	UpdatedAt sql.NullTime   `json:"updatedAt"`
 }
 
-func (q *Queries) UpdateUserAccess(ctx context.Context, arg UpdateUserAccessParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserAccess,
+func (q *Queries) UpdateUserAccess(ctx context.Context, arg UpdateUserAccessParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserAccess,
 		arg.UserID,
 		arg.LbID,
 		arg.RoleName,
 		arg.UpdatedAt,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const upsertAppLimit = `-- name: UpsertAppLimit :exec
@@ -1508,7 +2737,7 @@ func (q *Queries) UpsertAppLimit(ctx context.Context, arg UpsertAppLimitParams)
 	return err
 }
 
-const upsertApplication = `-- name: UpsertApplication :exec
+const upsertApplication = `-- name: UpsertApplication :execrows
 INSERT INTO applications AS a (
         application_id,
         name,
@@ -1516,14 +2745,23 @@ INSERT INTO applications AS a (
         first_date_surpassed,
         updated_at
     )
-VALUES ($1, $2, $3, $4, $5) ON CONFLICT (application_id) DO
+VALUES (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5
+    ) ON CONFLICT (application_id) DO
 UPDATE
 SET name = COALESCE(EXCLUDED.name, a.name),
     status = COALESCE(EXCLUDED.status, a.status),
     first_date_surpassed = COALESCE(
         EXCLUDED.first_date_surpassed,
         a.first_date_surpassed
-    )
+    ),
+    version = a.version + 1
+WHERE $6::INT = 0
+    OR a.version = $6::INT
 `
 
 type UpsertApplicationParams struct {
@@ -1532,17 +2770,22 @@ type UpsertApplicationParams struct {
 	Status             sql.NullString `json:"status"`
 	FirstDateSurpassed sql.NullTime   `json:"firstDateSurpassed"`
 	UpdatedAt          sql.NullTime   `json:"updatedAt"`
+	ExpectedVersion    int32          `json:"expectedVersion"`
 }
 
-func (q *Queries) UpsertApplication(ctx context.Context, arg UpsertApplicationParams) error {
-	_, err := q.db.ExecContext(ctx, upsertApplication,
+func (q *Queries) UpsertApplication(ctx context.Context, arg UpsertApplicationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upsertApplication,
 		arg.ApplicationID,
 		arg.Name,
 		arg.Status,
 		arg.FirstDateSurpassed,
 		arg.UpdatedAt,
+		arg.ExpectedVersion,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const upsertGatewaySettings = `-- name: UpsertGatewaySettings :exec
@@ -1593,6 +2836,25 @@ func (q *Queries) UpsertGatewaySettings(ctx context.Context, arg UpsertGatewaySe
 	return err
 }
 
+const upsertLBLimit = `-- name: UpsertLBLimit :exec
+INSERT INTO lb_limits AS ll (lb_id, pay_plan, custom_limit)
+VALUES ($1, $2, $3) ON CONFLICT (lb_id) DO
+UPDATE
+SET pay_plan = EXCLUDED.pay_plan,
+    custom_limit = EXCLUDED.custom_limit
+`
+
+type UpsertLBLimitParams struct {
+	LbID        string        `json:"lbID"`
+	PayPlan     string        `json:"payPlan"`
+	CustomLimit sql.NullInt32 `json:"customLimit"`
+}
+
+func (q *Queries) UpsertLBLimit(ctx context.Context, arg UpsertLBLimitParams) error {
+	_, err := q.db.ExecContext(ctx, upsertLBLimit, arg.LbID, arg.PayPlan, arg.CustomLimit)
+	return err
+}
+
 const upsertNotificationSettings = `-- name: UpsertNotificationSettings :exec
 INSERT INTO notification_settings AS ns (
         application_id,
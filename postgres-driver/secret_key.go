@@ -0,0 +1,364 @@
+package postgresdriver
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/encryption"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const (
+	secretKeyLength = 32
+	// defaultSecretKeyName names the key generated with an Application, or a SecretKey set before named keys were used
+	defaultSecretKeyName = "default"
+)
+
+var (
+	ErrMissingSecretKeyName = types.ErrMissingSecretKeyName
+	ErrNegativeOverlap      = types.ErrNegativeOverlap
+	ErrSecretKeyNotFound    = types.ErrSecretKeyNotFound
+)
+
+/*
+RotateSecretKey generates a new named secret key for an Application and makes it the Application's SecretKey.
+Every key that is still valid, including a SecretKey set before named keys were used, expires once the overlap
+has passed so gateways keep accepting the old keys during the rollover.
+A name can be rotated again, like the default key every Application is created with, its previous key then
+expiring with the others.
+Only the hash of the new key is stored, so the returned key cannot be read again.
+*/
+func (p *PostgresDriver) RotateSecretKey(ctx context.Context, appID, name string, overlap time.Duration) (_ *types.SecretKey, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if appID == "" {
+		return nil, ErrMissingID
+	}
+	if name == "" {
+		return nil, ErrMissingSecretKeyName
+	}
+	if overlap < 0 {
+		return nil, ErrNegativeOverlap
+	}
+
+	key, err := generateRandomSecretKey()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	secretKey := &types.SecretKey{
+		ID:        appID,
+		Name:      name,
+		Key:       key,
+		CreatedAt: now,
+	}
+	expiresAt := newSQLNullTime(now.Add(overlap))
+
+	hashedKey, err := types.HashSecretKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	currentKey, err := qtx.SelectGatewaySettingsSecretKey(ctx, appID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	keyCount, err := qtx.CountSecretKeys(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	// The legacy key is stored as the default key, already expiring, so the caller may rotate the default name too
+	if currentKey.Valid && keyCount == 0 {
+		hashedCurrentKey, err := p.hashStoredSecretKey(ctx, currentKey.String)
+		if err != nil {
+			return nil, err
+		}
+
+		err = qtx.InsertSecretKey(ctx, InsertSecretKeyParams{
+			ApplicationID: appID,
+			Name:          defaultSecretKeyName,
+			SecretKey:     hashedCurrentKey,
+			CreatedAt:     now,
+			ExpiresAt:     expiresAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = qtx.ExpireSecretKeys(ctx, ExpireSecretKeysParams{
+		ExpiresAt:     expiresAt,
+		ApplicationID: appID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.InsertSecretKey(ctx, extractInsertDBSecretKey(secretKey, hashedKey))
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.UpsertGatewaySettings(ctx, UpsertGatewaySettingsParams{
+		ApplicationID: appID,
+		SecretKey:     newSQLNullString(hashedKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return secretKey, nil
+}
+
+/*
+RevokeSecretKey immediately invalidates a named secret key of an Application, with the previous keys of its name.
+If one of them is the Application's current SecretKey, the SecretKey is cleared.
+*/
+func (p *PostgresDriver) RevokeSecretKey(ctx context.Context, appID, name string) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if appID == "" {
+		return ErrMissingID
+	}
+	if name == "" {
+		return ErrMissingSecretKeyName
+	}
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	// Locks the gateway settings first, in the same order as RotateSecretKey
+	currentKey, err := qtx.SelectGatewaySettingsSecretKey(ctx, appID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	revokedKeys, err := qtx.RevokeSecretKey(ctx, RevokeSecretKeyParams{
+		RevokedAt:     newSQLNullTime(time.Now()),
+		ApplicationID: appID,
+		Name:          name,
+	})
+	if err != nil {
+		return err
+	}
+	if len(revokedKeys) == 0 {
+		return ErrSecretKeyNotFound
+	}
+
+	for _, revokedKey := range revokedKeys {
+		if !currentKey.Valid {
+			break
+		}
+
+		isCurrentKey, err := p.isSameSecret(ctx, currentKey.String, revokedKey)
+		if err != nil {
+			return err
+		}
+		if !isCurrentKey {
+			continue
+		}
+
+		err = qtx.UpdateGatewaySettingsSecretKey(ctx, UpdateGatewaySettingsSecretKeyParams{
+			ApplicationID: appID,
+		})
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/* isSameSecret compares two stored secrets, which differ when encrypted even if their plaintexts match */
+func (p *PostgresDriver) isSameSecret(ctx context.Context, a, b string) (bool, error) {
+	plainA, err := p.decryptSecret(ctx, a)
+	if err != nil {
+		return false, err
+	}
+
+	plainB, err := p.decryptSecret(ctx, b)
+	if err != nil {
+		return false, err
+	}
+
+	return plainA == plainB, nil
+}
+
+/*
+HashSecretKeys is a one-off migration that replaces every secret key still stored in clear text or encrypted
+with its salted hash, returning how many values were rewritten.
+Encrypted secret keys can only be migrated with the KeyProvider they were encrypted with set.
+*/
+func (p *PostgresDriver) HashSecretKeys(ctx context.Context) (_ int64, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	var rewritten int64
+
+	settingsKeys, err := qtx.SelectGatewaySettingsSecretKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range settingsKeys {
+		if types.IsHashedSecretKey(row.SecretKey.String) {
+			continue
+		}
+
+		hashedKey, err := p.hashStoredSecretKey(ctx, row.SecretKey.String)
+		if err != nil {
+			return 0, err
+		}
+
+		err = qtx.UpdateGatewaySettingsSecretKey(ctx, UpdateGatewaySettingsSecretKeyParams{
+			ApplicationID: row.ApplicationID,
+			SecretKey:     newSQLNullString(hashedKey),
+		})
+		if err != nil {
+			return 0, err
+		}
+		rewritten++
+	}
+
+	namedKeys, err := qtx.SelectSecretKeySecrets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range namedKeys {
+		if types.IsHashedSecretKey(row.SecretKey) {
+			continue
+		}
+
+		hashedKey, err := p.hashStoredSecretKey(ctx, row.SecretKey)
+		if err != nil {
+			return 0, err
+		}
+
+		err = qtx.UpdateSecretKeySecret(ctx, UpdateSecretKeySecretParams{
+			ID:        row.ID,
+			SecretKey: hashedKey,
+		})
+		if err != nil {
+			return 0, err
+		}
+		rewritten++
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return rewritten, nil
+}
+
+/* hashStoredSecretKey hashes a secret key stored before hashing was introduced, decrypting it first if needed */
+func (p *PostgresDriver) hashStoredSecretKey(ctx context.Context, stored string) (string, error) {
+	if types.IsHashedSecretKey(stored) {
+		return stored, nil
+	}
+	if encryption.IsEncrypted(stored) && p.envelope == nil {
+		return "", ErrNoKeyProvider
+	}
+
+	key, err := p.decryptSecret(ctx, stored)
+	if err != nil {
+		return "", err
+	}
+
+	return types.HashSecretKey(key)
+}
+
+func extractInsertDBSecretKey(key *types.SecretKey, hashedKey string) InsertSecretKeyParams {
+	return InsertSecretKeyParams{
+		ApplicationID: key.ID,
+		Name:          key.Name,
+		SecretKey:     hashedKey,
+		CreatedAt:     key.CreatedAt,
+		ExpiresAt:     newSQLNullTime(key.ExpiresAt),
+	}
+}
+
+func generateRandomSecretKey() (string, error) {
+	bytes := make([]byte, secretKeyLength/2)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
+
+func stringToSecretKeys(rawSecretKeys string) []types.SecretKey {
+	var dbSecretKeys []dbSecretKeyJSON
+
+	if rawSecretKeys == "" {
+		return nil
+	}
+
+	_ = json.Unmarshal([]byte(rawSecretKeys), &dbSecretKeys)
+
+	var secretKeys []types.SecretKey
+	for _, dbSecretKey := range dbSecretKeys {
+		secretKeys = append(secretKeys, *dbSecretKey.toOutput())
+	}
+
+	return secretKeys
+}
+
+/* Used by Listener */
+type dbSecretKeyJSON struct {
+	ApplicationID string `json:"application_id"`
+	Name          string `json:"name"`
+	SecretKey     string `json:"secret_key"`
+	CreatedAt     string `json:"created_at"`
+	ExpiresAt     string `json:"expires_at"`
+	RevokedAt     string `json:"revoked_at"`
+}
+
+func (j dbSecretKeyJSON) toOutput() *types.SecretKey {
+	return &types.SecretKey{
+		ID:        j.ApplicationID,
+		Name:      j.Name,
+		Key:       j.SecretKey,
+		CreatedAt: psqlDateToTime(j.CreatedAt),
+		ExpiresAt: psqlDateToTime(j.ExpiresAt),
+		RevokedAt: psqlDateToTime(j.RevokedAt),
+	}
+}
@@ -0,0 +1,113 @@
+package postgresdriver
+
+import (
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func (ts *PGDriverTestSuite) Test_RotateSecretKey() {
+	appID := "test_app_47hfnths73j2se"
+
+	readSettings := func() types.GatewaySettings {
+		apps, err := ts.driver.ReadApplications(testCtx)
+		ts.NoError(err)
+		for _, app := range apps {
+			if app.ID == appID {
+				return app.GatewaySettings
+			}
+		}
+		ts.FailNow("application not found")
+		return types.GatewaySettings{}
+	}
+
+	_, err := ts.driver.RotateSecretKey(testCtx, appID, "", time.Hour)
+	ts.Equal(ErrMissingSecretKeyName, err)
+	_, err = ts.driver.RotateSecretKey(testCtx, appID, "test-key-1", -time.Hour)
+	ts.Equal(ErrNegativeOverlap, err)
+
+	// The legacy secret key is stored as the default key and stays valid during the overlap, so the new key may
+	// be named default too
+	firstKey, err := ts.driver.RotateSecretKey(testCtx, appID, defaultSecretKeyName, time.Hour)
+	ts.NoError(err)
+	ts.Len(firstKey.Key, secretKeyLength)
+	ts.Equal(defaultSecretKeyName, firstKey.Name)
+
+	settings := readSettings()
+	ts.True(types.IsHashedSecretKey(settings.SecretKey))
+	ts.True(types.VerifySecretKey(settings.SecretKey, firstKey.Key))
+	ts.Len(settings.SecretKeys, 2)
+	ts.Equal(defaultSecretKeyName, settings.SecretKeys[0].Name)
+	ts.Equal(defaultSecretKeyName, settings.SecretKeys[1].Name)
+	ts.True(types.VerifySecretKey(settings.SecretKeys[0].Key, "test_40f482d91a5ef2300ebb4e2308c"))
+	ts.True(types.VerifySecretKey(settings.SecretKeys[1].Key, firstKey.Key))
+	ts.True(settings.VerifySecretKey("test_40f482d91a5ef2300ebb4e2308c", time.Now()))
+	ts.True(settings.VerifySecretKey(firstKey.Key, time.Now()))
+	ts.False(settings.VerifySecretKey("test_40f482d91a5ef2300ebb4e2308c", time.Now().Add(2*time.Hour)))
+	ts.Len(settings.ValidSecretKeys(time.Now()), 2)
+	ts.Len(settings.ValidSecretKeys(time.Now().Add(2*time.Hour)), 1)
+
+	// Rotating a name again expires its previous key
+	rotatedKey, err := ts.driver.RotateSecretKey(testCtx, appID, defaultSecretKeyName, time.Hour)
+	ts.NoError(err)
+
+	settings = readSettings()
+	ts.True(types.VerifySecretKey(settings.SecretKey, rotatedKey.Key))
+	ts.Len(settings.SecretKeys, 3)
+	ts.True(settings.VerifySecretKey(firstKey.Key, time.Now()))
+	ts.False(settings.VerifySecretKey(firstKey.Key, time.Now().Add(2*time.Hour)))
+	ts.True(settings.VerifySecretKey(rotatedKey.Key, time.Now().Add(2*time.Hour)))
+
+	// Without an overlap every previous key expires immediately
+	secondKey, err := ts.driver.RotateSecretKey(testCtx, appID, "test-key-2", 0)
+	ts.NoError(err)
+	ts.NotEqual(rotatedKey.Key, secondKey.Key)
+
+	settings = readSettings()
+	ts.True(types.VerifySecretKey(settings.SecretKey, secondKey.Key))
+	ts.False(settings.VerifySecretKey(rotatedKey.Key, time.Now()))
+	ts.Len(settings.SecretKeys, 4)
+	validKeys := settings.ValidSecretKeys(time.Now())
+	ts.Len(validKeys, 1)
+	ts.Equal("test-key-2", validKeys[0].Name)
+
+	// Revoking the current key clears the secret key
+	err = ts.driver.RevokeSecretKey(testCtx, appID, "test-key-2")
+	ts.NoError(err)
+
+	settings = readSettings()
+	ts.Empty(settings.SecretKey)
+	ts.Empty(settings.ValidSecretKeys(time.Now()))
+	ts.True(settings.SecretKeys[3].IsRevoked())
+
+	err = ts.driver.RevokeSecretKey(testCtx, appID, "test-key-2")
+	ts.Equal(ErrSecretKeyNotFound, err)
+
+	// Revoking a name revokes every key it was rotated with
+	err = ts.driver.RevokeSecretKey(testCtx, appID, defaultSecretKeyName)
+	ts.NoError(err)
+
+	settings = readSettings()
+	for _, secretKey := range settings.SecretKeys {
+		ts.True(secretKey.IsRevoked())
+	}
+
+	err = ts.driver.RevokeSecretKey(testCtx, appID, "")
+	ts.Equal(ErrMissingSecretKeyName, err)
+}
+
+func (ts *PGDriverTestSuite) Test_SecretKeyHashing() {
+	rewritten, err := ts.driver.HashSecretKeys(testCtx)
+	ts.NoError(err)
+	ts.Positive(rewritten)
+
+	rewritten, err = ts.driver.HashSecretKeys(testCtx)
+	ts.NoError(err)
+	ts.Zero(rewritten)
+
+	storedApp, err := ts.driver.SelectOneApplication(testCtx, "test_app_5hdf7sh23jd828")
+	ts.NoError(err)
+	ts.True(types.IsHashedSecretKey(storedApp.SecretKey.String))
+	ts.True(types.VerifySecretKey(storedApp.SecretKey.String, "test_90210ac4bdd3423e24877d1ff92"))
+	ts.False(types.VerifySecretKey(storedApp.SecretKey.String, "test_40f482d91a5ef2300ebb4e2308c"))
+}
@@ -38,6 +38,10 @@ FROM blockchains AS b
         WHERE b.blockchain_id = r.blockchain_id
     ) redirects ON true
 ORDER BY b.blockchain_id ASC;
+-- name: SelectBlockchainChainIDChecks :many
+SELECT blockchain_id,
+    chain_id_check
+FROM blockchains;
 -- name: SelectPayPlans :many
 SELECT plan_type,
     daily_limit
@@ -114,7 +118,7 @@ VALUES (
         $5,
         $6
     );
--- name: ActivateBlockchain :exec
+-- name: ActivateBlockchain :execrows
 UPDATE blockchains
 SET active = $2,
     updated_at = $3
@@ -139,6 +143,7 @@ SELECT a.application_id,
     a.url,
     a.user_id,
     a.first_date_surpassed,
+    a.version,
     ga.address AS ga_address,
     ga.client_public_key AS ga_client_public_key,
     ga.private_key AS ga_private_key,
@@ -179,7 +184,29 @@ SELECT a.application_id,
             )
         )::VARCHAR
         ELSE null
-    END as whitelist_methods
+    END as whitelist_methods,
+    (
+        SELECT json_agg(
+                json_build_object(
+                    'application_id',
+                    sk.application_id,
+                    'name',
+                    sk.name,
+                    'secret_key',
+                    sk.secret_key,
+                    'created_at',
+                    sk.created_at,
+                    'expires_at',
+                    sk.expires_at,
+                    'revoked_at',
+                    sk.revoked_at
+                )
+                ORDER BY sk.created_at,
+                    sk.id
+            )::VARCHAR
+        FROM secret_keys AS sk
+        WHERE sk.application_id = a.application_id
+    ) AS secret_keys
 FROM applications AS a
     LEFT JOIN gateway_aat AS ga ON a.application_id = ga.application_id
     LEFT JOIN gateway_settings AS gs ON a.application_id = gs.application_id
@@ -200,6 +227,7 @@ GROUP BY a.application_id,
     a.url,
     a.user_id,
     a.first_date_surpassed,
+    a.version,
     ga.address,
     ga.client_public_key,
     ga.private_key,
@@ -241,6 +269,7 @@ SELECT a.application_id,
     a.url,
     a.user_id,
     a.first_date_surpassed,
+    a.version,
     ga.address AS ga_address,
     ga.client_public_key AS ga_client_public_key,
     ga.private_key AS ga_private_key,
@@ -303,6 +332,7 @@ GROUP BY a.application_id,
     a.url,
     a.user_id,
     a.first_date_surpassed,
+    a.version,
     ga.address,
     ga.client_public_key,
     ga.private_key,
@@ -357,6 +387,71 @@ FROM gateway_settings AS gs
     LEFT JOIN whitelist_contracts AS wc ON gs.application_id = wc.application_id
     LEFT JOIN whitelist_methods AS wm ON gs.application_id = wm.application_id
 WHERE gs.application_id = $1;
+-- name: SelectGatewayAATPrivateKeys :many
+SELECT application_id,
+    private_key
+FROM gateway_aat
+WHERE private_key IS NOT NULL
+    AND private_key <> '' FOR
+UPDATE;
+-- name: UpdateGatewayAATPrivateKey :exec
+UPDATE gateway_aat
+SET private_key = $2
+WHERE application_id = $1;
+-- name: SelectGatewaySettingsSecretKeys :many
+SELECT application_id,
+    secret_key
+FROM gateway_settings
+WHERE secret_key IS NOT NULL
+    AND secret_key <> '' FOR
+UPDATE;
+-- name: UpdateGatewaySettingsSecretKey :exec
+UPDATE gateway_settings
+SET secret_key = $2
+WHERE application_id = $1;
+-- name: SelectGatewaySettingsSecretKey :one
+SELECT secret_key
+FROM gateway_settings
+WHERE application_id = $1 FOR
+UPDATE;
+-- name: CountSecretKeys :one
+SELECT COUNT(*)
+FROM secret_keys
+WHERE application_id = $1;
+-- name: InsertSecretKey :exec
+INSERT INTO secret_keys (
+        application_id,
+        name,
+        secret_key,
+        created_at,
+        expires_at
+    )
+VALUES ($1, $2, $3, $4, $5);
+-- name: ExpireSecretKeys :exec
+UPDATE secret_keys
+SET expires_at = @expires_at
+WHERE application_id = @application_id
+    AND revoked_at IS NULL
+    AND (
+        expires_at IS NULL
+        OR expires_at > @expires_at
+    );
+-- name: RevokeSecretKey :many
+UPDATE secret_keys
+SET revoked_at = @revoked_at
+WHERE application_id = @application_id
+    AND name = @name
+    AND revoked_at IS NULL
+RETURNING secret_key;
+-- name: SelectSecretKeySecrets :many
+SELECT id,
+    secret_key
+FROM secret_keys FOR
+UPDATE;
+-- name: UpdateSecretKeySecret :exec
+UPDATE secret_keys
+SET secret_key = $2
+WHERE id = $1;
 -- name: SelectNotificationSettings :one
 SELECT application_id,
     signed_up,
@@ -443,7 +538,7 @@ VALUES (
         $5,
         $6
     );
--- name: UpsertApplication :exec
+-- name: UpsertApplication :execrows
 INSERT INTO applications AS a (
         application_id,
         name,
@@ -451,14 +546,23 @@ INSERT INTO applications AS a (
         first_date_surpassed,
         updated_at
     )
-VALUES ($1, $2, $3, $4, $5) ON CONFLICT (application_id) DO
+VALUES (
+        @application_id,
+        @name,
+        @status,
+        @first_date_surpassed,
+        @updated_at
+    ) ON CONFLICT (application_id) DO
 UPDATE
 SET name = COALESCE(EXCLUDED.name, a.name),
     status = COALESCE(EXCLUDED.status, a.status),
     first_date_surpassed = COALESCE(
         EXCLUDED.first_date_surpassed,
         a.first_date_surpassed
-    );
+    ),
+    version = a.version + 1
+WHERE @expected_version::INT = 0
+    OR a.version = @expected_version::INT;
 -- name: UpsertAppLimit :exec
 INSERT INTO app_limits AS al (
         application_id,
@@ -540,11 +644,16 @@ SET signed_up = COALESCE(EXCLUDED.signed_up, ns.signed_up),
     on_half = COALESCE(EXCLUDED.on_half, ns.on_half),
     on_three_quarters = COALESCE(EXCLUDED.on_three_quarters, ns.on_three_quarters),
     on_full = COALESCE(EXCLUDED.on_full, ns.on_full);
--- name: UpdateFirstDateSurpassed :exec
+-- name: UpdateFirstDateSurpassed :execrows
 UPDATE applications
 SET first_date_surpassed = @first_date_surpassed
 WHERE application_id = ANY (@application_ids::VARCHAR []);
--- name: RemoveApp :exec
+-- name: SelectApplicationStatus :one
+SELECT status
+FROM applications
+WHERE application_id = $1 FOR
+UPDATE;
+-- name: RemoveApp :execrows
 UPDATE applications
 SET status = COALESCE($2, status)
 WHERE application_id = $1;
@@ -555,16 +664,22 @@ SELECT lb.lb_id,
     lb.gigastake,
     lb.gigastake_redirect,
     lb.user_id,
+    lb.version,
     so.duration AS s_duration,
     so.sticky_max AS s_sticky_max,
     so.stickiness AS s_stickiness,
     so.origins AS s_origins,
+    ll.pay_plan AS l_pay_plan,
+    ll.custom_limit AS l_custom_limit,
+    lpp.daily_limit AS l_plan_limit,
     STRING_AGG(la.app_id, ',') AS app_ids,
     COALESCE(user_access.ua, '[]') AS users,
     lb.created_at,
     lb.updated_at
 FROM loadbalancers AS lb
     LEFT JOIN stickiness_options AS so ON lb.lb_id = so.lb_id
+    LEFT JOIN lb_limits AS ll ON lb.lb_id = ll.lb_id
+    LEFT JOIN pay_plans AS lpp ON ll.pay_plan = lpp.plan_type
     LEFT JOIN lb_apps AS la ON lb.lb_id = la.lb_id
     LEFT JOIN LATERAL (
         SELECT jsonb_agg(
@@ -591,10 +706,14 @@ GROUP BY lb.lb_id,
     lb.gigastake,
     lb.gigastake_redirect,
     lb.user_id,
+    lb.version,
     so.duration,
     so.sticky_max,
     so.stickiness,
     so.origins,
+    ll.pay_plan,
+    ll.custom_limit,
+    lpp.daily_limit,
     user_access.ua
 ORDER BY lb.lb_id ASC;
 -- name: SelectOneLoadBalancer :one
@@ -604,16 +723,22 @@ SELECT lb.lb_id,
     lb.gigastake,
     lb.gigastake_redirect,
     lb.user_id,
+    lb.version,
     so.duration,
     so.sticky_max,
     so.stickiness,
     so.origins,
+    ll.pay_plan,
+    ll.custom_limit,
+    lpp.daily_limit AS plan_limit,
     STRING_AGG(la.app_id, ',') AS app_ids,
     COALESCE(user_access.ua, '[]') AS users,
     lb.created_at,
     lb.updated_at
 FROM loadbalancers AS lb
     LEFT JOIN stickiness_options AS so ON lb.lb_id = so.lb_id
+    LEFT JOIN lb_limits AS ll ON lb.lb_id = ll.lb_id
+    LEFT JOIN pay_plans AS lpp ON ll.pay_plan = lpp.plan_type
     LEFT JOIN lb_apps AS la ON lb.lb_id = la.lb_id
     LEFT JOIN LATERAL (
         SELECT jsonb_agg(
@@ -641,10 +766,14 @@ GROUP BY lb.lb_id,
     lb.gigastake,
     lb.gigastake_redirect,
     lb.user_id,
+    lb.version,
     so.duration,
     so.sticky_max,
     so.stickiness,
     so.origins,
+    ll.pay_plan,
+    ll.custom_limit,
+    lpp.daily_limit,
     user_access.ua;
 -- name: SelectUserRoles :many
 SELECT ua.lb_id,
@@ -693,13 +822,76 @@ INSERT INTO user_access (
         updated_at
     )
 VALUES ($1, $2, $3, $4, $5, $6, $7);
--- name: UpdateUserAccess :exec
+-- name: InsertInvitation :exec
+INSERT INTO user_access (
+        lb_id,
+        role_name,
+        user_id,
+        email,
+        accepted,
+        invite_token,
+        invite_expires_at,
+        created_at,
+        updated_at
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+-- name: SelectInvitation :one
+SELECT lb_id,
+    user_id,
+    role_name,
+    email,
+    accepted,
+    invite_token,
+    invite_expires_at,
+    created_at
+FROM user_access
+WHERE invite_token = $1 FOR
+UPDATE;
+-- name: SelectPendingInvitations :many
+SELECT lb_id,
+    user_id,
+    role_name,
+    email,
+    accepted,
+    invite_token,
+    invite_expires_at,
+    created_at
+FROM user_access
+WHERE email = @email
+    AND accepted = false
+    AND invite_token IS NOT NULL
+    AND invite_expires_at > @now
+ORDER BY created_at ASC;
+-- name: AcceptInvite :exec
+UPDATE user_access
+SET user_id = $2,
+    accepted = true,
+    invite_token = NULL,
+    invite_expires_at = NULL,
+    updated_at = $3
+WHERE invite_token = $1;
+-- name: DeleteInvite :execrows
+DELETE FROM user_access
+WHERE invite_token = $1
+    AND accepted = false;
+-- name: DeleteExpiredInvites :execrows
+DELETE FROM user_access
+WHERE accepted = false
+    AND invite_expires_at < $1;
+-- name: UpdateUserAccess :execrows
 UPDATE user_access as ua
 SET role_name = COALESCE($3, ua.role_name),
     updated_at = $4
 WHERE ua.user_id = $1
     AND ua.lb_id = $2;
--- name: DeleteUserAccess :exec
+-- name: SelectUserAccess :one
+SELECT role_name,
+    accepted
+FROM user_access
+WHERE user_id = $1
+    AND lb_id = $2 FOR
+UPDATE;
+-- name: DeleteUserAccess :execrows
 DELETE FROM user_access
 WHERE user_id = $1
     AND lb_id = $2;
@@ -721,13 +913,230 @@ SET duration = COALESCE(EXCLUDED.duration, so.duration),
 INSERT into lb_apps (lb_id, app_id)
 SELECT @lb_id,
     unnest(@app_ids::VARCHAR []);
--- name: UpdateLB :exec
+-- name: UpdateLB :execrows
 UPDATE loadbalancers AS l
-SET name = COALESCE($2, l.name),
-    updated_at = $3
-WHERE l.lb_id = $1;
--- name: RemoveLB :exec
+SET name = COALESCE(@name, l.name),
+    updated_at = @updated_at,
+    version = l.version + 1
+WHERE l.lb_id = @lb_id
+    AND (
+        @expected_version::INT = 0
+        OR l.version = @expected_version::INT
+    );
+-- name: LoadBalancerExists :one
+SELECT EXISTS (
+        SELECT 1
+        FROM loadbalancers
+        WHERE lb_id = $1
+    );
+-- name: IncrementLBVersion :execrows
+UPDATE loadbalancers AS l
+SET version = l.version + 1
+WHERE l.lb_id = @lb_id
+    AND (
+        @expected_version::INT = 0
+        OR l.version = @expected_version::INT
+    );
+-- name: RemoveLB :execrows
 UPDATE loadbalancers
 SET user_id = '',
     updated_at = $2
 WHERE lb_id = $1;
+-- name: UpdateLBUserID :exec
+UPDATE loadbalancers
+SET user_id = $2,
+    updated_at = $3,
+    version = version + 1
+WHERE lb_id = $1;
+-- name: SelectAuditLog :many
+SELECT id,
+    entity_type,
+    entity_id,
+    action,
+    actor,
+    diff,
+    created_at
+FROM audit_log
+WHERE (
+        @entity_type::VARCHAR = ''
+        OR entity_type = @entity_type::VARCHAR
+    )
+    AND (
+        @entity_id::VARCHAR = ''
+        OR entity_id = @entity_id::VARCHAR
+    )
+    AND (
+        @actor::VARCHAR = ''
+        OR actor = @actor::VARCHAR
+    )
+ORDER BY id DESC
+LIMIT NULLIF(@max_rows::INT, 0);
+-- name: IncrementRelayUsage :one
+INSERT INTO relay_usage AS ru (
+        application_id,
+        usage_date,
+        relays,
+        updated_at
+    )
+VALUES ($1, $2, $3, $4) ON CONFLICT (application_id, usage_date) DO
+UPDATE
+SET relays = ru.relays + EXCLUDED.relays,
+    updated_at = EXCLUDED.updated_at
+RETURNING relays;
+-- name: IncrementRelayUsages :exec
+INSERT INTO relay_usage AS ru (
+        application_id,
+        usage_date,
+        relays,
+        updated_at
+    )
+SELECT unnest(@application_ids::VARCHAR []),
+    unnest(@usage_dates::VARCHAR [])::DATE,
+    unnest(@relays::BIGINT []),
+    @updated_at ON CONFLICT (application_id, usage_date) DO
+UPDATE
+SET relays = ru.relays + EXCLUDED.relays,
+    updated_at = EXCLUDED.updated_at;
+-- name: SelectRelayUsage :many
+SELECT application_id,
+    usage_date,
+    relays,
+    updated_at
+FROM relay_usage
+WHERE (
+        @application_id::VARCHAR = ''
+        OR application_id = @application_id::VARCHAR
+    )
+    AND usage_date BETWEEN @from_date::DATE AND @to_date::DATE
+ORDER BY usage_date,
+    application_id;
+-- name: SelectApplicationUsage :one
+SELECT a.application_id,
+    a.name,
+    a.user_id,
+    a.contact_email,
+    al.pay_plan,
+    al.custom_limit,
+    pp.daily_limit AS plan_limit,
+    ns.on_quarter,
+    ns.on_half,
+    ns.on_three_quarters,
+    ns.on_full,
+    COALESCE(ru.relays, 0)::BIGINT AS relays
+FROM applications AS a
+    LEFT JOIN app_limits AS al ON a.application_id = al.application_id
+    LEFT JOIN pay_plans AS pp ON al.pay_plan = pp.plan_type
+    LEFT JOIN notification_settings AS ns ON a.application_id = ns.application_id
+    LEFT JOIN relay_usage AS ru ON a.application_id = ru.application_id
+    AND ru.usage_date = @usage_date
+WHERE a.application_id = @application_id;
+-- name: InsertUsageThresholdNotifications :many
+INSERT INTO usage_threshold_notifications (
+        application_id,
+        usage_date,
+        threshold,
+        sent_at
+    )
+SELECT @application_id,
+    @usage_date,
+    unnest(@thresholds::INT []),
+    @sent_at ON CONFLICT DO NOTHING
+RETURNING threshold;
+-- name: MarkFirstDateSurpassed :many
+UPDATE applications AS a
+SET first_date_surpassed = @first_date_surpassed
+FROM relay_usage AS ru
+    INNER JOIN app_limits AS al ON ru.application_id = al.application_id
+    INNER JOIN pay_plans AS pp ON al.pay_plan = pp.plan_type
+WHERE a.application_id = ru.application_id
+    AND a.first_date_surpassed IS NULL
+    AND ru.application_id = ANY (@application_ids::VARCHAR [])
+    AND ru.usage_date = ANY (@usage_dates::VARCHAR []::DATE [])
+    AND ru.relays > NULLIF(
+        CASE
+            WHEN al.pay_plan = 'ENTERPRISE' THEN COALESCE(al.custom_limit, 0)
+            ELSE pp.daily_limit
+        END,
+        0
+    )
+RETURNING a.application_id;
+-- name: SelectApplicationsOverLimit :many
+SELECT ru.application_id,
+    ru.relays,
+    a.first_date_surpassed,
+    al.pay_plan,
+    al.custom_limit,
+    pp.daily_limit AS plan_limit
+FROM relay_usage AS ru
+    INNER JOIN applications AS a ON ru.application_id = a.application_id
+    INNER JOIN app_limits AS al ON ru.application_id = al.application_id
+    INNER JOIN pay_plans AS pp ON al.pay_plan = pp.plan_type
+WHERE ru.usage_date = @usage_date
+    AND ru.relays > NULLIF(
+        CASE
+            WHEN al.pay_plan = 'ENTERPRISE' THEN COALESCE(al.custom_limit, 0)
+            ELSE pp.daily_limit
+        END,
+        0
+    )
+ORDER BY ru.application_id;
+-- name: InsertAppPlanHistory :exec
+WITH current_limit AS (
+    SELECT application_id,
+        pay_plan,
+        custom_limit
+    FROM app_limits
+    WHERE application_id = @application_id
+),
+closed_period AS (
+    UPDATE app_plan_history AS h
+    SET effective_to = @changed_at::TIMESTAMP
+    FROM current_limit AS cl
+    WHERE h.application_id = cl.application_id
+        AND h.effective_to IS NULL
+        AND (h.pay_plan, h.custom_limit) IS DISTINCT FROM (cl.pay_plan, cl.custom_limit)
+    RETURNING h.id
+)
+INSERT INTO app_plan_history (
+        application_id,
+        pay_plan,
+        custom_limit,
+        effective_from
+    )
+SELECT cl.application_id,
+    cl.pay_plan,
+    cl.custom_limit,
+    @changed_at::TIMESTAMP
+FROM current_limit AS cl
+WHERE NOT EXISTS (
+        SELECT 1
+        FROM app_plan_history AS h
+        WHERE h.application_id = cl.application_id
+            AND h.effective_to IS NULL
+            AND (h.pay_plan, h.custom_limit) IS NOT DISTINCT FROM (cl.pay_plan, cl.custom_limit)
+    );
+-- name: SelectAppPlanHistory :many
+SELECT h.application_id,
+    h.pay_plan,
+    h.custom_limit,
+    pp.daily_limit AS plan_limit,
+    h.effective_from,
+    h.effective_to
+FROM app_plan_history AS h
+    INNER JOIN pay_plans AS pp ON h.pay_plan = pp.plan_type
+WHERE h.application_id = @application_id
+    AND h.effective_from <= @to_time::TIMESTAMP
+    AND (
+        h.effective_to IS NULL
+        OR h.effective_to > @from_time::TIMESTAMP
+    )
+ORDER BY h.effective_from;
+-- name: UpsertLBLimit :exec
+INSERT INTO lb_limits AS ll (lb_id, pay_plan, custom_limit)
+VALUES ($1, $2, $3) ON CONFLICT (lb_id) DO
+UPDATE
+SET pay_plan = EXCLUDED.pay_plan,
+    custom_limit = EXCLUDED.custom_limit;
+-- name: DeleteLBLimit :execrows
+DELETE FROM lb_limits
+WHERE lb_id = $1;
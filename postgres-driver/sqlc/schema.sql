@@ -70,6 +70,7 @@ CREATE TABLE IF NOT EXISTS loadbalancers (
 	request_timeout INT,
 	gigastake BOOLEAN,
 	gigastake_redirect BOOLEAN,
+	version INT NOT NULL DEFAULT 1,
 	created_at TIMESTAMP NULL,
 	updated_at TIMESTAMP NULL,
 	PRIMARY KEY (id)
@@ -84,6 +85,15 @@ CREATE TABLE IF NOT EXISTS stickiness_options (
 	PRIMARY KEY (id),
 	CONSTRAINT fk_lb FOREIGN KEY(lb_id) REFERENCES loadbalancers(lb_id)
 );
+CREATE TABLE IF NOT EXISTS lb_limits (
+	id INT GENERATED ALWAYS AS IDENTITY,
+	lb_id VARCHAR NOT NULL UNIQUE,
+	pay_plan VARCHAR NOT NULL,
+	custom_limit INT NULL,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_lb FOREIGN KEY(lb_id) REFERENCES loadbalancers(lb_id),
+	CONSTRAINT fk_pay_plan FOREIGN KEY(pay_plan) REFERENCES pay_plans(plan_type)
+);
 CREATE TABLE IF NOT EXISTS user_access (
 	id INT GENERATED ALWAYS AS IDENTITY,
 	lb_id VARCHAR,
@@ -91,6 +101,8 @@ CREATE TABLE IF NOT EXISTS user_access (
 	role_name VARCHAR,
 	email VARCHAR,
 	accepted BOOLEAN,
+	invite_token VARCHAR UNIQUE,
+	invite_expires_at TIMESTAMP NULL,
 	created_at TIMESTAMP NULL,
 	updated_at TIMESTAMP NULL,
 	PRIMARY KEY (id),
@@ -111,6 +123,7 @@ CREATE TABLE IF NOT EXISTS applications (
 	user_id VARCHAR,
 	dummy BOOLEAN,
 	first_date_surpassed TIMESTAMP NULL,
+	version INT NOT NULL DEFAULT 1,
 	created_at TIMESTAMP NULL,
 	updated_at TIMESTAMP NULL,
 	PRIMARY KEY (application_id)
@@ -124,6 +137,36 @@ CREATE TABLE IF NOT EXISTS app_limits (
 	CONSTRAINT fk_application FOREIGN KEY(application_id) REFERENCES applications(application_id),
 	CONSTRAINT fk_pay_plan FOREIGN KEY(pay_plan) REFERENCES pay_plans(plan_type)
 );
+CREATE TABLE IF NOT EXISTS app_plan_history (
+	id INT GENERATED ALWAYS AS IDENTITY,
+	application_id VARCHAR NOT NULL,
+	pay_plan VARCHAR NOT NULL,
+	custom_limit INT NULL,
+	effective_from TIMESTAMP NOT NULL,
+	effective_to TIMESTAMP NULL,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_application FOREIGN KEY(application_id) REFERENCES applications(application_id),
+	CONSTRAINT fk_pay_plan FOREIGN KEY(pay_plan) REFERENCES pay_plans(plan_type)
+);
+CREATE INDEX IF NOT EXISTS app_plan_history_application_idx ON app_plan_history (application_id, effective_from);
+-- Applications created before the history existed start it with their current limit, from their creation or now if unknown
+INSERT INTO app_plan_history (
+		application_id,
+		pay_plan,
+		custom_limit,
+		effective_from
+	)
+SELECT al.application_id,
+	al.pay_plan,
+	al.custom_limit,
+	COALESCE(a.created_at, NOW())
+FROM app_limits AS al
+	INNER JOIN applications AS a ON al.application_id = a.application_id
+WHERE NOT EXISTS (
+		SELECT 1
+		FROM app_plan_history AS h
+		WHERE h.application_id = al.application_id
+	);
 CREATE TABLE IF NOT EXISTS gateway_aat (
 	id INT GENERATED ALWAYS AS IDENTITY,
 	application_id VARCHAR NOT NULL UNIQUE,
@@ -147,6 +190,22 @@ CREATE TABLE IF NOT EXISTS gateway_settings (
 	PRIMARY KEY (id),
 	CONSTRAINT fk_application FOREIGN KEY(application_id) REFERENCES applications(application_id)
 );
+CREATE TABLE IF NOT EXISTS secret_keys (
+	id INT GENERATED ALWAYS AS IDENTITY,
+	application_id VARCHAR NOT NULL,
+	name VARCHAR NOT NULL,
+	secret_key VARCHAR NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	expires_at TIMESTAMP NULL,
+	revoked_at TIMESTAMP NULL,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_application FOREIGN KEY(application_id) REFERENCES applications(application_id)
+);
+-- Only the current key of a name must be unique, rotating a name expires its previous key
+ALTER TABLE secret_keys DROP CONSTRAINT IF EXISTS secret_keys_application_id_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS secret_keys_application_id_name_key ON secret_keys (application_id, name)
+WHERE revoked_at IS NULL
+	AND expires_at IS NULL;
 CREATE TABLE whitelist_contracts (
 	id SERIAL PRIMARY KEY,
 	application_id VARCHAR NOT NULL,
@@ -184,6 +243,36 @@ CREATE TABLE IF NOT EXISTS lb_apps (
 	CONSTRAINT fk_lb FOREIGN KEY(lb_id) REFERENCES loadbalancers(lb_id),
 	CONSTRAINT fk_app FOREIGN KEY(app_id) REFERENCES applications(application_id)
 );
+-- Relay Usage
+CREATE TABLE IF NOT EXISTS relay_usage (
+	application_id VARCHAR NOT NULL,
+	usage_date DATE NOT NULL,
+	relays BIGINT NOT NULL DEFAULT 0,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (application_id, usage_date),
+	CONSTRAINT fk_application FOREIGN KEY(application_id) REFERENCES applications(application_id)
+);
+CREATE TABLE IF NOT EXISTS usage_threshold_notifications (
+	application_id VARCHAR NOT NULL,
+	usage_date DATE NOT NULL,
+	threshold INT NOT NULL,
+	sent_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (application_id, usage_date, threshold),
+	CONSTRAINT fk_application FOREIGN KEY(application_id) REFERENCES applications(application_id)
+);
+-- Audit Log
+CREATE TABLE IF NOT EXISTS audit_log (
+	id INT GENERATED ALWAYS AS IDENTITY,
+	entity_type VARCHAR NOT NULL,
+	entity_id VARCHAR NOT NULL,
+	action VARCHAR NOT NULL,
+	actor VARCHAR,
+	diff JSONB NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor);
 -- Listener Notification Function
 CREATE OR REPLACE FUNCTION notify_event() RETURNS TRIGGER AS $$
 DECLARE data json;
@@ -209,6 +298,33 @@ PERFORM pg_notify('events', notification::text);
 RETURN NULL;
 END;
 $$ LANGUAGE plpgsql;
+-- Load balancer limits are sent with the daily limit of their plan, which their row only has the type of
+CREATE OR REPLACE FUNCTION notify_lb_limit_event() RETURNS TRIGGER AS $$
+DECLARE data jsonb;
+notification json;
+BEGIN IF (TG_OP = 'DELETE') THEN data = row_to_json(OLD)::jsonb;
+ELSE data = row_to_json(NEW)::jsonb;
+END IF;
+data = data || jsonb_build_object(
+	'daily_limit',
+	(
+		SELECT daily_limit
+		FROM pay_plans
+		WHERE plan_type = data->>'pay_plan'
+	)
+);
+notification = json_build_object(
+	'table',
+	TG_TABLE_NAME,
+	'action',
+	TG_OP,
+	'data',
+	data
+);
+PERFORM pg_notify('events', notification::text);
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
 CREATE TRIGGER user_roles_notify_event
 AFTER
 INSERT
@@ -224,6 +340,12 @@ AFTER
 INSERT
 	OR
 UPDATE ON stickiness_options FOR EACH ROW EXECUTE PROCEDURE notify_event();
+CREATE TRIGGER lb_limits_notify_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON lb_limits FOR EACH ROW EXECUTE PROCEDURE notify_lb_limit_event();
 CREATE TRIGGER user_access_notify_event
 AFTER
 INSERT
@@ -250,6 +372,11 @@ AFTER
 INSERT
 	OR
 UPDATE ON gateway_settings FOR EACH ROW EXECUTE PROCEDURE notify_event();
+CREATE TRIGGER secret_keys_notify_event
+AFTER
+INSERT
+	OR
+UPDATE ON secret_keys FOR EACH ROW EXECUTE PROCEDURE notify_event();
 CREATE TRIGGER whitelist_contracts_notify_event
 AFTER
 INSERT
@@ -276,3 +403,149 @@ INSERT ON redirects FOR EACH ROW EXECUTE PROCEDURE notify_event();
 CREATE TRIGGER sync_check_options_notify_event
 AFTER
 INSERT ON sync_check_options FOR EACH ROW EXECUTE PROCEDURE notify_event();
+-- Audit Log Function
+-- TG_ARGV[0] is the column holding the ID of the audited entity
+CREATE OR REPLACE FUNCTION audit_event() RETURNS TRIGGER AS $$
+DECLARE old_row jsonb = '{}';
+new_row jsonb = '{}';
+secret text;
+diff jsonb;
+BEGIN IF (TG_OP <> 'INSERT') THEN old_row = to_jsonb(OLD) - 'id';
+END IF;
+IF (TG_OP <> 'DELETE') THEN new_row = to_jsonb(NEW) - 'id';
+END IF;
+-- Secrets are hashed so changes to them are visible without storing them
+FOREACH secret IN ARRAY ARRAY ['private_key', 'secret_key'] LOOP IF (old_row ? secret) THEN old_row = old_row || jsonb_build_object(secret, md5(old_row->>secret));
+END IF;
+IF (new_row ? secret) THEN new_row = new_row || jsonb_build_object(secret, md5(new_row->>secret));
+END IF;
+END LOOP;
+-- Keep only the columns that changed
+SELECT jsonb_object_agg(
+		COALESCE(n.key, o.key),
+		jsonb_build_object('before', o.value, 'after', n.value)
+	) INTO diff
+FROM jsonb_each(new_row) n
+	FULL JOIN jsonb_each(old_row) o ON o.key = n.key
+WHERE o.value IS DISTINCT FROM n.value;
+IF (diff IS NULL) THEN RETURN NULL;
+END IF;
+INSERT INTO audit_log (
+		entity_type,
+		entity_id,
+		action,
+		actor,
+		diff,
+		created_at
+	)
+VALUES (
+		TG_TABLE_NAME,
+		CASE
+			WHEN TG_OP = 'DELETE' THEN old_row->>TG_ARGV [0]
+			ELSE new_row->>TG_ARGV [0]
+		END,
+		TG_OP,
+		NULLIF(current_setting('portal.actor', true), ''),
+		diff,
+		NOW()
+	);
+-- Result is ignored since this is an AFTER trigger
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER loadbalancers_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON loadbalancers FOR EACH ROW EXECUTE PROCEDURE audit_event('lb_id');
+CREATE TRIGGER stickiness_options_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON stickiness_options FOR EACH ROW EXECUTE PROCEDURE audit_event('lb_id');
+CREATE TRIGGER lb_limits_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON lb_limits FOR EACH ROW EXECUTE PROCEDURE audit_event('lb_id');
+CREATE TRIGGER user_access_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON user_access FOR EACH ROW EXECUTE PROCEDURE audit_event('lb_id');
+CREATE TRIGGER lb_apps_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON lb_apps FOR EACH ROW EXECUTE PROCEDURE audit_event('lb_id');
+CREATE TRIGGER application_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON applications FOR EACH ROW EXECUTE PROCEDURE audit_event('application_id');
+CREATE TRIGGER app_limits_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON app_limits FOR EACH ROW EXECUTE PROCEDURE audit_event('application_id');
+CREATE TRIGGER gateway_aat_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON gateway_aat FOR EACH ROW EXECUTE PROCEDURE audit_event('application_id');
+CREATE TRIGGER gateway_settings_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON gateway_settings FOR EACH ROW EXECUTE PROCEDURE audit_event('application_id');
+CREATE TRIGGER secret_keys_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON secret_keys FOR EACH ROW EXECUTE PROCEDURE audit_event('application_id');
+CREATE TRIGGER whitelist_contracts_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON whitelist_contracts FOR EACH ROW EXECUTE PROCEDURE audit_event('application_id');
+CREATE TRIGGER whitelist_methods_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON whitelist_methods FOR EACH ROW EXECUTE PROCEDURE audit_event('application_id');
+CREATE TRIGGER notification_settings_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON notification_settings FOR EACH ROW EXECUTE PROCEDURE audit_event('application_id');
+CREATE TRIGGER blockchain_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON blockchains FOR EACH ROW EXECUTE PROCEDURE audit_event('blockchain_id');
+CREATE TRIGGER redirect_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON redirects FOR EACH ROW EXECUTE PROCEDURE audit_event('blockchain_id');
+CREATE TRIGGER sync_check_options_audit_event
+AFTER
+INSERT
+	OR
+UPDATE
+	OR DELETE ON sync_check_options FOR EACH ROW EXECUTE PROCEDURE audit_event('blockchain_id');
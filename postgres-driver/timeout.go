@@ -0,0 +1,86 @@
+package postgresdriver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+/*
+OperationTimeouts holds the statement_timeout and lock_timeout set on a class of operations, zero disables the limit.
+Reads outside of InTx run without a transaction, so only their Statement timeout applies, through their context.
+The Lock timeout of reads is not used by them, only by read-only InTx transactions, as plain reads take no locks.
+*/
+type OperationTimeouts struct {
+	Statement time.Duration
+	Lock      time.Duration
+}
+
+/* Timeouts holds the OperationTimeouts for reads and writes */
+type Timeouts struct {
+	Read  OperationTimeouts
+	Write OperationTimeouts
+}
+
+/* DefaultTimeouts are used by drivers until SetTimeouts is called */
+var DefaultTimeouts = Timeouts{
+	Read: OperationTimeouts{
+		Statement: 10 * time.Second,
+	},
+	Write: OperationTimeouts{
+		Statement: 30 * time.Second,
+		Lock:      10 * time.Second,
+	},
+}
+
+/* SetTimeouts replaces the timeouts of the reads and transactions started after the call, even while others run */
+func (d *PostgresDriver) SetTimeouts(timeouts Timeouts) {
+	d.timeoutsMu.Lock()
+	defer d.timeoutsMu.Unlock()
+
+	d.timeouts = timeouts
+}
+
+/* currentTimeouts returns the timeouts last set by SetTimeouts */
+func (p *PostgresDriver) currentTimeouts() Timeouts {
+	p.timeoutsMu.RLock()
+	defer p.timeoutsMu.RUnlock()
+
+	return p.timeouts
+}
+
+func setLocalTimeouts(ctx context.Context, tx *sql.Tx, timeouts OperationTimeouts) error {
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d; SET LOCAL lock_timeout = %d",
+		timeouts.Statement.Milliseconds(), timeouts.Lock.Milliseconds()))
+
+	return err
+}
+
+/*
+readContext returns ctx limited by the read statement timeout, saving a read the round trips of a transaction.
+Inside InTx the transaction's own timeouts apply, as cancelling a query would abort the whole transaction.
+*/
+func (p *PostgresDriver) readContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := p.currentTimeouts().Read.Statement
+	if p.tx != nil || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+/* translateReadError translates err like translateError, reporting a read stopped by readContext as a statement timeout */
+func translateReadError(ctx, readCtx context.Context, err error) error {
+	err = translateError(readCtx, err)
+
+	var timeoutErr *types.TimeoutError
+	if errors.As(err, &timeoutErr) && timeoutErr.Kind == types.TimeoutContext && ctx.Err() == nil {
+		timeoutErr.Kind = types.TimeoutStatement
+	}
+
+	return err
+}
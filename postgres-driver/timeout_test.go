@@ -0,0 +1,164 @@
+package postgresdriver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func TestTranslateError(t *testing.T) {
+	expiredCtx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	otherErr := errors.New("other")
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		err          error
+		expectedKind types.TimeoutKind
+		expectedErr  error
+	}{
+		{
+			name: "Should return nil for a nil error",
+			ctx:  context.Background(),
+		},
+		{
+			name:         "Should translate a lock timeout",
+			ctx:          context.Background(),
+			err:          &pq.Error{Code: pqLockNotAvailable, Message: "canceling statement due to lock timeout"},
+			expectedKind: types.TimeoutLock,
+		},
+		{
+			name:         "Should translate a statement timeout",
+			ctx:          context.Background(),
+			err:          &pq.Error{Code: pqQueryCanceled, Message: "canceling statement due to statement timeout"},
+			expectedKind: types.TimeoutStatement,
+		},
+		{
+			name:         "Should translate a query cancelled by an expired context",
+			ctx:          expiredCtx,
+			err:          &pq.Error{Code: pqQueryCanceled, Message: "canceling statement due to user request"},
+			expectedKind: types.TimeoutContext,
+		},
+		{
+			name:         "Should translate a context deadline error",
+			ctx:          context.Background(),
+			err:          context.DeadlineExceeded,
+			expectedKind: types.TimeoutContext,
+		},
+		{
+			name:        "Should not translate a query cancelled by the caller",
+			ctx:         context.Background(),
+			err:         &pq.Error{Code: pqQueryCanceled, Message: "canceling statement due to user request"},
+			expectedErr: &pq.Error{Code: pqQueryCanceled, Message: "canceling statement due to user request"},
+		},
+		{
+			name:        "Should not translate other errors",
+			ctx:         context.Background(),
+			err:         otherErr,
+			expectedErr: otherErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			err := translateError(test.ctx, test.err)
+			if test.expectedKind == "" {
+				c.Equal(test.expectedErr, err)
+				return
+			}
+
+			var timeoutErr *types.TimeoutError
+			c.True(errors.As(err, &timeoutErr))
+			c.Equal(test.expectedKind, timeoutErr.Kind)
+			c.ErrorIs(err, types.ErrTimeout)
+			c.ErrorIs(err, test.err)
+		})
+	}
+}
+
+func TestTranslateReadError(t *testing.T) {
+	c := require.New(t)
+
+	expiredCtx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	var timeoutErr *types.TimeoutError
+	err := translateReadError(context.Background(), expiredCtx, context.DeadlineExceeded)
+	c.True(errors.As(err, &timeoutErr))
+	c.Equal(types.TimeoutStatement, timeoutErr.Kind)
+
+	err = translateReadError(expiredCtx, expiredCtx, context.DeadlineExceeded)
+	c.True(errors.As(err, &timeoutErr))
+	c.Equal(types.TimeoutContext, timeoutErr.Kind)
+
+	driver := &PostgresDriver{timeouts: DefaultTimeouts}
+	readCtx, cancel := driver.readContext(context.Background())
+	defer cancel()
+	deadline, ok := readCtx.Deadline()
+	c.True(ok)
+	c.WithinDuration(time.Now().Add(DefaultTimeouts.Read.Statement), deadline, time.Second)
+}
+
+func TestSetTimeouts(t *testing.T) {
+	c := require.New(t)
+
+	driver := &PostgresDriver{timeouts: DefaultTimeouts}
+
+	// Reads may run while the timeouts are replaced
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		driver.SetTimeouts(Timeouts{Read: OperationTimeouts{Statement: time.Minute}})
+	}()
+	_, cancel := driver.readContext(context.Background())
+	cancel()
+	wg.Wait()
+
+	readCtx, cancel := driver.readContext(context.Background())
+	defer cancel()
+	deadline, ok := readCtx.Deadline()
+	c.True(ok)
+	c.WithinDuration(time.Now().Add(time.Minute), deadline, time.Second)
+}
+
+func (ts *PGDriverTestSuite) Test_WriteTimeout() {
+	ts.driver.SetTimeouts(Timeouts{
+		Read:  DefaultTimeouts.Read,
+		Write: OperationTimeouts{Statement: time.Second, Lock: 50 * time.Millisecond},
+	})
+	defer ts.driver.SetTimeouts(DefaultTimeouts)
+
+	lockingTx, err := ts.driver.db.BeginTx(testCtx, nil)
+	ts.NoError(err)
+	defer func() { _ = lockingTx.Rollback() }()
+
+	_, err = lockingTx.ExecContext(testCtx, "SELECT * FROM applications WHERE application_id = 'test_app_47hfnths73j2se' FOR UPDATE")
+	ts.NoError(err)
+
+	err = ts.driver.RemoveApplication(testCtx, "test_app_47hfnths73j2se")
+	ts.ErrorIs(err, types.ErrTimeout)
+
+	var timeoutErr *types.TimeoutError
+	ts.True(errors.As(err, &timeoutErr))
+	ts.Equal(types.TimeoutLock, timeoutErr.Kind)
+
+	cancelledCtx, cancel := context.WithTimeout(testCtx, 50*time.Millisecond)
+	defer cancel()
+
+	ts.driver.SetTimeouts(Timeouts{})
+	err = ts.driver.RemoveApplication(cancelledCtx, "test_app_47hfnths73j2se")
+	ts.ErrorIs(err, types.ErrTimeout)
+	ts.True(errors.As(err, &timeoutErr))
+	ts.Equal(types.TimeoutContext, timeoutErr.Kind)
+}
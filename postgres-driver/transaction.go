@@ -0,0 +1,161 @@
+package postgresdriver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vishruthsk/portal-db-main/driver"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+var (
+	ErrNilTxFunc = types.ErrNilTxFunc
+)
+
+/* transaction wraps a sql.Tx so Writer methods can run on their own or inside InTx, where they use a savepoint */
+type transaction struct {
+	*sql.Tx
+	ctx       context.Context
+	savepoint string
+	done      bool
+}
+
+/*
+InTx runs fn inside a single database transaction, committing if fn returns nil and rolling back otherwise.
+The Writer passed to fn must not be used by several goroutines at once: each of its writes runs in a savepoint
+of the one transaction, and savepoints only nest, releasing one releases every savepoint taken after it.
+*/
+func (p *PostgresDriver) InTx(ctx context.Context, opts *driver.TxOptions, fn func(w driver.Writer) error) error {
+	if fn == nil {
+		return ErrNilTxFunc
+	}
+
+	driverTimeouts := p.currentTimeouts()
+	timeouts := driverTimeouts.Write
+	if opts != nil && opts.ReadOnly {
+		timeouts = driverTimeouts.Read
+	}
+
+	tx, err := p.db.BeginTx(ctx, extractTxOptions(opts))
+	if err != nil {
+		return translateError(ctx, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = setLocalTimeouts(ctx, tx, timeouts)
+	if err != nil {
+		return translateError(ctx, err)
+	}
+
+	txDriver := &PostgresDriver{
+		Queries:      p.WithTx(tx),
+		db:           p.db,
+		notification: p.notification,
+		listener:     p.listener,
+		timeouts:     driverTimeouts,
+		envelope:     p.envelope,
+		tx:           tx,
+		txActor:      types.ActorFromContext(ctx),
+	}
+
+	err = fn(txDriver)
+	if err != nil {
+		return err
+	}
+
+	return translateError(ctx, tx.Commit())
+}
+
+func extractTxOptions(opts *driver.TxOptions) *sql.TxOptions {
+	if opts == nil {
+		return nil
+	}
+
+	return &sql.TxOptions{
+		Isolation: opts.Isolation,
+		ReadOnly:  opts.ReadOnly,
+	}
+}
+
+/*
+beginTx starts a new transaction using the write timeouts, or a savepoint if the driver is bound to one by InTx.
+The actor from ctx, or else the one InTx was called with, is recorded for the audit_event trigger.
+*/
+func (p *PostgresDriver) beginTx(ctx context.Context) (*transaction, error) {
+	t, err := p.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := types.ActorFromContext(ctx)
+	if actor == "" {
+		actor = p.txActor
+	}
+
+	err = setLocalActor(ctx, t.Tx, actor)
+	if err != nil {
+		_ = t.Rollback()
+		return nil, translateError(ctx, err)
+	}
+
+	return t, nil
+}
+
+func (p *PostgresDriver) begin(ctx context.Context) (*transaction, error) {
+	if p.tx == nil {
+		tx, err := p.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, translateError(ctx, err)
+		}
+
+		err = setLocalTimeouts(ctx, tx, p.currentTimeouts().Write)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, translateError(ctx, err)
+		}
+
+		return &transaction{Tx: tx, ctx: ctx}, nil
+	}
+
+	// Not synchronised, InTx does not allow the Writer to be used concurrently
+	p.savepoints++
+	savepoint := fmt.Sprintf("sp_%d", p.savepoints)
+
+	_, err := p.tx.ExecContext(ctx, "SAVEPOINT "+savepoint)
+	if err != nil {
+		return nil, translateError(ctx, err)
+	}
+
+	return &transaction{Tx: p.tx, ctx: ctx, savepoint: savepoint}, nil
+}
+
+/* Commit commits the transaction or releases its savepoint */
+func (t *transaction) Commit() error {
+	if t.savepoint == "" {
+		return t.Tx.Commit()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+
+	t.done = true
+	_, err := t.Tx.ExecContext(t.ctx, "RELEASE SAVEPOINT "+t.savepoint)
+
+	return err
+}
+
+/* Rollback rolls back the transaction or everything since its savepoint */
+func (t *transaction) Rollback() error {
+	if t.savepoint == "" {
+		return t.Tx.Rollback()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+
+	t.done = true
+	_, err := t.Tx.ExecContext(t.ctx, "ROLLBACK TO SAVEPOINT "+t.savepoint)
+
+	return err
+}
@@ -0,0 +1,95 @@
+package postgresdriver
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/vishruthsk/portal-db-main/driver"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func (ts *PGDriverTestSuite) Test_WriteTransaction() {
+	errRollback := errors.New("rollback")
+
+	tests := []struct {
+		name         string
+		opts         *driver.TxOptions
+		failAfterLB  bool
+		failInsideLB bool
+		err          error
+	}{
+		{
+			name: "Should create an application and a load balancer using it atomically",
+			opts: &driver.TxOptions{Isolation: sql.LevelSerializable},
+			err:  nil,
+		},
+		{
+			name:        "Should roll back both writes if the transaction function returns an error",
+			failAfterLB: true,
+			err:         errRollback,
+		},
+		{
+			name:         "Should roll back the application if a later write fails",
+			failInsideLB: true,
+			err:          ErrLBMustHaveUser,
+		},
+	}
+
+	for _, test := range tests {
+		var createdApp *types.Application
+		var createdLB *types.LoadBalancer
+
+		err := ts.driver.InTx(testCtx, test.opts, func(w driver.Writer) error {
+			var err error
+			createdApp, err = w.WriteApplication(testCtx, &types.Application{
+				Name:   "vipr_app_tx",
+				UserID: "test_user_tx1234",
+				Status: types.InService,
+				Limit: types.AppLimit{
+					PayPlan: types.PayPlan{Type: types.FreetierV0},
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			lb := &types.LoadBalancer{
+				Name:           "vipr_lb_tx",
+				UserID:         "test_user_tx1234",
+				ApplicationIDs: []string{createdApp.ID},
+				Users: []types.UserAccess{
+					{UserID: "test_user_tx1234", RoleName: types.RoleOwner, Email: "owner_tx@test.com", Accepted: true},
+				},
+			}
+			if test.failInsideLB {
+				lb.Users = nil
+			}
+
+			createdLB, err = w.WriteLoadBalancer(testCtx, lb)
+			if err != nil {
+				return err
+			}
+
+			if test.failAfterLB {
+				return errRollback
+			}
+
+			return nil
+		})
+		ts.Equal(test.err, err)
+
+		_, appErr := ts.driver.SelectOneApplication(testCtx, createdApp.ID)
+		if test.err == nil {
+			ts.NoError(appErr)
+
+			loadBalancer, err := ts.driver.SelectOneLoadBalancer(testCtx, createdLB.ID)
+			ts.NoError(err)
+			ts.Contains(string(loadBalancer.AppIds), createdApp.ID)
+		} else {
+			ts.ErrorIs(appErr, sql.ErrNoRows)
+		}
+	}
+
+	err := ts.driver.InTx(testCtx, nil, nil)
+	ts.Equal(ErrNilTxFunc, err)
+}
@@ -0,0 +1,342 @@
+package postgresdriver
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+const usageDateLayout = "2006-01-02"
+
+var (
+	ErrNegativeRelays = types.ErrNegativeRelays
+
+	// maxUsageDate is used as the end of the range when a RelayUsageFilter leaves it open
+	maxUsageDate = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+/*
+IncrementUsage atomically adds relays to the Application's usage for the current UTC day
+and returns the day's total, so concurrent gateways never lose a count.
+The first time the usage goes over the Application's DailyLimit its FirstDateSurpassed is set.
+*/
+func (p *PostgresDriver) IncrementUsage(ctx context.Context, appID string, relays int64) (_ int64, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if appID == "" {
+		return 0, ErrMissingID
+	}
+	if relays < 0 {
+		return 0, ErrNegativeRelays
+	}
+
+	now := time.Now()
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	total, err := qtx.IncrementRelayUsage(ctx, IncrementRelayUsageParams{
+		ApplicationID: appID,
+		UsageDate:     types.UsageDay(now),
+		Relays:        relays,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = qtx.MarkFirstDateSurpassed(ctx, MarkFirstDateSurpassedParams{
+		FirstDateSurpassed: newSQLNullTime(now),
+		ApplicationIds:     []string{appID},
+		UsageDates:         []string{types.UsageDay(now).Format(usageDateLayout)},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+/*
+FlushUsage adds a batch of relay counts in a single statement, which is how gateways
+write the usage they buffer in memory. Counts for the same Application and day are summed
+and a zero Date counts as the current UTC day.
+Applications whose usage goes over their DailyLimit for the first time get their FirstDateSurpassed set.
+*/
+func (p *PostgresDriver) FlushUsage(ctx context.Context, usage []types.RelayUsage) (err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	now := time.Now()
+
+	params, err := extractIncrementRelayUsages(usage, now)
+	if err != nil {
+		return err
+	}
+	if len(params.ApplicationIds) == 0 {
+		return nil
+	}
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	err = qtx.IncrementRelayUsages(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	_, err = qtx.MarkFirstDateSurpassed(ctx, MarkFirstDateSurpassedParams{
+		FirstDateSurpassed: newSQLNullTime(now),
+		ApplicationIds:     params.ApplicationIds,
+		UsageDates:         params.UsageDates,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+extractIncrementRelayUsages sums the counts of each Application and day, since a single
+INSERT ... ON CONFLICT cannot update the same row twice, and sorts them so concurrent
+flushes lock rows in the same order
+*/
+func extractIncrementRelayUsages(usage []types.RelayUsage, now time.Time) (IncrementRelayUsagesParams, error) {
+	type usageKey struct {
+		appID string
+		date  string
+	}
+
+	totals := make(map[usageKey]int64)
+	for _, count := range usage {
+		if count.ApplicationID == "" {
+			return IncrementRelayUsagesParams{}, ErrMissingID
+		}
+		if count.Relays < 0 {
+			return IncrementRelayUsagesParams{}, ErrNegativeRelays
+		}
+		if count.Relays == 0 {
+			continue
+		}
+
+		date := count.Date
+		if date.IsZero() {
+			date = now
+		}
+
+		totals[usageKey{appID: count.ApplicationID, date: types.UsageDay(date).Format(usageDateLayout)}] += count.Relays
+	}
+
+	keys := make([]usageKey, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].appID != keys[j].appID {
+			return keys[i].appID < keys[j].appID
+		}
+		return keys[i].date < keys[j].date
+	})
+
+	params := IncrementRelayUsagesParams{UpdatedAt: now}
+	for _, key := range keys {
+		params.ApplicationIds = append(params.ApplicationIds, key.appID)
+		params.UsageDates = append(params.UsageDates, key.date)
+		params.Relays = append(params.Relays, totals[key])
+	}
+
+	return params, nil
+}
+
+/* ReadUsage returns the daily relay usage matching the filter, oldest day first */
+func (p *PostgresDriver) ReadUsage(ctx context.Context, filter types.RelayUsageFilter) (_ []*types.RelayUsage, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	to := filter.To
+	if to.IsZero() {
+		to = maxUsageDate
+	}
+
+	dbUsage, err := p.SelectRelayUsage(readCtx, SelectRelayUsageParams{
+		ApplicationID: filter.ApplicationID,
+		FromDate:      types.UsageDay(filter.From),
+		ToDate:        types.UsageDay(to),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []*types.RelayUsage
+	for _, dbDay := range dbUsage {
+		usage = append(usage, &types.RelayUsage{
+			ApplicationID: dbDay.ApplicationID,
+			Date:          types.UsageDay(dbDay.UsageDate),
+			Relays:        dbDay.Relays,
+		})
+	}
+
+	return usage, nil
+}
+
+/*
+ReadApplicationsOverLimit returns the Applications whose usage today is over their DailyLimit.
+Applications without a limit are never over it.
+*/
+func (p *PostgresDriver) ReadApplicationsOverLimit(ctx context.Context) (_ []*types.ApplicationUsage, err error) {
+	readCtx, cancel := p.readContext(ctx)
+	defer cancel()
+	defer func() { err = translateReadError(ctx, readCtx, err) }()
+
+	today := types.UsageDay(time.Now())
+
+	dbApps, err := p.SelectApplicationsOverLimit(readCtx, today)
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []*types.ApplicationUsage
+	for _, dbApp := range dbApps {
+		app := types.Application{
+			Limit: types.AppLimit{
+				PayPlan: types.PayPlan{
+					Type:  types.PayPlanType(dbApp.PayPlan),
+					Limit: int(dbApp.PlanLimit),
+				},
+				CustomLimit: int(dbApp.CustomLimit.Int32),
+			},
+		}
+
+		apps = append(apps, &types.ApplicationUsage{
+			ApplicationID:      dbApp.ApplicationID,
+			Date:               today,
+			FirstDateSurpassed: dbApp.FirstDateSurpassed.Time,
+			Quota:              app.Quota(dbApp.Relays),
+		})
+	}
+
+	return apps, nil
+}
+
+/*
+EvaluateUsageThresholds returns an event for each threshold of the daily limit the Application's usage
+reached today and that its NotificationSettings opted in to. Thresholds are recorded as sent in the
+same transaction, so each one fires at most once per Application and UTC day even if several
+evaluations run at the same time.
+*/
+func (p *PostgresDriver) EvaluateUsageThresholds(ctx context.Context, appID string) (_ []*types.UsageThresholdEvent, err error) {
+	defer func() { err = translateError(ctx, err) }()
+
+	if appID == "" {
+		return nil, ErrMissingID
+	}
+
+	now := time.Now()
+	today := types.UsageDay(now)
+
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := p.WithTx(tx.Tx)
+
+	dbUsage, err := qtx.SelectApplicationUsage(ctx, SelectApplicationUsageParams{
+		UsageDate:     today,
+		ApplicationID: appID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	app := dbUsage.toApplication()
+	quota := app.Quota(dbUsage.Relays)
+
+	reached := app.NotificationSettings.ReachedThresholds(quota)
+	if len(reached) == 0 {
+		return nil, nil
+	}
+
+	thresholds := make([]int32, 0, len(reached))
+	for _, threshold := range reached {
+		thresholds = append(thresholds, int32(threshold))
+	}
+
+	sent, err := qtx.InsertUsageThresholdNotifications(ctx, InsertUsageThresholdNotificationsParams{
+		ApplicationID: appID,
+		UsageDate:     today,
+		Thresholds:    thresholds,
+		SentAt:        now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(sent, func(i, j int) bool { return sent[i] < sent[j] })
+
+	var events []*types.UsageThresholdEvent
+	for _, threshold := range sent {
+		events = append(events, &types.UsageThresholdEvent{
+			ApplicationID: app.ID,
+			Name:          app.Name,
+			UserID:        app.UserID,
+			ContactEmail:  app.ContactEmail,
+			Date:          today,
+			Threshold:     types.UsageThreshold(threshold),
+			Quota:         quota,
+		})
+	}
+
+	return events, nil
+}
+
+func (a *SelectApplicationUsageRow) toApplication() *types.Application {
+	return &types.Application{
+		ID:           a.ApplicationID,
+		Name:         a.Name.String,
+		UserID:       a.UserID.String,
+		ContactEmail: a.ContactEmail.String,
+		Limit: types.AppLimit{
+			PayPlan: types.PayPlan{
+				Type:  types.PayPlanType(a.PayPlan.String),
+				Limit: int(a.PlanLimit.Int32),
+			},
+			CustomLimit: int(a.CustomLimit.Int32),
+		},
+		NotificationSettings: types.NotificationSettings{
+			Quarter:       a.OnQuarter.Bool,
+			Half:          a.OnHalf.Bool,
+			ThreeQuarters: a.OnThreeQuarters.Bool,
+			Full:          a.OnFull.Bool,
+		},
+	}
+}
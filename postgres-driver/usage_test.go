@@ -0,0 +1,160 @@
+package postgresdriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/portal-db-main/types"
+)
+
+func TestExtractIncrementRelayUsages(t *testing.T) {
+	c := require.New(t)
+	now := time.Date(2022, 11, 12, 1, 0, 0, 0, time.UTC)
+
+	params, err := extractIncrementRelayUsages([]types.RelayUsage{
+		{ApplicationID: "test_app_b", Relays: 5},
+		{ApplicationID: "test_app_a", Date: time.Date(2022, 11, 11, 23, 0, 0, 0, time.UTC), Relays: 10},
+		{ApplicationID: "test_app_b", Date: now.Add(time.Hour), Relays: 15},
+		{ApplicationID: "test_app_a", Relays: 0},
+	}, now)
+	c.NoError(err)
+	c.Equal(IncrementRelayUsagesParams{
+		ApplicationIds: []string{"test_app_a", "test_app_b"},
+		UsageDates:     []string{"2022-11-11", "2022-11-12"},
+		Relays:         []int64{10, 20},
+		UpdatedAt:      now,
+	}, params)
+
+	_, err = extractIncrementRelayUsages([]types.RelayUsage{{Relays: 1}}, now)
+	c.Equal(ErrMissingID, err)
+	_, err = extractIncrementRelayUsages([]types.RelayUsage{{ApplicationID: "test_app_a", Relays: -1}}, now)
+	c.Equal(ErrNegativeRelays, err)
+}
+
+func (ts *PGDriverTestSuite) Test_RelayUsage() {
+	appID := "test_app_47hfnths73j2se"
+	today := types.UsageDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	_, err := ts.driver.IncrementUsage(testCtx, "", 1)
+	ts.Equal(ErrMissingID, err)
+	_, err = ts.driver.IncrementUsage(testCtx, appID, -1)
+	ts.Equal(ErrNegativeRelays, err)
+	_, err = ts.driver.IncrementUsage(testCtx, "test_app_doesnotexist", 1)
+	ts.ErrorIs(err, types.ErrReferenceViolation)
+
+	total, err := ts.driver.IncrementUsage(testCtx, appID, 100)
+	ts.NoError(err)
+	ts.Equal(int64(100), total)
+	total, err = ts.driver.IncrementUsage(testCtx, appID, 50)
+	ts.NoError(err)
+	ts.Equal(int64(150), total)
+
+	err = ts.driver.FlushUsage(testCtx, []types.RelayUsage{
+		{ApplicationID: appID, Relays: 25},
+		{ApplicationID: appID, Date: yesterday, Relays: 1000},
+		{ApplicationID: "test_app_5hdf7sh23jd828", Date: yesterday, Relays: 10},
+	})
+	ts.NoError(err)
+	err = ts.driver.FlushUsage(testCtx, nil)
+	ts.NoError(err)
+
+	usage, err := ts.driver.ReadUsage(testCtx, types.RelayUsageFilter{ApplicationID: appID})
+	ts.NoError(err)
+	ts.Equal([]*types.RelayUsage{
+		{ApplicationID: appID, Date: yesterday, Relays: 1000},
+		{ApplicationID: appID, Date: today, Relays: 175},
+	}, usage)
+
+	usage, err = ts.driver.ReadUsage(testCtx, types.RelayUsageFilter{From: yesterday, To: yesterday})
+	ts.NoError(err)
+	ts.Len(usage, 2)
+	ts.Equal(appID, usage[0].ApplicationID)
+	ts.Equal("test_app_5hdf7sh23jd828", usage[1].ApplicationID)
+
+	usage, err = ts.driver.ReadUsage(testCtx, types.RelayUsageFilter{From: today.AddDate(0, 0, 1)})
+	ts.NoError(err)
+	ts.Empty(usage)
+
+	overLimit, err := ts.driver.ReadApplicationsOverLimit(testCtx)
+	ts.NoError(err)
+	ts.Empty(filterOverLimit(overLimit, appID))
+
+	// Going over the daily limit sets FirstDateSurpassed once
+	err = ts.driver.FlushUsage(testCtx, []types.RelayUsage{{ApplicationID: appID, Relays: 250_000}})
+	ts.NoError(err)
+
+	app, err := ts.driver.SelectOneApplication(testCtx, appID)
+	ts.NoError(err)
+	ts.True(app.FirstDateSurpassed.Valid)
+	firstDateSurpassed := app.FirstDateSurpassed.Time
+
+	_, err = ts.driver.IncrementUsage(testCtx, appID, 1)
+	ts.NoError(err)
+
+	app, err = ts.driver.SelectOneApplication(testCtx, appID)
+	ts.NoError(err)
+	ts.Equal(firstDateSurpassed, app.FirstDateSurpassed.Time)
+
+	overLimit, err = ts.driver.ReadApplicationsOverLimit(testCtx)
+	ts.NoError(err)
+	overLimit = filterOverLimit(overLimit, appID)
+	ts.Len(overLimit, 1)
+	ts.Equal(appID, overLimit[0].ApplicationID)
+	ts.Equal(today, overLimit[0].Date)
+	ts.Equal(firstDateSurpassed.UTC(), overLimit[0].FirstDateSurpassed.UTC())
+	ts.Equal(types.Quota{Limit: 250_000, Used: 250_176, Remaining: 0}, overLimit[0].Quota)
+}
+
+func (ts *PGDriverTestSuite) Test_UsageThresholds() {
+	appID := "test_app_9thr3sh0ld5kq2"
+
+	_, err := ts.driver.EvaluateUsageThresholds(testCtx, "")
+	ts.Equal(ErrMissingID, err)
+	_, err = ts.driver.EvaluateUsageThresholds(testCtx, "test_app_doesnotexist")
+	ts.Equal(types.ErrNotFound, err)
+
+	steps := []struct {
+		relays             int64
+		expectedThresholds []types.UsageThreshold
+	}{
+		{relays: 70_000, expectedThresholds: []types.UsageThreshold{types.ThresholdQuarter}},
+		{relays: 0, expectedThresholds: nil},
+		{relays: 130_000, expectedThresholds: []types.UsageThreshold{types.ThresholdThreeQuarters}},
+		{relays: 60_000, expectedThresholds: []types.UsageThreshold{types.ThresholdFull}},
+	}
+
+	for _, step := range steps {
+		_, err = ts.driver.IncrementUsage(testCtx, appID, step.relays)
+		ts.NoError(err)
+
+		events, err := ts.driver.EvaluateUsageThresholds(testCtx, appID)
+		ts.NoError(err)
+
+		var thresholds []types.UsageThreshold
+		for _, event := range events {
+			ts.Equal(appID, event.ApplicationID)
+			ts.Equal(types.UsageDay(time.Now()), event.Date)
+			ts.Equal(int64(250_000), event.Quota.Limit)
+			thresholds = append(thresholds, event.Threshold)
+		}
+		ts.Equal(step.expectedThresholds, thresholds)
+	}
+
+	events, err := ts.driver.EvaluateUsageThresholds(testCtx, appID)
+	ts.NoError(err)
+	ts.Empty(events)
+}
+
+/* filterOverLimit returns the usages of the application, so other tests' usage doesn't change the result */
+func filterOverLimit(overLimit []*types.ApplicationUsage, appID string) []*types.ApplicationUsage {
+	var filtered []*types.ApplicationUsage
+	for _, usage := range overLimit {
+		if usage.ApplicationID == appID {
+			filtered = append(filtered, usage)
+		}
+	}
+
+	return filtered
+}
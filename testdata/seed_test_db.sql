@@ -38,6 +38,16 @@ VALUES (
         true,
         '2022-11-11 11:11:11.000000',
         '2022-11-11 11:11:11.000000'
+    ),
+    (
+        'test_app_9thr3sh0ld5kq2',
+        'vipr_app_thresholds',
+        'IN_SERVICE',
+        'https://test.app789.io',
+        'test_user_thresholds9876',
+        true,
+        '2022-11-11 11:11:11.000000',
+        '2022-11-11 11:11:11.000000'
     );
 INSERT INTO app_limits (
         application_id,
@@ -53,6 +63,11 @@ VALUES (
         'test_app_5hdf7sh23jd828',
         'ENTERPRISE',
         2000000
+    ),
+    (
+        'test_app_9thr3sh0ld5kq2',
+        'FREETIER_V0',
+        null
     );
 INSERT INTO gateway_aat (
         application_id,
@@ -77,6 +92,14 @@ VALUES (
         'test_1272a8ab4cbbf636f09bf4fa5395b885',
         'test_d709871777b89ed3051190f229ea3f01',
         'test_53e50765d8bc1fb41b3b0065dd8094de'
+    ),
+    (
+        'test_app_9thr3sh0ld5kq2',
+        'test_0d3a9e1f6b7c4d5e8f9a0b1c2d3e4f5a',
+        'test_7e6d5c4b3a2918f7e6d5c4b3a2918f7e',
+        'test_4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e',
+        'test_a1b2c3d4e5f60718293a4b5c6d7e8f90',
+        'test_9f8e7d6c5b4a39281706f5e4d3c2b1a0'
     );
 INSERT INTO gateway_settings (
         application_id,
@@ -92,6 +115,11 @@ VALUES (
         'test_app_5hdf7sh23jd828',
         'test_90210ac4bdd3423e24877d1ff92',
         false
+    ),
+    (
+        'test_app_9thr3sh0ld5kq2',
+        'test_5c1e8a2b7d4f9e3a6b0c1d2e3f4',
+        false
     );
 INSERT INTO notification_settings (
         application_id,
@@ -116,6 +144,38 @@ VALUES (
         false,
         true,
         true
+    ),
+    (
+        'test_app_9thr3sh0ld5kq2',
+        true,
+        true,
+        false,
+        true,
+        true
+    );
+INSERT INTO app_plan_history (
+        application_id,
+        pay_plan,
+        custom_limit,
+        effective_from
+    )
+VALUES (
+        'test_app_47hfnths73j2se',
+        'FREETIER_V0',
+        null,
+        '2022-11-11 11:11:11.000000'
+    ),
+    (
+        'test_app_5hdf7sh23jd828',
+        'ENTERPRISE',
+        2000000,
+        '2022-11-11 11:11:11.000000'
+    ),
+    (
+        'test_app_9thr3sh0ld5kq2',
+        'FREETIER_V0',
+        null,
+        '2022-11-11 11:11:11.000000'
     );
 INSERT INTO loadbalancers (
         lb_id,
@@ -156,6 +216,16 @@ VALUES (
         false,
         '2022-11-11 11:11:11.000000',
         '2022-11-11 11:11:11.000000'
+    ),
+    (
+        'test_lb_9l1m1t3dk2f8s4',
+        'test_user_limit48fj39dk',
+        'test_lb_limit',
+        5000,
+        false,
+        false,
+        '2022-11-11 11:11:11.000000',
+        '2022-11-11 11:11:11.000000'
     );
 INSERT INTO stickiness_options (
         lb_id,
@@ -184,6 +254,13 @@ VALUES (
         600,
         false,
         '{ "test-extension://", "test-extension2://" }'
+    ),
+    (
+        'test_lb_9l1m1t3dk2f8s4',
+        10,
+        100,
+        false,
+        '{ "test-extension://" }'
     );
 INSERT INTO user_access (
         lb_id,
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidStatusTransition = errors.New("invalid app status transition")
+
+type statusOverrideContextKey struct{}
+
+/*
+AppStatusTransitions lists the statuses an app may move to from each status. Apps are funded then staked
+before they are in service, and removed apps wait out their grace period before being unstaked and
+decomissioned, which is final.
+*/
+var AppStatusTransitions = map[AppStatus][]AppStatus{
+	AwaitingFreetierFunds:   {AwaitingFreetierStaking, AwaitingGracePeriod, Decomissioned},
+	AwaitingFreetierStaking: {InService, Ready, AwaitingGracePeriod, Decomissioned},
+	AwaitingFunds:           {AwaitingStaking, AwaitingGracePeriod, Decomissioned},
+	AwaitingStaking:         {InService, Ready, AwaitingGracePeriod, Decomissioned},
+	AwaitingSlotFunds:       {AwaitingSlotStaking, AwaitingGracePeriod, Decomissioned},
+	AwaitingSlotStaking:     {Ready, AwaitingGracePeriod, Decomissioned},
+	Ready:                   {InService, Swappable, AwaitingGracePeriod, AwaitingUnstaking},
+	InService:               {Swappable, Orphaned, AwaitingGracePeriod},
+	Swappable:               {InService, Ready, Orphaned, AwaitingGracePeriod},
+	Orphaned:                {InService, AwaitingGracePeriod, AwaitingUnstaking},
+	AwaitingGracePeriod:     {InService, AwaitingFundsRemoval, AwaitingUnstaking},
+	AwaitingFundsRemoval:    {AwaitingUnstaking, Decomissioned},
+	AwaitingUnstaking:       {Decomissioned},
+	Decomissioned:           {},
+}
+
+/* CanTransitionTo returns true if AppStatusTransitions allows moving from s to next, keeping the same status and leaving an empty status are always allowed */
+func (s AppStatus) CanTransitionTo(next AppStatus) bool {
+	if s == next || s == "" || next == "" {
+		return true
+	}
+
+	for _, allowed := range AppStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+/* ValidateStatusTransition returns an error wrapping ErrInvalidStatusTransition if the app may not move from one status to the other */
+func ValidateStatusTransition(from, to AppStatus) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, from, to)
+	}
+
+	return nil
+}
+
+/*
+WithStatusOverride returns a copy of ctx allowing any status transition in UpdateApplication and RemoveApplication.
+It is meant for admin tooling fixing apps stuck in the wrong status.
+*/
+func WithStatusOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, statusOverrideContextKey{}, true)
+}
+
+/* StatusOverrideFromContext returns true if ctx was returned by WithStatusOverride */
+func StatusOverrideFromContext(ctx context.Context) bool {
+	override, _ := ctx.Value(statusOverrideContextKey{}).(bool)
+	return override
+}
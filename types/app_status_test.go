@@ -0,0 +1,70 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     AppStatus
+		to       AppStatus
+		expected bool
+	}{
+		{name: "Should allow staking a funded app", from: AwaitingFunds, to: AwaitingStaking, expected: true},
+		{name: "Should allow putting a staked app in service", from: AwaitingStaking, to: InService, expected: true},
+		{name: "Should allow removing an app in service", from: InService, to: AwaitingGracePeriod, expected: true},
+		{name: "Should allow restoring an app during its grace period", from: AwaitingGracePeriod, to: InService, expected: true},
+		{name: "Should allow decomissioning an unstaked app", from: AwaitingUnstaking, to: Decomissioned, expected: true},
+		{name: "Should allow keeping the same status", from: Decomissioned, to: Decomissioned, expected: true},
+		{name: "Should allow setting the status of an app without one", from: "", to: Ready, expected: true},
+		{name: "Should allow leaving the status unchanged", from: InService, to: "", expected: true},
+		{name: "Should not allow resurrecting a decomissioned app", from: Decomissioned, to: InService, expected: false},
+		{name: "Should not allow unstaking an app in service without a grace period", from: InService, to: AwaitingUnstaking, expected: false},
+		{name: "Should not allow an app in service back to awaiting funds", from: InService, to: AwaitingFunds, expected: false},
+		{name: "Should not allow skipping staking", from: AwaitingFunds, to: InService, expected: false},
+		{name: "Should not allow moving to an unknown status", from: InService, to: AppStatus("INVALID_STATUS"), expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			c.Equal(test.expected, test.from.CanTransitionTo(test.to))
+
+			err := ValidateStatusTransition(test.from, test.to)
+			if test.expected {
+				c.NoError(err)
+			} else {
+				c.ErrorIs(err, ErrInvalidStatusTransition)
+			}
+		})
+	}
+}
+
+func TestAppStatusTransitions(t *testing.T) {
+	c := require.New(t)
+
+	for status := range ValidAppStatuses {
+		if status == "" {
+			continue
+		}
+
+		nextStatuses, ok := AppStatusTransitions[status]
+		c.True(ok, "missing transitions from %s", status)
+		for _, next := range nextStatuses {
+			c.True(ValidAppStatuses[next], "invalid transition from %s to %s", status, next)
+			c.NotEqual(status, next)
+		}
+	}
+}
+
+func TestStatusOverride(t *testing.T) {
+	c := require.New(t)
+
+	c.False(StatusOverrideFromContext(context.Background()))
+	c.True(StatusOverrideFromContext(WithStatusOverride(context.Background())))
+}
@@ -11,6 +11,7 @@ var (
 	ErrInvalidPayPlanType             = errors.New("invalid pay plan type")
 	ErrNotEnterprisePlan              = errors.New("custom limits may only be set on enterprise plans")
 	ErrEnterprisePlanNeedsCustomLimit = errors.New("enterprise plans must have a custom limit set")
+	ErrSecretKeyIsGenerated           = errors.New("secret keys are generated when the application is created and cannot be set")
 )
 
 type (
@@ -29,6 +30,7 @@ type (
 		GatewaySettings      GatewaySettings      `json:"gatewaySettings"`
 		Limit                AppLimit             `json:"limit"`
 		NotificationSettings NotificationSettings `json:"notificationSettings"`
+		Version              int                  `json:"version"`
 		CreatedAt            time.Time            `json:"createdAt"`
 		UpdatedAt            time.Time            `json:"updatedAt"`
 	}
@@ -45,12 +47,22 @@ type (
 		ID                   string              `json:"id,omitempty"`
 		SecretKey            string              `json:"secretKey"`
 		SecretKeyRequired    bool                `json:"secretKeyRequired"`
+		SecretKeys           []SecretKey         `json:"secretKeys,omitempty"`
 		WhitelistOrigins     []string            `json:"whitelistOrigins,omitempty"`
 		WhitelistUserAgents  []string            `json:"whitelistUserAgents,omitempty"`
 		WhitelistContracts   []WhitelistContract `json:"whitelistContracts,omitempty"`
 		WhitelistMethods     []WhitelistMethod   `json:"whitelistMethods,omitempty"`
 		WhitelistBlockchains []string            `json:"whitelistBlockchains,omitempty"`
 	}
+	/* SecretKey is a named secret key of an Application, several may be valid at once while a key is rotated */
+	SecretKey struct {
+		ID        string    `json:"id,omitempty"`
+		Name      string    `json:"name"`
+		Key       string    `json:"key"`
+		CreatedAt time.Time `json:"createdAt"`
+		ExpiresAt time.Time `json:"expiresAt"`
+		RevokedAt time.Time `json:"revokedAt"`
+	}
 	WhitelistContract struct {
 		ID           string   `json:"id,omitempty"`
 		BlockchainID string   `json:"blockchainID"`
@@ -87,10 +99,11 @@ type (
 		NotificationSettings *UpdateNotificationSettings `json:"notificationSettings,omitempty"`
 		Limit                *AppLimit                   `json:"appLimit,omitempty"`
 		Remove               bool                        `json:"remove,omitempty"`
+		// ExpectedVersion is optional; when set the update is only applied if it matches the stored version
+		ExpectedVersion int `json:"expectedVersion,omitempty"`
 	}
 	UpdateGatewaySettings struct {
 		ID                   string              `json:"id,omitempty"`
-		SecretKey            string              `json:"secretKey"`
 		SecretKeyRequired    *bool               `json:"secretKeyRequired"`
 		WhitelistOrigins     []string            `json:"whitelistOrigins,omitempty"`
 		WhitelistUserAgents  []string            `json:"whitelistUserAgents,omitempty"`
@@ -170,51 +183,113 @@ var (
 )
 
 func (a *Application) DailyLimit() int {
-	if a.Limit.PayPlan.Type == Enterprise {
-		return a.Limit.CustomLimit
+	return a.Limit.DailyLimit()
+}
+
+/* DailyLimit returns the CustomLimit of an enterprise plan and the pay plan's limit otherwise, 0 is unlimited */
+func (l AppLimit) DailyLimit() int {
+	return dailyLimit(l.PayPlan, l.CustomLimit)
+}
+
+func dailyLimit(payPlan PayPlan, customLimit int) int {
+	if payPlan.Type == Enterprise {
+		return customLimit
+	}
+
+	return payPlan.Limit
+}
+
+/* IsRevoked returns whether the SecretKey has been revoked */
+func (k *SecretKey) IsRevoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+/* IsValid returns whether the SecretKey is neither revoked nor expired at the given time */
+func (k *SecretKey) IsValid(now time.Time) bool {
+	if k.IsRevoked() {
+		return false
+	}
+
+	return k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt)
+}
+
+/* ValidSecretKeys returns the named secret keys that are valid at the given time */
+func (s *GatewaySettings) ValidSecretKeys(now time.Time) []SecretKey {
+	var valid []SecretKey
+	for _, key := range s.SecretKeys {
+		if key.IsValid(now) {
+			valid = append(valid, key)
+		}
 	}
 
-	return a.Limit.PayPlan.Limit
+	return valid
 }
 
+/* Validate returns a ValidationError holding every violation of the Application, or nil if it is valid */
 func (a *Application) Validate() error {
+	errs := &ValidationError{}
+
 	if !ValidAppStatuses[a.Status] {
-		return ErrInvalidAppStatus
+		errs.Add("status", ViolationInvalid, ErrInvalidAppStatus)
 	}
 
-	if !ValidPayPlanTypes[a.Limit.PayPlan.Type] {
-		return ErrInvalidPayPlanType
-	}
+	errs.Merge("limit.payPlan", a.Limit.PayPlan.Validate())
 
 	if a.Limit.PayPlan.Type != Enterprise && a.Limit.CustomLimit != 0 {
-		return ErrNotEnterprisePlan
+		errs.Add("limit.customLimit", ViolationNotAllowed, ErrNotEnterprisePlan)
+	}
+
+	if a.GatewaySettings.SecretKey != "" {
+		errs.Add("gatewaySettings.secretKey", ViolationNotAllowed, ErrSecretKeyIsGenerated)
 	}
-	return nil
+	if len(a.GatewaySettings.SecretKeys) != 0 {
+		errs.Add("gatewaySettings.secretKeys", ViolationNotAllowed, ErrSecretKeyIsGenerated)
+	}
+
+	errs.Merge("gatewaySettings", validateWhitelists(a.GatewaySettings.WhitelistOrigins, a.GatewaySettings.WhitelistUserAgents,
+		a.GatewaySettings.WhitelistContracts, a.GatewaySettings.WhitelistMethods))
+
+	return errs.ErrorOrNil()
 }
 
+/* Validate returns a ValidationError holding every violation of the update, or nil if it is valid */
 func (u *UpdateApplication) Validate() error {
 	if u == nil {
 		return ErrNoFieldsToUpdate
 	}
+
+	errs := &ValidationError{}
+
 	if !ValidAppStatuses[u.Status] {
-		return ErrInvalidAppStatus
-	}
-	if u.Limit != nil && !ValidPayPlanTypes[u.Limit.PayPlan.Type] {
-		return ErrInvalidPayPlanType
+		errs.Add("status", ViolationInvalid, ErrInvalidAppStatus)
 	}
-	if u.Limit != nil && u.Limit.PayPlan.Type != Enterprise && u.Limit.CustomLimit != 0 {
-		return ErrNotEnterprisePlan
+
+	if u.Limit != nil {
+		errs.Merge("limit.payPlan", u.Limit.PayPlan.Validate())
+
+		if u.Limit.PayPlan.Type != Enterprise && u.Limit.CustomLimit != 0 {
+			errs.Add("limit.customLimit", ViolationNotAllowed, ErrNotEnterprisePlan)
+		}
+		if u.Limit.PayPlan.Type == Enterprise && u.Limit.CustomLimit == 0 {
+			errs.Add("limit.customLimit", ViolationRequired, ErrEnterprisePlanNeedsCustomLimit)
+		}
 	}
-	if u.Limit != nil && u.Limit.PayPlan.Type == Enterprise && u.Limit.CustomLimit == 0 {
-		return ErrEnterprisePlanNeedsCustomLimit
+
+	if u.GatewaySettings != nil {
+		errs.Merge("gatewaySettings", validateWhitelists(u.GatewaySettings.WhitelistOrigins, u.GatewaySettings.WhitelistUserAgents,
+			u.GatewaySettings.WhitelistContracts, u.GatewaySettings.WhitelistMethods))
 	}
-	return nil
+
+	return errs.ErrorOrNil()
 }
 
+/* Validate returns a ValidationError if the PayPlan's type is unknown */
 func (p *PayPlan) Validate() error {
+	errs := &ValidationError{}
+
 	if !ValidPayPlanTypes[p.Type] {
-		return ErrInvalidPayPlanType
+		errs.Add("type", ViolationInvalid, ErrInvalidPayPlanType)
 	}
 
-	return nil
+	return errs.ErrorOrNil()
 }
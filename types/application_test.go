@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretKey_IsValid(t *testing.T) {
+	now := time.Date(2022, 11, 11, 11, 11, 11, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		key      SecretKey
+		expected bool
+	}{
+		{
+			name:     "Should be valid without an expiry",
+			key:      SecretKey{Name: "current"},
+			expected: true,
+		},
+		{
+			name:     "Should be valid during the overlap",
+			key:      SecretKey{Name: "previous", ExpiresAt: now.Add(time.Minute)},
+			expected: true,
+		},
+		{
+			name:     "Should be invalid once expired",
+			key:      SecretKey{Name: "expired", ExpiresAt: now},
+			expected: false,
+		},
+		{
+			name:     "Should be invalid once revoked",
+			key:      SecretKey{Name: "revoked", RevokedAt: now.Add(-time.Minute)},
+			expected: false,
+		},
+	}
+
+	settings := GatewaySettings{}
+	var expectedValid []SecretKey
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.key.IsValid(now))
+		})
+
+		settings.SecretKeys = append(settings.SecretKeys, test.key)
+		if test.expected {
+			expectedValid = append(expectedValid, test.key)
+		}
+	}
+
+	require.Equal(t, expectedValid, settings.ValidSecretKeys(now))
+}
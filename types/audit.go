@@ -0,0 +1,45 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type actorContextKey struct{}
+
+type (
+	/* AuditLogEntry records a single row change made by a Writer operation */
+	AuditLogEntry struct {
+		ID         int                    `json:"id"`
+		EntityType Table                  `json:"entityType"`
+		EntityID   string                 `json:"entityID"`
+		Action     Action                 `json:"action"`
+		ActorID    string                 `json:"actorID,omitempty"`
+		Diff       map[string]AuditChange `json:"diff"`
+		CreatedAt  time.Time              `json:"createdAt"`
+	}
+	/* AuditChange holds the previous and new value of a changed column, Before is null for inserts and After for deletes */
+	AuditChange struct {
+		Before json.RawMessage `json:"before"`
+		After  json.RawMessage `json:"after"`
+	}
+	/* AuditLogFilter narrows ReadAuditLog results, empty fields match everything and a zero Limit returns all entries */
+	AuditLogFilter struct {
+		EntityType Table  `json:"entityType,omitempty"`
+		EntityID   string `json:"entityID,omitempty"`
+		ActorID    string `json:"actorID,omitempty"`
+		Limit      int    `json:"limit,omitempty"`
+	}
+)
+
+/* WithActor returns a copy of ctx carrying the ID of the user making the change, which is recorded in the audit log */
+func WithActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, userID)
+}
+
+/* ActorFromContext returns the user ID set by WithActor, or an empty string if there is none */
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorContextKey{}).(string)
+	return actor
+}
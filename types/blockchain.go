@@ -1,9 +1,20 @@
 package types
 
 import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
 	"time"
 )
 
+var (
+	ErrInvalidAltruistURL       = errors.New("altruist must be an http or https URL with a host")
+	ErrDuplicateBlockchainAlias = errors.New("blockchain aliases must be unique")
+	ErrInvalidSyncCheckBody     = errors.New("sync check body must be valid JSON")
+	ErrInvalidRedirectDomain    = errors.New("redirect domain must be a valid hostname")
+)
+
 type (
 	Blockchain struct {
 		ID                string           `json:"id"`
@@ -43,3 +54,115 @@ type (
 		Allowance    int    `json:"allowance"`
 	}
 )
+
+/* IsEVM returns whether the Blockchain is EVM compatible, which is when its chain ID is checked with eth_chainId */
+func (b *Blockchain) IsEVM() bool {
+	return strings.Contains(b.ChainIDCheck, "eth_chainId")
+}
+
+/* Validate returns a ValidationError holding every violation of the Blockchain, or nil if it is valid */
+func (b *Blockchain) Validate() error {
+	errs := &ValidationError{}
+
+	if b.ID == "" {
+		errs.Add("id", ViolationRequired, ErrMissingValue)
+	}
+
+	if b.Altruist == "" {
+		errs.Add("altruist", ViolationRequired, ErrMissingValue)
+	} else if !isValidAltruistURL(b.Altruist) {
+		errs.Add("altruist", ViolationInvalid, ErrInvalidAltruistURL)
+	}
+
+	seenAliases := make(map[string]bool, len(b.BlockchainAliases))
+	for _, alias := range b.BlockchainAliases {
+		switch {
+		case alias == "":
+			errs.Add("blockchainAliases", ViolationRequired, ErrMissingValue)
+		case seenAliases[alias]:
+			errs.Add("blockchainAliases", ViolationInvalid, ErrDuplicateBlockchainAlias)
+		}
+		seenAliases[alias] = true
+	}
+
+	if b.LogLimitBlocks < 0 {
+		errs.Add("logLimitBlocks", ViolationInvalid, ErrNegativeValue)
+	}
+	if b.RequestTimeout < 0 {
+		errs.Add("requestTimeout", ViolationInvalid, ErrNegativeValue)
+	}
+	if b.SyncAllowance < 0 {
+		errs.Add("syncAllowance", ViolationInvalid, ErrNegativeValue)
+	}
+
+	errs.Merge("syncCheckOptions", b.SyncCheckOptions.Validate())
+
+	return errs.ErrorOrNil()
+}
+
+/* Validate returns a ValidationError if the body is not valid JSON or the allowance is negative */
+func (s *SyncCheckOptions) Validate() error {
+	errs := &ValidationError{}
+
+	if s.Body != "" && !json.Valid([]byte(s.Body)) {
+		errs.Add("body", ViolationInvalid, ErrInvalidSyncCheckBody)
+	}
+	if s.Allowance < 0 {
+		errs.Add("allowance", ViolationInvalid, ErrNegativeValue)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+/* Validate returns a ValidationError holding every violation of the Redirect, or nil if it is valid */
+func (r *Redirect) Validate() error {
+	errs := &ValidationError{}
+
+	if r.BlockchainID == "" {
+		errs.Add("blockchainID", ViolationRequired, ErrMissingValue)
+	}
+	if r.Alias == "" {
+		errs.Add("alias", ViolationRequired, ErrMissingValue)
+	}
+	if r.LoadBalancerID == "" {
+		errs.Add("loadBalancerID", ViolationRequired, ErrMissingValue)
+	}
+
+	if r.Domain == "" {
+		errs.Add("domain", ViolationRequired, ErrMissingValue)
+	} else if !isValidHostname(r.Domain) {
+		errs.Add("domain", ViolationInvalid, ErrInvalidRedirectDomain)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func isValidAltruistURL(altruist string) bool {
+	altruistURL, err := url.Parse(altruist)
+	if err != nil {
+		return false
+	}
+
+	return (altruistURL.Scheme == "http" || altruistURL.Scheme == "https") && isValidHostname(altruistURL.Hostname())
+}
+
+/* isValidHostname checks the hostname is made of dot separated labels of letters, digits and inner hyphens */
+func isValidHostname(hostname string) bool {
+	if hostname == "" || len(hostname) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(hostname, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, char := range label {
+			isAlphanumeric := (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9')
+			if !isAlphanumeric && char != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
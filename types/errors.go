@@ -0,0 +1,82 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrConflict           = errors.New("conflict: the record was modified since it was last read")
+	ErrTimeout            = errors.New("timeout: the operation did not complete in time")
+	ErrNotFound           = errors.New("not found: no record matches the given ID")
+	ErrAlreadyExists      = errors.New("already exists: a record with the same unique fields exists")
+	ErrReferenceViolation = errors.New("reference violation: the record refers to or is referred to by another record")
+)
+
+// Errors every driver returns for input it rejects before reaching the stored records
+var (
+	ErrMissingID               = errors.New("missing id")
+	ErrNilTxFunc               = errors.New("error: transaction function is nil")
+	ErrNegativeRelays          = errors.New("error: relays cannot be negative")
+	ErrInvalidTimeRange        = errors.New("error: time range cannot start after it ends")
+	ErrMissingSecretKeyName    = errors.New("error: secret key name is missing")
+	ErrNegativeOverlap         = errors.New("error: secret key overlap cannot be negative")
+	ErrSecretKeyNotFound       = errors.New("error: secret key not found or already revoked")
+	ErrUserInputIsMissingField = errors.New("error: user access input is missing a required field")
+	ErrLBMustHaveUser          = errors.New("error: a new load balancer must have at least one user")
+	ErrCannotSetToOwner        = errors.New("error: load balancers may only have one owner and the owner role is already set")
+	ErrUserIsNotOwner          = errors.New("error: user is not the owner of the load balancer")
+	ErrNewOwnerNotAccepted     = errors.New("error: the new owner must be an accepted member of the load balancer")
+	ErrSameOwner               = errors.New("error: the load balancer is already owned by this user")
+	ErrInvitationNotFound      = errors.New("error: invitation not found")
+	ErrInvitationExpired       = errors.New("error: invitation has expired")
+	ErrInvitationUserMismatch  = errors.New("error: invitation was issued to a different user")
+)
+
+// TimeoutKind identifies which limit caused an operation to time out
+type TimeoutKind string
+
+const (
+	TimeoutStatement TimeoutKind = "statement"
+	TimeoutLock      TimeoutKind = "lock"
+	TimeoutContext   TimeoutKind = "context"
+)
+
+// TimeoutError is returned when an operation is aborted by a statement timeout, a lock timeout or the caller's context deadline
+type TimeoutError struct {
+	Kind TimeoutKind
+	Err  error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s: %s limit exceeded: %s", ErrTimeout, e.Kind, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrTimeout) true for every TimeoutError
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// ConstraintError is returned when a write violates a database constraint, Kind is ErrAlreadyExists or ErrReferenceViolation
+type ConstraintError struct {
+	Kind       error
+	Constraint string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%s: constraint %s", e.Kind, e.Constraint)
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, Kind) true so callers can match on the sentinel without knowing the constraint
+func (e *ConstraintError) Is(target error) bool {
+	return target == e.Kind
+}
@@ -15,6 +15,7 @@ const (
 	TableLoadBalancers     Table = "loadbalancers"
 	TableStickinessOptions Table = "stickiness_options"
 	TableUserAccess        Table = "user_access"
+	TableLbLimits          Table = "lb_limits"
 
 	TableLbApps Table = "lb_apps"
 
@@ -22,6 +23,7 @@ const (
 	TableAppLimits            Table = "app_limits"
 	TableGatewayAAT           Table = "gateway_aat"
 	TableGatewaySettings      Table = "gateway_settings"
+	TableSecretKeys           Table = "secret_keys"
 	TableWhitelistContracts   Table = "whitelist_contracts"
 	TableWhitelistMethods     Table = "whitelist_methods"
 	TableNotificationSettings Table = "notification_settings"
@@ -35,6 +37,28 @@ const (
 	ActionDelete Action = "DELETE"
 )
 
+/* NotifiedActions lists the actions each table's notify trigger fires on, other changes send no Notification */
+var NotifiedActions = map[Table]map[Action]bool{
+	TableLoadBalancers:     {ActionInsert: true, ActionUpdate: true},
+	TableStickinessOptions: {ActionInsert: true, ActionUpdate: true},
+	TableLbLimits:          {ActionInsert: true, ActionUpdate: true, ActionDelete: true},
+	TableUserAccess:        {ActionInsert: true, ActionUpdate: true},
+	TableLbApps:            {ActionInsert: true},
+
+	TableApplications:         {ActionInsert: true, ActionUpdate: true},
+	TableAppLimits:            {ActionInsert: true, ActionUpdate: true},
+	TableGatewayAAT:           {ActionInsert: true},
+	TableGatewaySettings:      {ActionInsert: true, ActionUpdate: true},
+	TableSecretKeys:           {ActionInsert: true, ActionUpdate: true},
+	TableWhitelistContracts:   {ActionInsert: true, ActionUpdate: true},
+	TableWhitelistMethods:     {ActionInsert: true, ActionUpdate: true},
+	TableNotificationSettings: {ActionInsert: true, ActionUpdate: true},
+
+	TableBlockchains:      {ActionInsert: true, ActionUpdate: true},
+	TableRedirects:        {ActionInsert: true},
+	TableSyncCheckOptions: {ActionInsert: true},
+}
+
 type SavedOnDB interface {
 	Table() Table
 }
@@ -48,6 +72,9 @@ func (s *StickyOptions) Table() Table {
 func (s *UserAccess) Table() Table {
 	return TableUserAccess
 }
+func (l *LBLimit) Table() Table {
+	return TableLbLimits
+}
 
 func (l *LbApp) Table() Table {
 	return TableLbApps
@@ -62,6 +89,9 @@ func (a *GatewayAAT) Table() Table {
 func (s *GatewaySettings) Table() Table {
 	return TableGatewaySettings
 }
+func (k *SecretKey) Table() Table {
+	return TableSecretKeys
+}
 func (s *WhitelistContract) Table() Table {
 	return TableWhitelistContracts
 }
@@ -1,10 +1,17 @@
 package types
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 )
 
+var (
+	ErrInvalidStickyDuration = errors.New("sticky duration must be a number of seconds or a duration such as 1m30s")
+	ErrLimitSetAndRemoved    = errors.New("limit cannot be set and removed in the same update")
+)
+
 /* LB Apps Table represents DB relationship of LBs and apps */
 // do not change the tags, they're snake_case on purpose
 type LbApp struct {
@@ -23,8 +30,10 @@ type (
 		Gigastake         bool           `json:"gigastake"`
 		GigastakeRedirect bool           `json:"gigastakeRedirect"`
 		StickyOptions     StickyOptions  `json:"stickinessOptions"`
+		Limit             *LBLimit       `json:"limit,omitempty"`
 		Applications      []*Application `json:"applications"`
 		Users             []UserAccess   `json:"users"`
+		Version           int            `json:"version"`
 		CreatedAt         time.Time      `json:"createdAt"`
 		UpdatedAt         time.Time      `json:"updatedAt"`
 	}
@@ -35,6 +44,12 @@ type (
 		StickyMax     int      `json:"stickyMax"`
 		Stickiness    bool     `json:"stickiness"`
 	}
+	/* LBLimit is an optional daily limit shared by all the Applications of a LoadBalancer */
+	LBLimit struct {
+		ID          string  `json:"id,omitempty"`
+		PayPlan     PayPlan `json:"payPlan"`
+		CustomLimit int     `json:"customLimit"`
+	}
 	UserAccess struct {
 		ID       string   `json:"id,omitempty"`
 		UserID   string   `json:"userID"`
@@ -42,11 +57,24 @@ type (
 		Email    string   `json:"email"`
 		Accepted bool     `json:"accepted"`
 	}
+	Invitation struct {
+		LbID      string    `json:"lbID"`
+		UserID    string    `json:"userID,omitempty"`
+		RoleName  RoleName  `json:"roleName"`
+		Email     string    `json:"email"`
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expiresAt"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
 	/* Update structs */
 	UpdateLoadBalancer struct {
 		Name          string               `json:"name,omitempty"`
 		StickyOptions *UpdateStickyOptions `json:"stickinessOptions,omitempty"`
+		Limit         *LBLimit             `json:"limit,omitempty"`
+		RemoveLimit   bool                 `json:"removeLimit,omitempty"`
 		Remove        bool                 `json:"remove,omitempty"`
+		// ExpectedVersion is optional; when set the update is only applied if it matches the stored version
+		ExpectedVersion int `json:"expectedVersion,omitempty"`
 	}
 	UpdateStickyOptions struct {
 		ID            string   `json:"id,omitempty"`
@@ -59,6 +87,8 @@ type (
 		ID       string   `json:"id,omitempty"`
 		UserID   string   `json:"userID"`
 		RoleName RoleName `json:"roleName"`
+		// ExpectedVersion is the version of the load balancer the user belongs to, it is optional
+		ExpectedVersion int `json:"expectedVersion,omitempty"`
 	}
 
 	RoleName        string
@@ -99,9 +129,177 @@ func (e *PermissionsEnum) Scan(src interface{}) error {
 	return nil
 }
 
+func (i *Invitation) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
 func (s *StickyOptions) IsEmpty() bool {
 	if !s.Stickiness {
 		return true
 	}
 	return len(s.StickyOrigins) == 0
 }
+
+/* Validate returns a ValidationError holding every violation of the LoadBalancer, or nil if it is valid */
+func (l *LoadBalancer) Validate() error {
+	errs := &ValidationError{}
+
+	if l.RequestTimeout < 0 {
+		errs.Add("requestTimeout", ViolationInvalid, ErrNegativeValue)
+	}
+
+	errs.Merge("stickinessOptions", l.StickyOptions.Validate())
+
+	if l.Limit != nil {
+		errs.Merge("limit", l.Limit.Validate())
+	}
+
+	return errs.ErrorOrNil()
+}
+
+/* Validate returns a ValidationError if the duration cannot be parsed or StickyMax is negative */
+func (s *StickyOptions) Validate() error {
+	return validateStickyOptions(s.Duration, s.StickyMax)
+}
+
+/* Validate returns a ValidationError holding every violation of the update, or nil if it is valid */
+func (u *UpdateLoadBalancer) Validate() error {
+	if u == nil {
+		return ErrNoFieldsToUpdate
+	}
+
+	errs := &ValidationError{}
+
+	if u.StickyOptions != nil {
+		errs.Merge("stickinessOptions", validateStickyOptions(u.StickyOptions.Duration, u.StickyOptions.StickyMax))
+	}
+	if u.Limit != nil {
+		errs.Merge("limit", u.Limit.Validate())
+
+		if u.RemoveLimit {
+			errs.Add("removeLimit", ViolationNotAllowed, ErrLimitSetAndRemoved)
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func validateStickyOptions(duration string, stickyMax int) error {
+	errs := &ValidationError{}
+
+	if duration != "" {
+		if _, err := ParseStickyDuration(duration); err != nil {
+			errs.Add("duration", ViolationInvalid, err)
+		}
+	}
+	if stickyMax < 0 {
+		errs.Add("stickyMax", ViolationInvalid, ErrNegativeValue)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+/*
+ParseStickyDuration parses a sticky options duration, stored either as a whole number of seconds
+such as "60" or as a Go duration such as "1m30s". Negative durations are rejected.
+*/
+func ParseStickyDuration(duration string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(duration); err == nil {
+		if seconds < 0 {
+			return 0, ErrNegativeValue
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	parsed, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, ErrInvalidStickyDuration
+	}
+	if parsed < 0 {
+		return 0, ErrNegativeValue
+	}
+
+	return parsed, nil
+}
+
+/* Validate returns a ValidationError if the pay plan is unknown or the custom limit does not match it */
+func (l *LBLimit) Validate() error {
+	errs := &ValidationError{}
+
+	errs.Merge("payPlan", l.PayPlan.Validate())
+
+	if l.PayPlan.Type != Enterprise && l.CustomLimit != 0 {
+		errs.Add("customLimit", ViolationNotAllowed, ErrNotEnterprisePlan)
+	}
+	if l.PayPlan.Type == Enterprise && l.CustomLimit == 0 {
+		errs.Add("customLimit", ViolationRequired, ErrEnterprisePlanNeedsCustomLimit)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+/* DailyLimit returns the CustomLimit of an enterprise plan and the pay plan's limit otherwise, 0 is unlimited */
+func (l LBLimit) DailyLimit() int {
+	return dailyLimit(l.PayPlan, l.CustomLimit)
+}
+
+/* DailyLimit returns the daily limit shared by the LoadBalancer's Applications, 0 is unlimited or no limit set */
+func (l *LoadBalancer) DailyLimit() int {
+	if l.Limit == nil {
+		return 0
+	}
+
+	return l.Limit.DailyLimit()
+}
+
+/* Quota returns how many relays all the LoadBalancer's Applications have left for the day after using used relays */
+func (l *LoadBalancer) Quota(used int64) Quota {
+	return newQuota(l.DailyLimit(), used)
+}
+
+/*
+EffectiveDailyLimit returns the daily limit a relay of the Application sent through the LoadBalancer is checked against,
+which is the stricter of the Application's DailyLimit and the LoadBalancer's limit. 0 is unlimited.
+The LoadBalancer's limit is shared, so the Application may run out earlier, use EffectiveQuota when usage is known.
+*/
+func EffectiveDailyLimit(lb *LoadBalancer, app *Application) int {
+	appLimit, lbLimit := app.DailyLimit(), 0
+	if lb != nil {
+		lbLimit = lb.DailyLimit()
+	}
+
+	switch {
+	case appLimit <= 0:
+		return lbLimit
+	case lbLimit <= 0:
+		return appLimit
+	case lbLimit < appLimit:
+		return lbLimit
+	default:
+		return appLimit
+	}
+}
+
+/*
+EffectiveQuota returns the quota a relay of the Application sent through the LoadBalancer is checked against.
+appUsed is the Application's usage today and lbUsed the usage of all the LoadBalancer's Applications,
+the quota with the fewest relays left applies.
+*/
+func EffectiveQuota(lb *LoadBalancer, app *Application, appUsed, lbUsed int64) Quota {
+	appQuota := app.Quota(appUsed)
+	if lb == nil {
+		return appQuota
+	}
+
+	lbQuota := lb.Quota(lbUsed)
+	switch {
+	case lbQuota.Unlimited:
+		return appQuota
+	case appQuota.Unlimited:
+		return lbQuota
+	case lbQuota.Remaining < appQuota.Remaining:
+		return lbQuota
+	default:
+		return appQuota
+	}
+}
@@ -0,0 +1,235 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBalancer_Validate(t *testing.T) {
+	tests := []struct {
+		name           string
+		loadBalancer   *LoadBalancer
+		expectedFields []string
+		expectedErrs   []error
+	}{
+		{
+			name: "Should pass a valid load balancer",
+			loadBalancer: &LoadBalancer{
+				RequestTimeout: 5000,
+				StickyOptions:  StickyOptions{Duration: "60", StickyMax: 300, Stickiness: true},
+			},
+		},
+		{
+			name:         "Should pass a load balancer without sticky options",
+			loadBalancer: &LoadBalancer{},
+		},
+		{
+			name:         "Should pass a load balancer with an enterprise limit",
+			loadBalancer: &LoadBalancer{Limit: &LBLimit{PayPlan: PayPlan{Type: Enterprise}, CustomLimit: 5_000_000}},
+		},
+		{
+			name: "Should collect every violation",
+			loadBalancer: &LoadBalancer{
+				RequestTimeout: -1,
+				StickyOptions:  StickyOptions{Duration: "a minute", StickyMax: -1},
+				Limit:          &LBLimit{PayPlan: PayPlan{Type: Enterprise}},
+			},
+			expectedFields: []string{"requestTimeout", "stickinessOptions.duration", "stickinessOptions.stickyMax", "limit.customLimit"},
+			expectedErrs:   []error{ErrNegativeValue, ErrInvalidStickyDuration, ErrEnterprisePlanNeedsCustomLimit},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			err := test.loadBalancer.Validate()
+			if test.expectedErrs == nil {
+				c.NoError(err)
+				return
+			}
+
+			for _, expectedErr := range test.expectedErrs {
+				c.ErrorIs(err, expectedErr)
+			}
+			c.Equal(test.expectedFields, violationFields(err))
+		})
+	}
+}
+
+func TestUpdateLoadBalancer_Validate(t *testing.T) {
+	tests := []struct {
+		name           string
+		update         *UpdateLoadBalancer
+		expectedFields []string
+		expectedErrs   []error
+	}{
+		{
+			name:   "Should pass an update without sticky options",
+			update: &UpdateLoadBalancer{Name: "vipr_app_updated"},
+		},
+		{
+			name:   "Should pass a partial sticky options update",
+			update: &UpdateLoadBalancer{StickyOptions: &UpdateStickyOptions{StickyOrigins: []string{"chrome-extension://"}}},
+		},
+		{
+			name:   "Should pass an update removing the limit",
+			update: &UpdateLoadBalancer{RemoveLimit: true},
+		},
+		{
+			name:         "Should fail without an update",
+			expectedErrs: []error{ErrNoFieldsToUpdate},
+		},
+		{
+			name: "Should fail when the limit is set and removed",
+			update: &UpdateLoadBalancer{
+				Limit:       &LBLimit{PayPlan: PayPlan{Type: PayPlanType("INVALID_PAY_PLAN")}, CustomLimit: 10},
+				RemoveLimit: true,
+			},
+			expectedFields: []string{"limit.payPlan.type", "limit.customLimit", "removeLimit"},
+			expectedErrs:   []error{ErrInvalidPayPlanType, ErrNotEnterprisePlan, ErrLimitSetAndRemoved},
+		},
+		{
+			name:           "Should fail with invalid sticky options",
+			update:         &UpdateLoadBalancer{StickyOptions: &UpdateStickyOptions{Duration: "-30", StickyMax: -1}},
+			expectedFields: []string{"stickinessOptions.duration", "stickinessOptions.stickyMax"},
+			expectedErrs:   []error{ErrNegativeValue},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			err := test.update.Validate()
+			if test.expectedErrs == nil {
+				c.NoError(err)
+				return
+			}
+
+			for _, expectedErr := range test.expectedErrs {
+				c.ErrorIs(err, expectedErr)
+			}
+			c.Equal(test.expectedFields, violationFields(err))
+		})
+	}
+}
+
+func TestParseStickyDuration(t *testing.T) {
+	tests := []struct {
+		name             string
+		duration         string
+		expectedDuration time.Duration
+		err              error
+	}{
+		{
+			name:             "Should parse a number of seconds",
+			duration:         "60",
+			expectedDuration: time.Minute,
+		},
+		{
+			name:             "Should parse a Go duration",
+			duration:         "1m30s",
+			expectedDuration: 90 * time.Second,
+		},
+		{
+			name:     "Should fail with a negative number of seconds",
+			duration: "-60",
+			err:      ErrNegativeValue,
+		},
+		{
+			name:     "Should fail with a negative Go duration",
+			duration: "-1m",
+			err:      ErrNegativeValue,
+		},
+		{
+			name:     "Should fail with an unparseable duration",
+			duration: "a minute",
+			err:      ErrInvalidStickyDuration,
+		},
+		{
+			name:     "Should fail with a fractional number of seconds",
+			duration: "1.5",
+			err:      ErrInvalidStickyDuration,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			duration, err := ParseStickyDuration(test.duration)
+			c.Equal(test.err, err)
+			c.Equal(test.expectedDuration, duration)
+		})
+	}
+}
+
+func TestEffectiveQuota(t *testing.T) {
+	freetierApp := &Application{Limit: AppLimit{PayPlan: PayPlan{Type: FreetierV0, Limit: 250_000}}}
+	unlimitedApp := &Application{Limit: AppLimit{PayPlan: PayPlan{Type: PayAsYouGoV0}}}
+	sharedLB := &LoadBalancer{Limit: &LBLimit{PayPlan: PayPlan{Type: Enterprise}, CustomLimit: 1_000_000}}
+
+	tests := []struct {
+		name          string
+		lb            *LoadBalancer
+		app           *Application
+		appUsed       int64
+		lbUsed        int64
+		expectedLimit int
+		expectedQuota Quota
+	}{
+		{
+			name:          "Should use the application limit without a load balancer",
+			app:           freetierApp,
+			appUsed:       1000,
+			expectedLimit: 250_000,
+			expectedQuota: Quota{Limit: 250_000, Used: 1000, Remaining: 249_000},
+		},
+		{
+			name:          "Should use the application limit when the load balancer has none",
+			lb:            &LoadBalancer{},
+			app:           freetierApp,
+			appUsed:       1000,
+			lbUsed:        5000,
+			expectedLimit: 250_000,
+			expectedQuota: Quota{Limit: 250_000, Used: 1000, Remaining: 249_000},
+		},
+		{
+			name:          "Should use the load balancer limit when the application is unlimited",
+			lb:            sharedLB,
+			app:           unlimitedApp,
+			appUsed:       1000,
+			lbUsed:        5000,
+			expectedLimit: 1_000_000,
+			expectedQuota: Quota{Limit: 1_000_000, Used: 5000, Remaining: 995_000},
+		},
+		{
+			name:          "Should use the load balancer quota once its shared usage leaves fewer relays",
+			lb:            sharedLB,
+			app:           freetierApp,
+			appUsed:       1000,
+			lbUsed:        900_000,
+			expectedLimit: 250_000,
+			expectedQuota: Quota{Limit: 1_000_000, Used: 900_000, Remaining: 100_000},
+		},
+		{
+			name:          "Should be unlimited when neither has a limit",
+			lb:            &LoadBalancer{},
+			app:           unlimitedApp,
+			appUsed:       1000,
+			expectedQuota: Quota{Used: 1000, Unlimited: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			c.Equal(test.expectedLimit, EffectiveDailyLimit(test.lb, test.app))
+			c.Equal(test.expectedQuota, EffectiveQuota(test.lb, test.app, test.appUsed, test.lbUsed))
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+type (
+	/* AppPlanPeriod is the pay plan and limit an Application was on between two times, EffectiveTo is zero for the current plan */
+	AppPlanPeriod struct {
+		ApplicationID string    `json:"applicationID"`
+		Limit         AppLimit  `json:"limit"`
+		EffectiveFrom time.Time `json:"effectiveFrom"`
+		EffectiveTo   time.Time `json:"effectiveTo,omitempty"`
+	}
+)
+
+/* IsCurrent returns whether the period has not ended */
+func (p *AppPlanPeriod) IsCurrent() bool {
+	return p.EffectiveTo.IsZero()
+}
+
+/* Contains returns whether t falls within the period, which includes EffectiveFrom and excludes EffectiveTo */
+func (p *AppPlanPeriod) Contains(t time.Time) bool {
+	return !t.Before(p.EffectiveFrom) && (p.IsCurrent() || t.Before(p.EffectiveTo))
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppPlanPeriod_Contains(t *testing.T) {
+	c := require.New(t)
+	start := time.Date(2022, 11, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	ended := &AppPlanPeriod{EffectiveFrom: start, EffectiveTo: end}
+	c.False(ended.IsCurrent())
+	c.True(ended.Contains(start))
+	c.True(ended.Contains(end.Add(-time.Second)))
+	c.False(ended.Contains(end))
+	c.False(ended.Contains(start.Add(-time.Second)))
+
+	current := &AppPlanPeriod{EffectiveFrom: start}
+	c.True(current.IsCurrent())
+	c.True(current.Contains(end.AddDate(10, 0, 0)))
+	c.False(current.Contains(start.Add(-time.Second)))
+}
+
+func TestAppLimit_DailyLimit(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal(250_000, AppLimit{PayPlan: PayPlan{Type: FreetierV0, Limit: 250_000}, CustomLimit: 10}.DailyLimit())
+	c.Equal(2_000_000, AppLimit{PayPlan: PayPlan{Type: Enterprise}, CustomLimit: 2_000_000}.DailyLimit())
+}
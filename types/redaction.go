@@ -0,0 +1,214 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RedactedSecret replaces non-empty secrets when Applications, GatewayAATs, GatewaySettings and SecretKeys are serialised
+const RedactedSecret = "[REDACTED]"
+
+type (
+	/* GatewayAATWithSecrets serialises the wrapped GatewayAAT including its private key */
+	GatewayAATWithSecrets struct {
+		*GatewayAAT
+	}
+	/* GatewaySettingsWithSecrets serialises the wrapped GatewaySettings including its secret keys */
+	GatewaySettingsWithSecrets struct {
+		*GatewaySettings
+	}
+	/* SecretKeyWithSecrets serialises the wrapped SecretKey including its key */
+	SecretKeyWithSecrets struct {
+		*SecretKey
+	}
+	/* ApplicationWithSecrets serialises the wrapped Application including its private key and secret key */
+	ApplicationWithSecrets struct {
+		*Application
+	}
+	/* LoadBalancerWithSecrets serialises the wrapped LoadBalancer including the secrets of its Applications */
+	LoadBalancerWithSecrets struct {
+		*LoadBalancer
+	}
+
+	// Aliases without the redacting methods, used to avoid infinite recursion
+	gatewayAAT      GatewayAAT
+	gatewaySettings GatewaySettings
+	secretKey       SecretKey
+	application     Application
+	loadBalancer    LoadBalancer
+)
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return RedactedSecret
+}
+
+/* Redacted returns a copy of the GatewayAAT with the private key replaced by RedactedSecret */
+func (a GatewayAAT) Redacted() GatewayAAT {
+	a.PrivateKey = redact(a.PrivateKey)
+	return a
+}
+
+/* MarshalJSON serialises the GatewayAAT with its private key redacted, use WithSecrets to include it */
+func (a GatewayAAT) MarshalJSON() ([]byte, error) {
+	return json.Marshal(gatewayAAT(a.Redacted()))
+}
+
+/* String formats the GatewayAAT with its private key redacted so it is safe to log */
+func (a GatewayAAT) String() string {
+	return fmt.Sprintf("%+v", gatewayAAT(a.Redacted()))
+}
+
+/* WithSecrets explicitly opts in to serialising the private key */
+func (a *GatewayAAT) WithSecrets() GatewayAATWithSecrets {
+	return GatewayAATWithSecrets{GatewayAAT: a}
+}
+
+func (a GatewayAATWithSecrets) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*gatewayAAT)(a.GatewayAAT))
+}
+
+/* Redacted returns a copy of the GatewaySettings with the secret key and named keys replaced by RedactedSecret */
+func (s GatewaySettings) Redacted() GatewaySettings {
+	s.SecretKey = redact(s.SecretKey)
+
+	if s.SecretKeys != nil {
+		secretKeys := make([]SecretKey, 0, len(s.SecretKeys))
+		for _, key := range s.SecretKeys {
+			secretKeys = append(secretKeys, key.Redacted())
+		}
+		s.SecretKeys = secretKeys
+	}
+
+	return s
+}
+
+/* MarshalJSON serialises the GatewaySettings with its secret key redacted, use WithSecrets to include it */
+func (s GatewaySettings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(gatewaySettings(s.Redacted()))
+}
+
+/* String formats the GatewaySettings with its secret key redacted so it is safe to log */
+func (s GatewaySettings) String() string {
+	return fmt.Sprintf("%+v", gatewaySettings(s.Redacted()))
+}
+
+/* WithSecrets explicitly opts in to serialising the secret key and named keys */
+func (s *GatewaySettings) WithSecrets() GatewaySettingsWithSecrets {
+	return GatewaySettingsWithSecrets{GatewaySettings: s}
+}
+
+func (s GatewaySettingsWithSecrets) MarshalJSON() ([]byte, error) {
+	var secretKeys []SecretKeyWithSecrets
+	for i := range s.SecretKeys {
+		secretKeys = append(secretKeys, s.SecretKeys[i].WithSecrets())
+	}
+
+	return json.Marshal(struct {
+		*gatewaySettings
+		SecretKeys []SecretKeyWithSecrets `json:"secretKeys,omitempty"`
+	}{
+		gatewaySettings: (*gatewaySettings)(s.GatewaySettings),
+		SecretKeys:      secretKeys,
+	})
+}
+
+/* Redacted returns a copy of the SecretKey with the key replaced by RedactedSecret */
+func (k SecretKey) Redacted() SecretKey {
+	k.Key = redact(k.Key)
+	return k
+}
+
+/* MarshalJSON serialises the SecretKey with its key redacted, use WithSecrets to include it */
+func (k SecretKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(secretKey(k.Redacted()))
+}
+
+/* String formats the SecretKey with its key redacted so it is safe to log */
+func (k SecretKey) String() string {
+	return fmt.Sprintf("%+v", secretKey(k.Redacted()))
+}
+
+/* WithSecrets explicitly opts in to serialising the key */
+func (k *SecretKey) WithSecrets() SecretKeyWithSecrets {
+	return SecretKeyWithSecrets{SecretKey: k}
+}
+
+func (k SecretKeyWithSecrets) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*secretKey)(k.SecretKey))
+}
+
+/* Redacted returns a copy of the Application with its private key and secret key replaced by RedactedSecret */
+func (a *Application) Redacted() *Application {
+	redacted := *a
+	redacted.GatewayAAT = a.GatewayAAT.Redacted()
+	redacted.GatewaySettings = a.GatewaySettings.Redacted()
+
+	return &redacted
+}
+
+/* WithSecrets explicitly opts in to serialising the private key and secret key */
+func (a *Application) WithSecrets() ApplicationWithSecrets {
+	return ApplicationWithSecrets{Application: a}
+}
+
+func (a ApplicationWithSecrets) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*application
+		GatewayAAT      GatewayAATWithSecrets      `json:"gatewayAAT"`
+		GatewaySettings GatewaySettingsWithSecrets `json:"gatewaySettings"`
+	}{
+		application:     (*application)(a.Application),
+		GatewayAAT:      a.GatewayAAT.WithSecrets(),
+		GatewaySettings: a.GatewaySettings.WithSecrets(),
+	})
+}
+
+/* WithSecrets explicitly opts in to serialising the secrets of the LoadBalancer's Applications */
+func (lb *LoadBalancer) WithSecrets() LoadBalancerWithSecrets {
+	return LoadBalancerWithSecrets{LoadBalancer: lb}
+}
+
+func (lb LoadBalancerWithSecrets) MarshalJSON() ([]byte, error) {
+	var applications []ApplicationWithSecrets
+	if lb.Applications != nil {
+		applications = make([]ApplicationWithSecrets, 0, len(lb.Applications))
+	}
+	for _, app := range lb.Applications {
+		applications = append(applications, app.WithSecrets())
+	}
+
+	return json.Marshal(struct {
+		*loadBalancer
+		Applications []ApplicationWithSecrets `json:"applications"`
+	}{
+		loadBalancer: (*loadBalancer)(lb.LoadBalancer),
+		Applications: applications,
+	})
+}
+
+/*
+WithSecrets returns a copy of the Notification whose Data serialises its secrets.
+The Data of the copy is wrapped, so type switches should be done on the original Notification.
+*/
+func (n *Notification) WithSecrets() *Notification {
+	withSecrets := *n
+
+	switch data := n.Data.(type) {
+	case *Application:
+		withSecrets.Data = data.WithSecrets()
+	case *GatewayAAT:
+		withSecrets.Data = data.WithSecrets()
+	case *GatewaySettings:
+		withSecrets.Data = data.WithSecrets()
+	case *SecretKey:
+		withSecrets.Data = data.WithSecrets()
+	case *LoadBalancer:
+		withSecrets.Data = data.WithSecrets()
+	}
+
+	return &withSecrets
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testApplication() *Application {
+	return &Application{
+		ID: "test_app_47hfnths73j2se",
+		GatewayAAT: GatewayAAT{
+			Address:    "test_34715cae753e67c75fbb340442e7de8e",
+			PrivateKey: "test_d2ce53f115f4ecb2208e9188800a85cf",
+		},
+		GatewaySettings: GatewaySettings{
+			SecretKey:        "test_40f482d91a5ef2300ebb4e2308c",
+			SecretKeys:       []SecretKey{{Name: "default", Key: "test_40f482d91a5ef2300ebb4e2308c"}},
+			WhitelistOrigins: []string{"https://portal.test"},
+		},
+	}
+}
+
+func TestApplication_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name               string
+		value              func(app *Application) any
+		expectedPrivateKey string
+		expectedSecretKey  string
+	}{
+		{
+			name:               "Should redact secrets of a pointer",
+			value:              func(app *Application) any { return app },
+			expectedPrivateKey: RedactedSecret,
+			expectedSecretKey:  RedactedSecret,
+		},
+		{
+			name:               "Should redact secrets of a value",
+			value:              func(app *Application) any { return *app },
+			expectedPrivateKey: RedactedSecret,
+			expectedSecretKey:  RedactedSecret,
+		},
+		{
+			name:               "Should include secrets when explicitly requested",
+			value:              func(app *Application) any { return app.WithSecrets() },
+			expectedPrivateKey: "test_d2ce53f115f4ecb2208e9188800a85cf",
+			expectedSecretKey:  "test_40f482d91a5ef2300ebb4e2308c",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+			app := testApplication()
+
+			raw, err := json.Marshal(test.value(app))
+			c.NoError(err)
+
+			var decoded Application
+			c.NoError(json.Unmarshal(raw, &decoded))
+			c.Equal(test.expectedPrivateKey, decoded.GatewayAAT.PrivateKey)
+			c.Equal(test.expectedSecretKey, decoded.GatewaySettings.SecretKey)
+			c.Equal(test.expectedSecretKey, decoded.GatewaySettings.SecretKeys[0].Key)
+			c.Equal("default", decoded.GatewaySettings.SecretKeys[0].Name)
+			c.Equal(app.ID, decoded.ID)
+			c.Equal(app.GatewayAAT.Address, decoded.GatewayAAT.Address)
+			c.Equal(app.GatewaySettings.WhitelistOrigins, decoded.GatewaySettings.WhitelistOrigins)
+
+			// The original is never modified
+			c.Equal("test_d2ce53f115f4ecb2208e9188800a85cf", app.GatewayAAT.PrivateKey)
+		})
+	}
+}
+
+func TestApplication_Redaction(t *testing.T) {
+	c := require.New(t)
+	app := testApplication()
+
+	c.NotContains(fmt.Sprintf("%v", app), "test_d2ce53f115f4ecb2208e9188800a85cf")
+	c.NotContains(fmt.Sprintf("%+v", *app), "test_40f482d91a5ef2300ebb4e2308c")
+
+	redacted := app.Redacted()
+	c.Equal(RedactedSecret, redacted.GatewayAAT.PrivateKey)
+	c.Equal(RedactedSecret, redacted.GatewaySettings.SecretKey)
+	c.Equal(RedactedSecret, redacted.GatewaySettings.SecretKeys[0].Key)
+	c.Equal("test_d2ce53f115f4ecb2208e9188800a85cf", app.GatewayAAT.PrivateKey)
+	c.Equal("test_40f482d91a5ef2300ebb4e2308c", app.GatewaySettings.SecretKeys[0].Key)
+	c.NotContains(app.GatewaySettings.SecretKeys[0].String(), "test_40f482d91a5ef2300ebb4e2308c")
+
+	c.Empty(GatewayAAT{}.Redacted().PrivateKey)
+}
+
+func TestLoadBalancer_WithSecrets(t *testing.T) {
+	c := require.New(t)
+	lb := &LoadBalancer{ID: "test_lb_34gg4r43", Applications: []*Application{testApplication()}}
+
+	raw, err := json.Marshal(lb)
+	c.NoError(err)
+	c.NotContains(string(raw), "test_d2ce53f115f4ecb2208e9188800a85cf")
+
+	raw, err = json.Marshal(lb.WithSecrets())
+	c.NoError(err)
+
+	var decoded LoadBalancer
+	c.NoError(json.Unmarshal(raw, &decoded))
+	c.Equal(lb.ID, decoded.ID)
+	c.Equal("test_d2ce53f115f4ecb2208e9188800a85cf", decoded.Applications[0].GatewayAAT.PrivateKey)
+	c.Equal("test_40f482d91a5ef2300ebb4e2308c", decoded.Applications[0].GatewaySettings.SecretKey)
+}
+
+func TestNotification_WithSecrets(t *testing.T) {
+	app := testApplication()
+
+	tests := []struct {
+		name         string
+		notification *Notification
+		secret       string
+	}{
+		{
+			name:         "Application",
+			notification: &Notification{Table: TableApplications, Action: ActionInsert, Data: app},
+			secret:       "test_d2ce53f115f4ecb2208e9188800a85cf",
+		},
+		{
+			name:         "GatewayAAT",
+			notification: &Notification{Table: TableGatewayAAT, Action: ActionInsert, Data: &app.GatewayAAT},
+			secret:       "test_d2ce53f115f4ecb2208e9188800a85cf",
+		},
+		{
+			name:         "GatewaySettings",
+			notification: &Notification{Table: TableGatewaySettings, Action: ActionUpdate, Data: &app.GatewaySettings},
+			secret:       "test_40f482d91a5ef2300ebb4e2308c",
+		},
+		{
+			name:         "SecretKey",
+			notification: &Notification{Table: TableSecretKeys, Action: ActionInsert, Data: &app.GatewaySettings.SecretKeys[0]},
+			secret:       "test_40f482d91a5ef2300ebb4e2308c",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			redacted, err := json.Marshal(test.notification)
+			c.NoError(err)
+			c.NotContains(string(redacted), test.secret)
+
+			withSecrets, err := json.Marshal(test.notification.WithSecrets())
+			c.NoError(err)
+			c.Contains(string(withSecrets), test.secret)
+			c.Equal(test.notification.Data.Table(), test.notification.WithSecrets().Data.Table())
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+const (
+	// SecretKeyHashPrefix marks a stored secret key as a salted SHA-256 hash in the form sha256$<salt>$<digest>
+	SecretKeyHashPrefix = "sha256$"
+	secretKeySaltLength = 16
+)
+
+/*
+HashSecretKey returns a salted hash of the secret key to be stored instead of it.
+Secret keys are random and high entropy, so a single salted SHA-256 round is enough to protect them at rest.
+*/
+func HashSecretKey(key string) (string, error) {
+	salt := make([]byte, secretKeySaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	return SecretKeyHashPrefix + hex.EncodeToString(salt) + "$" + hex.EncodeToString(secretKeyDigest(salt, key)), nil
+}
+
+/* IsHashedSecretKey returns whether the stored secret key is a hash created by HashSecretKey */
+func IsHashedSecretKey(stored string) bool {
+	return strings.HasPrefix(stored, SecretKeyHashPrefix)
+}
+
+/*
+VerifySecretKey returns whether the key matches the stored secret key in constant time.
+Stored keys that have not been migrated to hashes yet are compared as clear text.
+*/
+func VerifySecretKey(stored, key string) bool {
+	if stored == "" || key == "" {
+		return false
+	}
+
+	if !IsHashedSecretKey(stored) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(key)) == 1
+	}
+
+	parts := strings.Split(strings.TrimPrefix(stored, SecretKeyHashPrefix), "$")
+	if len(parts) != 2 {
+		return false
+	}
+
+	salt, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+	digest, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(digest, secretKeyDigest(salt, key)) == 1
+}
+
+func secretKeyDigest(salt []byte, key string) []byte {
+	hash := sha256.New()
+	hash.Write(salt)
+	hash.Write([]byte(key))
+
+	return hash.Sum(nil)
+}
+
+/*
+VerifySecretKey returns whether the key matches the current SecretKey or one of the named keys valid at the given time.
+Every key is checked so the time taken does not reveal which one matched.
+*/
+func (s *GatewaySettings) VerifySecretKey(key string, now time.Time) bool {
+	matched := VerifySecretKey(s.SecretKey, key)
+
+	for _, secretKey := range s.ValidSecretKeys(now) {
+		if VerifySecretKey(secretKey.Key, key) {
+			matched = true
+		}
+	}
+
+	return matched
+}
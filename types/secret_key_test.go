@@ -0,0 +1,105 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashSecretKey(t *testing.T) {
+	c := require.New(t)
+
+	hashed, err := HashSecretKey("test_40f482d91a5ef2300ebb4e2308c")
+	c.NoError(err)
+	c.True(IsHashedSecretKey(hashed))
+	c.NotContains(hashed, "test_40f482d91a5ef2300ebb4e2308c")
+
+	hashedAgain, err := HashSecretKey("test_40f482d91a5ef2300ebb4e2308c")
+	c.NoError(err)
+	c.NotEqual(hashed, hashedAgain, "hashes should be salted")
+
+	c.False(IsHashedSecretKey("test_40f482d91a5ef2300ebb4e2308c"))
+}
+
+func TestVerifySecretKey(t *testing.T) {
+	hashed, err := HashSecretKey("test_40f482d91a5ef2300ebb4e2308c")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		stored   string
+		key      string
+		expected bool
+	}{
+		{
+			name:     "Should match the hashed key",
+			stored:   hashed,
+			key:      "test_40f482d91a5ef2300ebb4e2308c",
+			expected: true,
+		},
+		{
+			name:     "Should not match another key",
+			stored:   hashed,
+			key:      "test_90210ac4bdd3423e24877d1ff92",
+			expected: false,
+		},
+		{
+			name:     "Should match a key stored in clear text before the migration",
+			stored:   "test_40f482d91a5ef2300ebb4e2308c",
+			key:      "test_40f482d91a5ef2300ebb4e2308c",
+			expected: true,
+		},
+		{
+			name:     "Should not match the hash itself",
+			stored:   hashed,
+			key:      hashed,
+			expected: false,
+		},
+		{
+			name:     "Should not match an empty key",
+			stored:   "",
+			key:      "",
+			expected: false,
+		},
+		{
+			name:     "Should not match a malformed hash",
+			stored:   hashed[:len(hashed)-4] + "zzzz",
+			key:      "test_40f482d91a5ef2300ebb4e2308c",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, VerifySecretKey(test.stored, test.key))
+		})
+	}
+}
+
+func TestGatewaySettings_VerifySecretKey(t *testing.T) {
+	c := require.New(t)
+	now := time.Date(2022, 11, 11, 11, 11, 11, 0, time.UTC)
+
+	currentKey, err := HashSecretKey("test_current_key")
+	c.NoError(err)
+	previousKey, err := HashSecretKey("test_previous_key")
+	c.NoError(err)
+	revokedKey, err := HashSecretKey("test_revoked_key")
+	c.NoError(err)
+
+	settings := GatewaySettings{
+		SecretKey: currentKey,
+		SecretKeys: []SecretKey{
+			{Name: "previous", Key: previousKey, ExpiresAt: now.Add(time.Hour)},
+			{Name: "revoked", Key: revokedKey, RevokedAt: now},
+			{Name: "current", Key: currentKey},
+		},
+	}
+
+	c.True(settings.VerifySecretKey("test_current_key", now))
+	c.True(settings.VerifySecretKey("test_previous_key", now))
+	c.False(settings.VerifySecretKey("test_previous_key", now.Add(time.Hour)))
+	c.False(settings.VerifySecretKey("test_revoked_key", now))
+	c.False(settings.VerifySecretKey("test_unknown_key", now))
+}
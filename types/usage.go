@@ -0,0 +1,128 @@
+package types
+
+import "time"
+
+type (
+	/* RelayUsage is the number of relays an Application served on a UTC day */
+	RelayUsage struct {
+		ApplicationID string    `json:"applicationID"`
+		Date          time.Time `json:"date"`
+		Relays        int64     `json:"relays"`
+	}
+	/* RelayUsageFilter narrows ReadUsage results, an empty ApplicationID matches every Application and zero dates leave the range open */
+	RelayUsageFilter struct {
+		ApplicationID string    `json:"applicationID,omitempty"`
+		From          time.Time `json:"from,omitempty"`
+		To            time.Time `json:"to,omitempty"`
+	}
+	/* Quota compares the relays an Application used on a day with its daily limit */
+	Quota struct {
+		Limit     int64 `json:"limit"`
+		Used      int64 `json:"used"`
+		Remaining int64 `json:"remaining"`
+		Unlimited bool  `json:"unlimited"`
+	}
+	/* ApplicationUsage is an Application's relay usage on a day compared with its daily limit */
+	ApplicationUsage struct {
+		ApplicationID      string    `json:"applicationID"`
+		Date               time.Time `json:"date"`
+		FirstDateSurpassed time.Time `json:"firstDateSurpassed"`
+		Quota              Quota     `json:"quota"`
+	}
+	/* UsageThresholdEvent is sent once per day when an Application's usage crosses a threshold it opted in to */
+	UsageThresholdEvent struct {
+		ApplicationID string         `json:"applicationID"`
+		Name          string         `json:"name"`
+		UserID        string         `json:"userID"`
+		ContactEmail  string         `json:"contactEmail"`
+		Date          time.Time      `json:"date"`
+		Threshold     UsageThreshold `json:"threshold"`
+		Quota         Quota          `json:"quota"`
+	}
+
+	// UsageThreshold is a percentage of the daily limit an Application can be notified about
+	UsageThreshold int
+)
+
+const (
+	ThresholdQuarter       UsageThreshold = 25
+	ThresholdHalf          UsageThreshold = 50
+	ThresholdThreeQuarters UsageThreshold = 75
+	ThresholdFull          UsageThreshold = 100
+)
+
+// UsageThresholds lists every UsageThreshold in ascending order
+var UsageThresholds = []UsageThreshold{ThresholdQuarter, ThresholdHalf, ThresholdThreeQuarters, ThresholdFull}
+
+/* UsageDay returns the UTC day t falls on, which is the key relay usage is counted under */
+func UsageDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+/*
+Quota returns how many relays the Application has left for the day after using used relays.
+The limit is the Application's DailyLimit, so enterprise plans use their CustomLimit, and a limit of 0 is unlimited.
+*/
+func (a *Application) Quota(used int64) Quota {
+	return newQuota(a.DailyLimit(), used)
+}
+
+func newQuota(dailyLimit int, used int64) Quota {
+	limit := int64(dailyLimit)
+	if limit <= 0 {
+		return Quota{Used: used, Unlimited: true}
+	}
+
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return Quota{
+		Limit:     limit,
+		Used:      used,
+		Remaining: remaining,
+	}
+}
+
+/* IsExceeded returns whether more relays were used than the limit allows */
+func (q Quota) IsExceeded() bool {
+	return !q.Unlimited && q.Used > q.Limit
+}
+
+/* IsReached returns whether the used relays are at or above the threshold's share of the limit */
+func (q Quota) IsReached(threshold UsageThreshold) bool {
+	return !q.Unlimited && q.Used*100 >= q.Limit*int64(threshold)
+}
+
+/* NotifiesOn returns whether the settings opted in to notifications for the threshold */
+func (s NotificationSettings) NotifiesOn(threshold UsageThreshold) bool {
+	switch threshold {
+	case ThresholdQuarter:
+		return s.Quarter
+	case ThresholdHalf:
+		return s.Half
+	case ThresholdThreeQuarters:
+		return s.ThreeQuarters
+	case ThresholdFull:
+		return s.Full
+	default:
+		return false
+	}
+}
+
+/*
+ReachedThresholds returns the thresholds the settings opted in to that the quota has reached, in ascending order.
+An unlimited quota never reaches a threshold.
+*/
+func (s NotificationSettings) ReachedThresholds(quota Quota) []UsageThreshold {
+	var reached []UsageThreshold
+	for _, threshold := range UsageThresholds {
+		if s.NotifiesOn(threshold) && quota.IsReached(threshold) {
+			reached = append(reached, threshold)
+		}
+	}
+
+	return reached
+}
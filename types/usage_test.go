@@ -0,0 +1,110 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageDay(t *testing.T) {
+	c := require.New(t)
+
+	est := time.FixedZone("EST", -5*60*60)
+
+	c.Equal(time.Date(2022, 11, 11, 0, 0, 0, 0, time.UTC), UsageDay(time.Date(2022, 11, 11, 23, 59, 59, 0, time.UTC)))
+	c.Equal(time.Date(2022, 11, 12, 0, 0, 0, 0, time.UTC), UsageDay(time.Date(2022, 11, 11, 20, 0, 0, 0, est)))
+}
+
+func TestApplication_Quota(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         AppLimit
+		used          int64
+		expectedQuota Quota
+		exceeded      bool
+	}{
+		{
+			name:          "Should return the relays left on the pay plan",
+			limit:         AppLimit{PayPlan: PayPlan{Type: FreetierV0, Limit: 250_000}},
+			used:          100_000,
+			expectedQuota: Quota{Limit: 250_000, Used: 100_000, Remaining: 150_000},
+		},
+		{
+			name:          "Should not return a negative remaining quota",
+			limit:         AppLimit{PayPlan: PayPlan{Type: FreetierV0, Limit: 250_000}},
+			used:          300_000,
+			expectedQuota: Quota{Limit: 250_000, Used: 300_000, Remaining: 0},
+			exceeded:      true,
+		},
+		{
+			name:          "Should use the custom limit of an enterprise plan",
+			limit:         AppLimit{PayPlan: PayPlan{Type: Enterprise, Limit: 0}, CustomLimit: 2_000_000},
+			used:          500_000,
+			expectedQuota: Quota{Limit: 2_000_000, Used: 500_000, Remaining: 1_500_000},
+		},
+		{
+			name:          "Should ignore a custom limit outside an enterprise plan",
+			limit:         AppLimit{PayPlan: PayPlan{Type: FreetierV0, Limit: 250_000}, CustomLimit: 2_000_000},
+			used:          250_000,
+			expectedQuota: Quota{Limit: 250_000, Used: 250_000, Remaining: 0},
+		},
+		{
+			name:          "Should be unlimited without a daily limit",
+			limit:         AppLimit{PayPlan: PayPlan{Type: PayAsYouGoV0, Limit: 0}},
+			used:          10_000_000,
+			expectedQuota: Quota{Used: 10_000_000, Unlimited: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			app := &Application{Limit: test.limit}
+			quota := app.Quota(test.used)
+			c.Equal(test.expectedQuota, quota)
+			c.Equal(test.exceeded, quota.IsExceeded())
+		})
+	}
+}
+
+func TestNotificationSettings_ReachedThresholds(t *testing.T) {
+	allThresholds := NotificationSettings{Quarter: true, Half: true, ThreeQuarters: true, Full: true}
+
+	tests := []struct {
+		name               string
+		settings           NotificationSettings
+		quota              Quota
+		expectedThresholds []UsageThreshold
+	}{
+		{
+			name:     "Should not reach a threshold below a quarter of the limit",
+			settings: allThresholds,
+			quota:    Quota{Limit: 100, Used: 24},
+		},
+		{
+			name:               "Should reach every threshold up to the usage",
+			settings:           allThresholds,
+			quota:              Quota{Limit: 100, Used: 75},
+			expectedThresholds: []UsageThreshold{ThresholdQuarter, ThresholdHalf, ThresholdThreeQuarters},
+		},
+		{
+			name:               "Should only reach thresholds that were opted in to",
+			settings:           NotificationSettings{ThreeQuarters: true, Full: true},
+			quota:              Quota{Limit: 100, Used: 250},
+			expectedThresholds: []UsageThreshold{ThresholdThreeQuarters, ThresholdFull},
+		},
+		{
+			name:     "Should never reach a threshold of an unlimited quota",
+			settings: allThresholds,
+			quota:    Quota{Used: 1_000_000, Unlimited: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expectedThresholds, test.settings.ReachedThresholds(test.quota))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"errors"
+	"strings"
+)
+
+// Violation codes group violations by the kind of rule that was broken
+const (
+	ViolationInvalid    = "invalid"
+	ViolationRequired   = "required"
+	ViolationNotAllowed = "not_allowed"
+)
+
+var (
+	ErrMissingValue  = errors.New("value is required")
+	ErrNegativeValue = errors.New("value cannot be negative")
+)
+
+type (
+	// Violation is a single invalid field, Err holds the sentinel error describing it
+	Violation struct {
+		Field   string `json:"field"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Err     error  `json:"-"`
+	}
+
+	// ValidationError collects every Violation found while validating a value
+	ValidationError struct {
+		Violations []Violation `json:"violations"`
+	}
+)
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		if violation.Field == "" {
+			messages = append(messages, violation.Message)
+			continue
+		}
+		messages = append(messages, violation.Field+": "+violation.Message)
+	}
+
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Is makes errors.Is true for the sentinel error of every Violation
+func (e *ValidationError) Is(target error) bool {
+	for _, violation := range e.Violations {
+		if errors.Is(violation.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/* Add records a violation of the field described by a sentinel error */
+func (e *ValidationError) Add(field, code string, err error) {
+	e.Violations = append(e.Violations, Violation{
+		Field:   field,
+		Code:    code,
+		Message: err.Error(),
+		Err:     err,
+	})
+}
+
+/*
+Merge records the violations of a nested value with their fields prefixed by the field path of the value.
+Errors that are not a ValidationError are recorded as an invalid value of the field.
+*/
+func (e *ValidationError) Merge(field string, err error) {
+	if err == nil {
+		return
+	}
+
+	var nested *ValidationError
+	if !errors.As(err, &nested) {
+		e.Add(field, ViolationInvalid, err)
+		return
+	}
+
+	for _, violation := range nested.Violations {
+		violation.Field = joinFieldPath(field, violation.Field)
+		e.Violations = append(e.Violations, violation)
+	}
+}
+
+/* ErrorOrNil returns the ValidationError if it holds any violations and nil otherwise */
+func (e *ValidationError) ErrorOrNil() error {
+	if e == nil || len(e.Violations) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func joinFieldPath(parent, field string) string {
+	switch {
+	case parent == "":
+		return field
+	case field == "":
+		return parent
+	default:
+		return parent + "." + field
+	}
+}
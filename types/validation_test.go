@@ -0,0 +1,149 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplication_Validate(t *testing.T) {
+	tests := []struct {
+		name               string
+		app                *Application
+		expectedViolations []Violation
+	}{
+		{
+			name: "Should pass a valid application",
+			app: &Application{
+				Status: InService,
+				Limit:  AppLimit{PayPlan: PayPlan{Type: Enterprise}, CustomLimit: 2_000_000},
+			},
+		},
+		{
+			name: "Should collect every violation",
+			app: &Application{
+				Status:          AppStatus("INVALID_STATUS"),
+				Limit:           AppLimit{PayPlan: PayPlan{Type: PayPlanType("INVALID_PAY_PLAN")}, CustomLimit: 123},
+				GatewaySettings: GatewaySettings{SecretKey: "test_40f482d91a5ef2300ebb4e2308c"},
+			},
+			expectedViolations: []Violation{
+				{Field: "status", Code: ViolationInvalid, Message: ErrInvalidAppStatus.Error(), Err: ErrInvalidAppStatus},
+				{Field: "limit.payPlan.type", Code: ViolationInvalid, Message: ErrInvalidPayPlanType.Error(), Err: ErrInvalidPayPlanType},
+				{Field: "limit.customLimit", Code: ViolationNotAllowed, Message: ErrNotEnterprisePlan.Error(), Err: ErrNotEnterprisePlan},
+				{Field: "gatewaySettings.secretKey", Code: ViolationNotAllowed, Message: ErrSecretKeyIsGenerated.Error(), Err: ErrSecretKeyIsGenerated},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			err := test.app.Validate()
+			if test.expectedViolations == nil {
+				c.NoError(err)
+				return
+			}
+
+			var validationErr *ValidationError
+			c.ErrorAs(err, &validationErr)
+			c.Equal(test.expectedViolations, validationErr.Violations)
+
+			for _, violation := range test.expectedViolations {
+				c.ErrorIs(err, violation.Err)
+			}
+			c.False(errors.Is(err, ErrEnterprisePlanNeedsCustomLimit))
+		})
+	}
+}
+
+func TestUpdateApplication_Validate(t *testing.T) {
+	tests := []struct {
+		name           string
+		update         *UpdateApplication
+		expectedFields []string
+		expectedErrs   []error
+	}{
+		{
+			name:   "Should pass a valid update",
+			update: &UpdateApplication{Status: Ready, Limit: &AppLimit{PayPlan: PayPlan{Type: FreetierV0}}},
+		},
+		{
+			name:         "Should fail without an update",
+			expectedErrs: []error{ErrNoFieldsToUpdate},
+		},
+		{
+			name: "Should collect every violation",
+			update: &UpdateApplication{
+				Status: AppStatus("INVALID_STATUS"),
+				Limit:  &AppLimit{PayPlan: PayPlan{Type: Enterprise}},
+			},
+			expectedFields: []string{"status", "limit.customLimit"},
+			expectedErrs:   []error{ErrInvalidAppStatus, ErrEnterprisePlanNeedsCustomLimit},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := require.New(t)
+
+			err := test.update.Validate()
+			if test.expectedErrs == nil {
+				c.NoError(err)
+				return
+			}
+
+			for _, expectedErr := range test.expectedErrs {
+				c.ErrorIs(err, expectedErr)
+			}
+
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				var fields []string
+				for _, violation := range validationErr.Violations {
+					fields = append(fields, violation.Field)
+				}
+				c.Equal(test.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	c := require.New(t)
+
+	errs := &ValidationError{}
+	c.NoError(errs.ErrorOrNil())
+
+	errs.Add("status", ViolationInvalid, ErrInvalidAppStatus)
+	errs.Merge("limit", &ValidationError{Violations: []Violation{
+		{Field: "payPlan.type", Code: ViolationInvalid, Message: ErrInvalidPayPlanType.Error(), Err: ErrInvalidPayPlanType},
+	}})
+	errs.Merge("limit.customLimit", ErrNotEnterprisePlan)
+	errs.Merge("ignored", nil)
+
+	err := errs.ErrorOrNil()
+	c.Error(err)
+	c.Len(errs.Violations, 3)
+	c.Equal("limit.payPlan.type", errs.Violations[1].Field)
+	c.Equal("limit.customLimit", errs.Violations[2].Field)
+	c.Equal(ViolationInvalid, errs.Violations[2].Code)
+	c.Equal("validation failed: status: invalid app status; limit.payPlan.type: invalid pay plan type; "+
+		"limit.customLimit: custom limits may only be set on enterprise plans", err.Error())
+	c.ErrorIs(err, ErrNotEnterprisePlan)
+}
+
+func violationFields(err error) []string {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+
+	var fields []string
+	for _, violation := range validationErr.Violations {
+		fields = append(fields, violation.Field)
+	}
+
+	return fields
+}
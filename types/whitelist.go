@@ -0,0 +1,204 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrInvalidWhitelistOrigin    = errors.New("origins must be a scheme and a host such as https://example.com or https://*.example.com")
+	ErrInvalidWhitelistUserAgent = errors.New("user agents must not be blank or contain control characters")
+	ErrInvalidContractAddress    = errors.New("contract addresses must be 0x followed by 40 hex characters")
+	ErrInvalidContractChecksum   = errors.New("contract address checksum is invalid")
+	ErrInvalidMethodName         = errors.New("method names must start with a letter followed by letters, digits, underscores or dots")
+	ErrUnknownBlockchain         = errors.New("blockchain does not exist")
+)
+
+/*
+ValidateWhitelistOrigin returns an error unless the origin is a scheme and a host with an optional port,
+the host may start with a *. wildcard to allow every subdomain
+*/
+func ValidateWhitelistOrigin(origin string) error {
+	scheme, host, found := strings.Cut(origin, "://")
+	if !found || !isValidScheme(scheme) {
+		return ErrInvalidWhitelistOrigin
+	}
+
+	if hostname, port, hasPort := strings.Cut(host, ":"); hasPort {
+		portNumber, err := strconv.Atoi(port)
+		if err != nil || portNumber < 1 || portNumber > 65535 {
+			return ErrInvalidWhitelistOrigin
+		}
+		host = hostname
+	}
+
+	if !isValidHostname(strings.TrimPrefix(host, "*.")) {
+		return ErrInvalidWhitelistOrigin
+	}
+
+	return nil
+}
+
+/*
+ValidateContractAddress returns an error unless the address is an EVM address, 0x followed by 40 hex characters.
+Mixed case addresses carry an EIP-55 checksum which is verified, all lower or upper case addresses have none.
+*/
+func ValidateContractAddress(address string) error {
+	hexAddress := strings.TrimPrefix(address, "0x")
+	if len(address) != 42 || len(hexAddress) != 40 {
+		return ErrInvalidContractAddress
+	}
+	for _, char := range hexAddress {
+		if !isHexDigit(char) {
+			return ErrInvalidContractAddress
+		}
+	}
+
+	if hexAddress == strings.ToLower(hexAddress) || hexAddress == strings.ToUpper(hexAddress) {
+		return nil
+	}
+	if hexAddress != checksumAddress(hexAddress) {
+		return ErrInvalidContractChecksum
+	}
+
+	return nil
+}
+
+/* ValidateMethodName returns an error unless the method starts with a letter followed by letters, digits, underscores or dots */
+func ValidateMethodName(method string) error {
+	if method == "" {
+		return ErrInvalidMethodName
+	}
+
+	for i, char := range method {
+		isLetter := (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+		if i == 0 && !isLetter {
+			return ErrInvalidMethodName
+		}
+		if !isLetter && !(char >= '0' && char <= '9') && char != '_' && char != '.' {
+			return ErrInvalidMethodName
+		}
+	}
+
+	return nil
+}
+
+func validateWhitelists(origins, userAgents []string, contracts []WhitelistContract, methods []WhitelistMethod) error {
+	errs := &ValidationError{}
+
+	for i, origin := range origins {
+		if err := ValidateWhitelistOrigin(origin); err != nil {
+			errs.Add(fmt.Sprintf("whitelistOrigins[%d]", i), ViolationInvalid, err)
+		}
+	}
+
+	for i, userAgent := range userAgents {
+		if strings.TrimSpace(userAgent) == "" || strings.IndexFunc(userAgent, unicode.IsControl) != -1 {
+			errs.Add(fmt.Sprintf("whitelistUserAgents[%d]", i), ViolationInvalid, ErrInvalidWhitelistUserAgent)
+		}
+	}
+
+	for i, contract := range contracts {
+		if contract.BlockchainID == "" {
+			errs.Add(fmt.Sprintf("whitelistContracts[%d].blockchainID", i), ViolationRequired, ErrMissingValue)
+		}
+	}
+
+	for i, method := range methods {
+		if method.BlockchainID == "" {
+			errs.Add(fmt.Sprintf("whitelistMethods[%d].blockchainID", i), ViolationRequired, ErrMissingValue)
+		}
+		for j, name := range method.Methods {
+			if err := ValidateMethodName(name); err != nil {
+				errs.Add(fmt.Sprintf("whitelistMethods[%d].methods[%d]", i, j), ViolationInvalid, err)
+			}
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+/*
+ValidateWhitelistBlockchains returns a ValidationError for every whitelisted contract or method of a blockchain missing
+from blockchains, which maps IDs to the Blockchains. Contracts of EVM blockchains must be valid EVM addresses,
+other blockchains have their own address formats which are not checked.
+*/
+func ValidateWhitelistBlockchains(contracts []WhitelistContract, methods []WhitelistMethod, blockchains map[string]*Blockchain) error {
+	errs := &ValidationError{}
+
+	for i, contract := range contracts {
+		if contract.BlockchainID == "" {
+			continue
+		}
+
+		blockchain, ok := blockchains[contract.BlockchainID]
+		if !ok {
+			errs.Add(fmt.Sprintf("whitelistContracts[%d].blockchainID", i), ViolationInvalid, ErrUnknownBlockchain)
+			continue
+		}
+		if !blockchain.IsEVM() {
+			continue
+		}
+
+		for j, address := range contract.Contracts {
+			if err := ValidateContractAddress(address); err != nil {
+				errs.Add(fmt.Sprintf("whitelistContracts[%d].contracts[%d]", i, j), ViolationInvalid, err)
+			}
+		}
+	}
+	for i, method := range methods {
+		if _, ok := blockchains[method.BlockchainID]; method.BlockchainID != "" && !ok {
+			errs.Add(fmt.Sprintf("whitelistMethods[%d].blockchainID", i), ViolationInvalid, ErrUnknownBlockchain)
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+/* checksumAddress applies the EIP-55 checksum casing to a 40 character hex address */
+func checksumAddress(hexAddress string) string {
+	lowerAddress := strings.ToLower(hexAddress)
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write([]byte(lowerAddress))
+	hash := hasher.Sum(nil)
+
+	checksummed := []byte(lowerAddress)
+	for i, char := range checksummed {
+		hashNibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			hashNibble = hash[i/2] & 0x0f
+		}
+		if char >= 'a' && hashNibble >= 8 {
+			checksummed[i] = char - 'a' + 'A'
+		}
+	}
+
+	return string(checksummed)
+}
+
+func isValidScheme(scheme string) bool {
+	if scheme == "" {
+		return false
+	}
+
+	for i, char := range scheme {
+		isLetter := (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+		if i == 0 && !isLetter {
+			return false
+		}
+		if !isLetter && !(char >= '0' && char <= '9') && char != '+' && char != '-' && char != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isHexDigit(char rune) bool {
+	return (char >= '0' && char <= '9') || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')
+}
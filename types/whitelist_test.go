@@ -0,0 +1,130 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWhitelistOrigin(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		err    error
+	}{
+		{name: "Should pass a scheme and host", origin: "https://example.com"},
+		{name: "Should pass a wildcard subdomain", origin: "https://*.example.com"},
+		{name: "Should pass a host with a port", origin: "http://localhost:3000"},
+		{name: "Should pass a browser extension", origin: "chrome-extension://abcdefghijklmnop"},
+		{name: "Should fail without a scheme", origin: "example.com", err: ErrInvalidWhitelistOrigin},
+		{name: "Should fail without a host", origin: "https://", err: ErrInvalidWhitelistOrigin},
+		{name: "Should fail with a path", origin: "https://example.com/app", err: ErrInvalidWhitelistOrigin},
+		{name: "Should fail with a wildcard inside the host", origin: "https://app.*.example.com", err: ErrInvalidWhitelistOrigin},
+		{name: "Should fail with a bare wildcard", origin: "https://*", err: ErrInvalidWhitelistOrigin},
+		{name: "Should fail with an invalid port", origin: "https://example.com:99999", err: ErrInvalidWhitelistOrigin},
+		{name: "Should fail with an invalid scheme", origin: "1http://example.com", err: ErrInvalidWhitelistOrigin},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.err, ValidateWhitelistOrigin(test.origin))
+		})
+	}
+}
+
+func TestValidateContractAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		err     error
+	}{
+		{name: "Should pass a checksummed address", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{name: "Should pass another checksummed address", address: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"},
+		{name: "Should pass a lower case address", address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{name: "Should pass an upper case address", address: "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED"},
+		{name: "Should fail with a wrong checksum", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", err: ErrInvalidContractChecksum},
+		{name: "Should fail without the 0x prefix", address: "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00", err: ErrInvalidContractAddress},
+		{name: "Should fail if too short", address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea", err: ErrInvalidContractAddress},
+		{name: "Should fail with non hex characters", address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beazz", err: ErrInvalidContractAddress},
+		{name: "Should fail with surrounding spaces", address: " 0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", err: ErrInvalidContractAddress},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.err, ValidateContractAddress(test.address))
+		})
+	}
+}
+
+func TestValidateMethodName(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		err    error
+	}{
+		{name: "Should pass an EVM method", method: "eth_getBalance"},
+		{name: "Should pass a namespaced method", method: "EXPERIMENTAL_changes.v2"},
+		{name: "Should fail when empty", method: "", err: ErrInvalidMethodName},
+		{name: "Should fail when starting with a digit", method: "1eth_call", err: ErrInvalidMethodName},
+		{name: "Should fail with spaces", method: "eth_call ", err: ErrInvalidMethodName},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.err, ValidateMethodName(test.method))
+		})
+	}
+}
+
+func TestUpdateApplication_ValidateWhitelists(t *testing.T) {
+	c := require.New(t)
+
+	update := &UpdateApplication{
+		GatewaySettings: &UpdateGatewaySettings{
+			WhitelistOrigins:    []string{"https://example.com", "example.com"},
+			WhitelistUserAgents: []string{"Mozilla/5.0", " "},
+			WhitelistContracts: []WhitelistContract{
+				{BlockchainID: "0021", Contracts: []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0x123"}},
+			},
+			WhitelistMethods: []WhitelistMethod{
+				{Methods: []string{"eth_call"}},
+			},
+		},
+	}
+
+	err := update.Validate()
+	c.Equal([]string{
+		"gatewaySettings.whitelistOrigins[1]",
+		"gatewaySettings.whitelistUserAgents[1]",
+		"gatewaySettings.whitelistMethods[0].blockchainID",
+	}, violationFields(err))
+	c.ErrorIs(err, ErrInvalidWhitelistOrigin)
+	c.ErrorIs(err, ErrInvalidWhitelistUserAgent)
+	c.ErrorIs(err, ErrMissingValue)
+}
+
+func TestValidateWhitelistBlockchains(t *testing.T) {
+	c := require.New(t)
+
+	blockchains := map[string]*Blockchain{
+		"0001": {ID: "0001"},
+		"0021": {ID: "0021", ChainIDCheck: `{"method":"eth_chainId","id":1,"jsonrpc":"2.0"}`},
+	}
+	contracts := []WhitelistContract{{BlockchainID: "0021"}, {BlockchainID: "0040"}}
+	methods := []WhitelistMethod{{BlockchainID: "0001"}, {BlockchainID: "0050"}}
+
+	err := ValidateWhitelistBlockchains(contracts, methods, blockchains)
+	c.ErrorIs(err, ErrUnknownBlockchain)
+	c.Equal([]string{"whitelistContracts[1].blockchainID", "whitelistMethods[1].blockchainID"}, violationFields(err))
+
+	c.NoError(ValidateWhitelistBlockchains(contracts[:1], methods[:1], blockchains))
+
+	// Only the contracts of EVM blockchains are EVM addresses
+	contracts = []WhitelistContract{
+		{BlockchainID: "0021", Contracts: []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0x123"}},
+		{BlockchainID: "0001", Contracts: []string{"vipr1qyqszqgpqyqszqgpqyqszqgpqyqszqgp"}},
+	}
+	err = ValidateWhitelistBlockchains(contracts, nil, blockchains)
+	c.ErrorIs(err, ErrInvalidContractAddress)
+	c.Equal([]string{"whitelistContracts[0].contracts[1]"}, violationFields(err))
+}